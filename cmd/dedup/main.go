@@ -0,0 +1,58 @@
+// dedup 清理日线分表中重复的 (f_ts_code, f_trade_date) 并添加唯一索引
+//
+//	go run ./cmd/dedup -dry-run
+//	go run ./cmd/dedup
+//
+// 每组重复数据保留最新写入的一行，唯一索引添加后写入改为覆盖
+package main
+
+import (
+	"context"
+	"financia/public/db/connector"
+	"financia/public/db/dao"
+	"financia/public/db/model"
+	"flag"
+	"log"
+)
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "只统计重复行数，不删除")
+	flag.Parse()
+
+	ctx := context.Background()
+	tables := []string{model.StockData{}.TableName(), model.FundData{}.TableName()}
+
+	var total int64
+	for _, table := range tables {
+		for _, shard := range connector.ShardTables(table) {
+			if *dryRun {
+				count, err := dao.CountDailyDuplicates(ctx, shard)
+				if err != nil {
+					log.Fatalf("%s: count duplicates: %s", shard, err)
+				}
+				if count > 0 {
+					log.Printf("%s: %d duplicate rows", shard, count)
+				}
+				total += count
+				continue
+			}
+
+			deleted, err := dao.DeleteDailyDuplicates(ctx, shard)
+			if err != nil {
+				log.Fatalf("%s: delete duplicates: %s", shard, err)
+			}
+			added, err := dao.EnsureDailyUniqueIndex(ctx, shard)
+			if err != nil {
+				log.Fatalf("%s: add unique index: %s", shard, err)
+			}
+			log.Printf("%s: deleted %d duplicate rows, unique index added = %t", shard, deleted, added)
+			total += deleted
+		}
+	}
+
+	if *dryRun {
+		log.Printf("dry run: %d duplicate rows in total", total)
+		return
+	}
+	log.Printf("done: deleted %d duplicate rows in total", total)
+}
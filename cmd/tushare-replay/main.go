@@ -0,0 +1,29 @@
+// tushare-replay 启动离线 tushare 接口，回放录制目录中的请求
+//
+//	go run ./cmd/tushare-replay -dir ./fixtures -addr :8900
+//
+// 将配置中的 TuShare.Url 指向该地址即可脱离真实接口运行
+package main
+
+import (
+	"financia/server/tushare"
+	"flag"
+	"log"
+	"net/http"
+)
+
+func main() {
+	dir := flag.String("dir", "./fixtures", "录制文件目录")
+	addr := flag.String("addr", ":8900", "监听地址")
+	flag.Parse()
+
+	srv, err := tushare.NewReplayServer(*dir)
+	if err != nil {
+		log.Fatalf("load fixtures: %s", err)
+	}
+
+	log.Printf("tushare replay server listening on %s, fixtures = %s", *addr, *dir)
+	if err := http.ListenAndServe(*addr, srv); err != nil {
+		log.Fatal(err)
+	}
+}
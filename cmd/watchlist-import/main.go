@@ -0,0 +1,107 @@
+// watchlist-import 将 Redis 中的关注集合 stock_follow:<uid>、fund_follow:<uid> 导入自选列表
+//
+//	go run ./cmd/watchlist-import -dry-run
+//	go run ./cmd/watchlist-import
+//
+// 关注的股票与基金加入各用户的默认列表，已存在的条目跳过，可重复执行；
+// 关注时的价格未知，导入的条目加入价格为空。Redis 中的集合保留不删除
+package main
+
+import (
+	"context"
+	"financia/public"
+	"financia/public/db/connector"
+	"financia/public/db/dao"
+	"financia/public/db/model"
+	"flag"
+	"fmt"
+	"github.com/spf13/cast"
+	"log"
+	"strings"
+)
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "只统计待导入的条目，不写入")
+	flag.Parse()
+
+	ctx := context.Background()
+	var total int
+	for _, asset := range []string{public.AssetStock, public.AssetFund} {
+		pattern := public.RedisKeyStockFollow
+		if asset == public.AssetFund {
+			pattern = public.RedisKeyFundFollow
+		}
+		prefix := strings.TrimSuffix(pattern, "%d")
+
+		iter := connector.GetRedis().Scan(ctx, 0, prefix+"*", 100).Iterator()
+		for iter.Next(ctx) {
+			key := iter.Val()
+			userId := cast.ToInt64(strings.TrimPrefix(key, prefix))
+			if userId == 0 {
+				log.Printf("%s: skip, invalid user id", key)
+				continue
+			}
+			members, err := connector.GetRedis().SMembers(ctx, key).Result()
+			if err != nil {
+				log.Fatalf("%s: smembers: %s", key, err)
+			}
+
+			items, err := followItems(ctx, asset, cast.ToIntSlice(members))
+			if err != nil {
+				log.Fatalf("%s: load %s info: %s", key, asset, err)
+			}
+			total += len(items)
+			if *dryRun {
+				log.Printf("%s: %d items", key, len(items))
+				continue
+			}
+
+			watchlist, err := dao.GetDefaultWatchlist(ctx, userId)
+			if err != nil {
+				log.Fatalf("%s: default watchlist: %s", key, err)
+			}
+			for _, item := range items {
+				item.WatchlistId, item.UserId = watchlist.Id, userId
+				if err := dao.AddWatchlistItem(ctx, item); err != nil {
+					log.Fatalf("%s: add %s: %s", key, item.Code, err)
+				}
+			}
+			log.Printf("%s: imported %d items into watchlist %d", key, len(items), watchlist.Id)
+		}
+		if err := iter.Err(); err != nil {
+			log.Fatalf("scan %s*: %s", prefix, err)
+		}
+	}
+
+	if *dryRun {
+		log.Printf("dry run: %d items in total", total)
+		return
+	}
+	log.Printf("done: imported %d items in total", total)
+}
+
+// followItems 关注的 ID 对应的条目，信息表中已不存在的 ID 跳过
+func followItems(ctx context.Context, asset string, ids []int) ([]*model.WatchlistItem, error) {
+	var items []*model.WatchlistItem
+	switch asset {
+	case public.AssetStock:
+		list, err := dao.GetStockInfos(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range list {
+			items = append(items, &model.WatchlistItem{Asset: asset, RefId: v.Id, Code: v.TsCode, Name: v.Name})
+		}
+	case public.AssetFund:
+		list, err := dao.GetFundInfos(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range list {
+			items = append(items, &model.WatchlistItem{Asset: asset, RefId: int(v.Id), Code: v.TsCode, Name: v.Name})
+		}
+	default:
+		return nil, fmt.Errorf("unknown asset %q", asset)
+	}
+	return items, nil
+}
@@ -3,21 +3,26 @@ package config
 import (
 	"github.com/spf13/viper"
 	"log"
+	"time"
 )
 
 var Configs Config
 
 type Config struct {
-	MySQL   MySQLConfig
-	Redis   RedisConfig
-	Auth    AuthConfig
-	App     AppConfig
-	Alpha   AlphaConfig
-	Logger  LoggerConfig
-	Email   EmailConfig
-	TuShare TuShareConfig
-	Python  PythonConfig
-	Spark   SparkConfig
+	MySQL     MySQLConfig
+	Redis     RedisConfig
+	Auth      AuthConfig
+	App       AppConfig
+	Alpha     AlphaConfig
+	Logger    LoggerConfig
+	Email     EmailConfig
+	TuShare   TuShareConfig
+	Python    PythonConfig
+	Spark     SparkConfig
+	Ingest    IngestConfig
+	Predictor PredictorConfig
+	LLM       LLMConfig
+	Notify    NotifyConfig
 }
 
 type MySQLConfig struct {
@@ -63,24 +68,72 @@ type LoggerConfig struct {
 }
 
 type EmailConfig struct {
+	Provider string // smtp 或 memory，默认 smtp；memory 只保存在内存中，用于测试与本地调试
 	Server   string
 	Port     int
 	User     string
 	Password string
 }
 
+type NotifyConfig struct {
+	Channels       []string      // 提醒、摘要等消息的默认渠道，默认 email、inbox
+	WebhookURL     string        // 非空时启用 webhook 渠道
+	WebhookSecret  string        // 非空时对请求体签名
+	WebhookTimeout time.Duration // 默认 10s
+	BaseURL        string        // 邮件中链接的接口地址前缀，如 https://example.com/api/v1，为空时不带退订链接
+}
+
 type TuShareConfig struct {
-	Token string
+	Token      string
+	Url        string // 接口地址，默认 http://api.tushare.pro
+	Rate       int    // 每分钟请求数，按积分档位配置
+	Burst      int    // 令牌桶容量
+	MaxRetries int    // 网络错误重试次数
+	Timeout    int    // 单次请求超时（秒）
+	RecordDir  string // 录制目录，非空时保存请求与响应用于离线回放
+}
+
+type IngestConfig struct {
+	StartDate string // 首次拉取日线的起始日期 YYYY-MM-DD，默认回补最近 30 天
+}
+
+type PredictorConfig struct {
+	Stock      []string // 股票预测器回退顺序，默认 python、linear、naive
+	Fund       []string // 基金预测器回退顺序，默认同股票
+	Workers    int      // 预测任务队列的工作协程数，默认 4
+	FeatureSet string   // 发送给 python 服务的特征集，默认 basic，见 server/feature
 }
 
 type PythonConfig struct {
-	Url string
+	Url             string
+	Timeout         time.Duration // 单次预测的超时，默认 5s
+	BatchTimeout    time.Duration // PredictAll 的超时，默认 8s
+	BreakerFailures int           // 连续失败多少次后熔断，默认 5
+	BreakerCooldown time.Duration // 熔断后多久放行一次探测请求，默认 30s
 }
 
 type SparkConfig struct {
 	Password string
 }
 
+type LLMConfig struct {
+	Provider      string                  // openai 或 mock，默认 openai
+	BaseURL       string                  // OpenAI 兼容接口地址，默认讯飞星火
+	APIKey        string                  // 未配置时使用 Spark.Password
+	Model         string                  // 默认 4.0Ultra
+	Timeout       time.Duration           // 单次分析的超时，默认 2m
+	Prompts       map[string]PromptConfig // 按资产类型（stock、fund）覆盖提示词模板
+	DailyQuota    int                     // 每个用户每天触发生成的次数，回放缓存不计，默认 20
+	ContextTokens int                     // 参考资料（财务、股东、宏观等）的 token 预算，默认 1500
+	HistoryTokens int                     // 追问时随请求发送的对话历史的 token 预算，默认 2000
+}
+
+// PromptConfig 提示词模板，使用 text/template 语法，未填写的一项使用默认模板
+type PromptConfig struct {
+	System string
+	User   string
+}
+
 func init() {
 	viper.SetConfigName("config")
 	viper.SetConfigType("yaml")
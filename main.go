@@ -8,7 +8,10 @@ import (
 )
 
 func main() {
-	go python.NewGRPCClient()
+	python.NewGRPCClient()
 	go server.CronDailyWorker()
+	go server.BacktestWorker()
+	go server.PredictWorker()
+	go server.NotifyWorker()
 	router.HTTPRouter()
 }
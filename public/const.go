@@ -8,21 +8,48 @@ const (
 	RedisKeyFundVol         = "fund_vol"
 	RedisKeyGraphStock      = "graph_stock"
 
-	RedisKeyStockPredict = "stock_predict:%d"
-	RedisKeyStockToday   = "stock_today:%s"
-	RedisKeyStockFollow  = "stock_follow:%d"
+	RedisKeyStockPredict  = "stock_predict:%d"
+	RedisKeyStockForecast = "stock_forecast:%d" // 多步预测路径 JSON
+	RedisKeyStockToday    = "stock_today:%s"
+	RedisKeyStockFollow   = "stock_follow:%d"
 
-	RedisKeyFundPredict = "fund_predict:%d"
-	RedisKeyFundFollow  = "fund_follow:%d"
-	RedisKeyFundToday   = "fund_today:%s"
+	RedisKeyFundPredict  = "fund_predict:%d"
+	RedisKeyFundForecast = "fund_forecast:%d" // 多步预测路径 JSON
+	RedisKeyFundFollow   = "fund_follow:%d"
+	RedisKeyFundToday    = "fund_today:%s"
 
 	RedisKeyStockDataDoToday = "stock_data_do_today:%s"
 	RedisKeyFundDataDoToday  = "fund_data_do_today:%s"
 
 	RedisKeyTip = "tip:%d"
 
+	// 指数日线，参数为指数代码、开始日期
+	RedisKeyIndexDaily = "index_daily:%s:%s"
+
+	// 用户当日触发 AI 分析生成的次数，参数为用户 ID、日期
+	RedisKeyAiQuota = "ai_quota:%d:%s"
+
 	RedisKeyPredictList = "predict_list"
 	RedisKeyRankStock   = "rank_stock:%s:%d"
+
+	// 预测任务队列与去重标记，标记参数依次为资产类型、代码、输入窗口的最后交易日
+	RedisKeyPredictQueue = "predict_queue"
+	RedisKeyPredictJob   = "predict_job:%s:%s:%s"
+
+	// 聚合 K 线缓存，参数依次为代码、周期、复权方式、起止日期
+	RedisKeyStockBars = "stock_bars:%s:%s:%s:%s:%s"
+	RedisKeyFundBars  = "fund_bars:%s:%s:%s:%s"
+
+	// 股票换手率、指数成分，参数为代码、开始日期
+	RedisKeyTurnover    = "turnover:%s:%s"
+	RedisKeyIndexWeight = "index_weight:%s:%s"
+
+	// 上交所交易日历，参数为起止日期
+	RedisKeyTradeCal = "trade_cal:%s:%s"
+
+	// 预测特征、AI 分析上下文使用的 SHIBOR 与 CPI，缓存到当天结束
+	RedisKeyShiborDaily = "shibor_daily"
+	RedisKeyCnCpiDaily  = "cn_cpi_daily"
 )
 
 const (
@@ -39,10 +66,47 @@ const (
 	TuShareEconomicsShibor  = "shibor"
 	TuShareEconomicsCnGDP   = "cn_gdp"
 	TuShareEconomicsCnCPI   = "cn_cpi"
+	TuShareAdjFactor        = "adj_factor"
+	TuShareIndexDaily       = "index_daily"
+	TuShareDailyBasic       = "daily_basic"
+	TuShareIndexWeight      = "index_weight"
+)
+
+// 复权方式
+const (
+	AdjNone = "none" // 不复权
+	AdjQfq  = "qfq"  // 前复权
+	AdjHfq  = "hfq"  // 后复权
+)
+
+// 资产类型
+const (
+	AssetStock = "stock"
+	AssetFund  = "fund"
+	AssetFut   = "fut"
+)
+
+// 异步任务状态
+const (
+	JobStatusPending = "pending"
+	JobStatusRunning = "running"
+	JobStatusDone    = "done"
+	JobStatusFailed  = "failed"
+)
+
+// K 线周期
+const (
+	PeriodDay     = "D"
+	PeriodWeek    = "W"
+	PeriodMonth   = "M"
+	PeriodQuarter = "Q"
 )
 
+// 消息的发送状态
 const (
-	EmailTitle = "zandala-financial 验证码"
+	NotifyStatusPending = "pending"
+	NotifyStatusSent    = "sent"
+	NotifyStatusFailed  = "failed"
 )
 
 const (
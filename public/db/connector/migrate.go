@@ -0,0 +1,57 @@
+package connector
+
+import (
+	"financia/public/db/model"
+	"gorm.io/gorm"
+)
+
+// migrateModels 启动时自动建表的模型
+// 分表模型（StockData、FundData）由分表插件管理，不在此列
+var migrateModels = []interface{}{
+	&model.IngestCheckpoint{},
+	&model.StockAdjFactor{},
+	&model.StockPredict{},
+	&model.FundPredict{},
+	&model.PredictAccuracy{},
+	&model.BacktestJob{},
+	&model.AiAnalysis{},
+	&model.AiAnalysisView{},
+	&model.AiChat{},
+	&model.AiChatMessage{},
+	&model.Watchlist{},
+	&model.WatchlistItem{},
+	&model.Portfolio{},
+	&model.PortfolioTransaction{},
+	&model.AlertRule{},
+	&model.AlertFire{},
+	&model.Notification{},
+	&model.InboxMessage{},
+	&model.DigestSubscription{},
+}
+
+// staleIndexes 模型修改后不再使用的索引，AutoMigrate 不会删除
+var staleIndexes = []struct {
+	model interface{}
+	name  string
+}{
+	// 预测表改为按模型分别保存，旧的唯一索引不含模型
+	{&model.StockPredict{}, "uk_ts_code_trade_date_horizon"},
+	{&model.FundPredict{}, "uk_ts_code_trade_date_horizon"},
+}
+
+func migrate(db *gorm.DB) error {
+	if err := db.AutoMigrate(migrateModels...); err != nil {
+		return err
+	}
+
+	m := db.Migrator()
+	for _, v := range staleIndexes {
+		if !m.HasIndex(v.model, v.name) {
+			continue
+		}
+		if err := m.DropIndex(v.model, v.name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -12,6 +12,9 @@ import (
 	"time"
 )
 
+// NumberOfShards 日线数据分表数量
+const NumberOfShards = 20
+
 var db *gorm.DB
 
 func init() {
@@ -50,13 +53,18 @@ func init() {
 	// 注册分表插件
 	err = mysql.Use(sharding.Register(sharding.Config{
 		ShardingKey:         "f_ts_code",          // 分片键
-		NumberOfShards:      20,                   // 分片数量
+		NumberOfShards:      NumberOfShards,       // 分片数量
 		PrimaryKeyGenerator: sharding.PKSnowflake, // 使用 Snowflake 算法生成主键
 	}, model.StockData{}, model.FundData{})) // 注册需要分表的表
 	if err != nil {
 		panic(fmt.Sprintf("failed to register sharding plugin: %v", err))
 	}
 
+	if err := migrate(mysql); err != nil {
+		zap.S().Error("[init] [migrate] [err] = ", err.Error())
+		panic(err)
+	}
+
 	db = mysql
 }
 
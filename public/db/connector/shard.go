@@ -0,0 +1,36 @@
+package connector
+
+import (
+	"fmt"
+	"github.com/bwmarrin/snowflake"
+	"hash/crc32"
+	"strconv"
+)
+
+// idNode 直接写物理分表时生成主键，节点号与分表插件（按分表序号）错开
+var idNode, _ = snowflake.NewNode(1023)
+
+// ShardSuffix 计算分片键对应的分表后缀，与 sharding 插件的默认算法一致
+// 同一批次插入的数据必须落在同一张分表
+func ShardSuffix(key string) string {
+	id, err := strconv.Atoi(key)
+	if err != nil {
+		id = int(crc32.ChecksumIEEE([]byte(key)))
+	}
+	return fmt.Sprintf("_%02d", id%NumberOfShards)
+}
+
+// ShardTables 返回逻辑表对应的全部物理分表名
+// 用于不带分片键、需要遍历所有分表的查询
+func ShardTables(table string) []string {
+	tables := make([]string, 0, NumberOfShards)
+	for i := 0; i < NumberOfShards; i++ {
+		tables = append(tables, fmt.Sprintf("%s_%02d", table, i))
+	}
+	return tables
+}
+
+// GenerateID 生成分表主键，用于绕过分表插件直接写物理分表
+func GenerateID() int64 {
+	return idNode.Generate().Int64()
+}
@@ -0,0 +1,29 @@
+package dao
+
+import (
+	"context"
+	"financia/public/db/connector"
+	"financia/public/db/model"
+	"gorm.io/gorm/clause"
+)
+
+// GetStockAdjFactors 获取股票全部复权因子，按交易日升序
+func GetStockAdjFactors(ctx context.Context, tsCode string) ([]*model.StockAdjFactor, error) {
+	var factors []*model.StockAdjFactor
+	err := connector.GetDB().WithContext(ctx).
+		Where("f_ts_code = ?", tsCode).Order("f_trade_date").Find(&factors).Error
+
+	return factors, err
+}
+
+// UpsertStockAdjFactor 写入复权因子，同一代码同一交易日重复写入时覆盖
+func UpsertStockAdjFactor(ctx context.Context, data []*model.StockAdjFactor) error {
+	if len(data) == 0 {
+		return nil
+	}
+
+	return connector.GetDB().WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "f_ts_code"}, {Name: "f_trade_date"}},
+		DoUpdates: clause.AssignmentColumns([]string{"f_adj_factor"}),
+	}).CreateInBatches(data, 1000).Error
+}
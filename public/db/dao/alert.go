@@ -0,0 +1,109 @@
+package dao
+
+import (
+	"context"
+	"financia/public/db/connector"
+	"financia/public/db/model"
+	"gorm.io/gorm"
+	"time"
+)
+
+func CreateAlertRule(ctx context.Context, rule *model.AlertRule) error {
+	return connector.GetDB().WithContext(ctx).Create(rule).Error
+}
+
+func CountAlertRules(ctx context.Context, userId int64) (int64, error) {
+	var count int64
+	err := connector.GetDB().WithContext(ctx).Model(&model.AlertRule{}).
+		Where("f_user_id = ?", userId).Count(&count).Error
+
+	return count, err
+}
+
+func ListAlertRules(ctx context.Context, userId int64) ([]*model.AlertRule, error) {
+	var list []*model.AlertRule
+	err := connector.GetDB().WithContext(ctx).
+		Where("f_user_id = ?", userId).Order("f_id").Find(&list).Error
+
+	return list, err
+}
+
+// GetAlertRule 用户的规则，不属于该用户时返回 gorm.ErrRecordNotFound
+func GetAlertRule(ctx context.Context, userId, id int64) (*model.AlertRule, error) {
+	var rule model.AlertRule
+	err := connector.GetDB().WithContext(ctx).
+		Where("f_id = ? AND f_user_id = ?", id, userId).First(&rule).Error
+
+	return &rule, err
+}
+
+// GetEnabledAlertRules 全部启用的规则，按代码排列
+func GetEnabledAlertRules(ctx context.Context) ([]*model.AlertRule, error) {
+	var list []*model.AlertRule
+	err := connector.GetDB().WithContext(ctx).
+		Where("f_enabled = ?", true).Order("f_asset, f_ts_code, f_id").Find(&list).Error
+
+	return list, err
+}
+
+// UpdateAlertRule 修改规则的阈值、周期、冷却时间与开关
+func UpdateAlertRule(ctx context.Context, rule *model.AlertRule) error {
+	return connector.GetDB().WithContext(ctx).Model(rule).
+		Updates(map[string]any{
+			"f_threshold": rule.Threshold,
+			"f_period":    rule.Period,
+			"f_cooldown":  rule.Cooldown,
+			"f_enabled":   rule.Enabled,
+		}).Error
+}
+
+// DeleteAlertRule 删除用户的规则及其触发记录，返回是否删除
+func DeleteAlertRule(ctx context.Context, userId, id int64) (bool, error) {
+	var deleted bool
+	err := connector.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("f_id = ? AND f_user_id = ?", id, userId).Delete(&model.AlertRule{})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		deleted = true
+		return tx.Where("f_rule_id = ?", id).Delete(&model.AlertFire{}).Error
+	})
+
+	return deleted, err
+}
+
+// CreateAlertFire 保存触发记录，并以本次判断的开始时间 firedAt 与交易日更新规则的上次提醒
+func CreateAlertFire(ctx context.Context, fire *model.AlertFire, firedAt time.Time) error {
+	return connector.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(fire).Error; err != nil {
+			return err
+		}
+		return tx.Model(&model.AlertRule{}).Where("f_id = ?", fire.RuleId).
+			Updates(map[string]any{
+				"f_last_fired_at":   firedAt,
+				"f_last_trade_date": fire.TradeDate,
+			}).Error
+	})
+}
+
+// ListAlertFires 用户最近的触发记录，ruleId 为 0 时不限规则
+func ListAlertFires(ctx context.Context, userId, ruleId int64, limit int) ([]*model.AlertFire, error) {
+	db := connector.GetDB().WithContext(ctx).Where("f_user_id = ?", userId)
+	if ruleId > 0 {
+		db = db.Where("f_rule_id = ?", ruleId)
+	}
+
+	var list []*model.AlertFire
+	err := db.Order("f_id DESC").Limit(limit).Find(&list).Error
+
+	return list, err
+}
+
+// GetAlertFiresSince 用户在 since 之后触发的提醒
+func GetAlertFiresSince(ctx context.Context, userId int64, since time.Time) ([]*model.AlertFire, error) {
+	var list []*model.AlertFire
+	err := connector.GetDB().WithContext(ctx).
+		Where("f_user_id = ? AND f_created_at >= ?", userId, since).Order("f_id").Find(&list).Error
+
+	return list, err
+}
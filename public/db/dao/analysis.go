@@ -0,0 +1,78 @@
+package dao
+
+import (
+	"context"
+	"financia/public/db/connector"
+	"financia/public/db/model"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+// GetAiAnalysisByKey 按缓存键查找分析，不存在时返回 gorm.ErrRecordNotFound
+func GetAiAnalysisByKey(ctx context.Context, asset, tsCode string, tradeDate time.Time, promptVersion, modelName string) (*model.AiAnalysis, error) {
+	var analysis model.AiAnalysis
+	err := connector.GetDB().WithContext(ctx).
+		Where("f_asset = ? AND f_ts_code = ? AND f_trade_date = ? AND f_prompt_version = ? AND f_model = ?",
+			asset, tsCode, tradeDate, promptVersion, modelName).
+		First(&analysis).Error
+
+	return &analysis, err
+}
+
+// CreateAiAnalysis 保存分析，并发生成同一个键时保留先写入的一条并回填其 Id
+func CreateAiAnalysis(ctx context.Context, analysis *model.AiAnalysis) error {
+	db := connector.GetDB().WithContext(ctx)
+	result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(analysis)
+	if result.Error != nil || result.RowsAffected > 0 {
+		return result.Error
+	}
+
+	exists, err := GetAiAnalysisByKey(ctx, analysis.Asset, analysis.TsCode, analysis.TradeDate, analysis.PromptVersion, analysis.Model)
+	if err != nil {
+		return err
+	}
+	analysis.Id = exists.Id
+	return nil
+}
+
+func GetAiAnalysis(ctx context.Context, id int64) (*model.AiAnalysis, error) {
+	var analysis model.AiAnalysis
+	err := connector.GetDB().WithContext(ctx).Where("f_id = ?", id).First(&analysis).Error
+
+	return &analysis, err
+}
+
+// SaveAiAnalysisView 记录用户查看分析
+func SaveAiAnalysisView(ctx context.Context, userId, analysisId int64) error {
+	return connector.GetDB().WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "f_user_id"}, {Name: "f_analysis_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"f_viewed_at"}),
+	}).Create(&model.AiAnalysisView{
+		UserId:     userId,
+		AnalysisId: analysisId,
+		ViewedAt:   time.Now(),
+	}).Error
+}
+
+// HasAiAnalysisView 用户是否查看过某条分析
+func HasAiAnalysisView(ctx context.Context, userId, analysisId int64) (bool, error) {
+	var count int64
+	err := connector.GetDB().WithContext(ctx).Model(&model.AiAnalysisView{}).
+		Where("f_user_id = ? AND f_analysis_id = ?", userId, analysisId).Count(&count).Error
+
+	return count > 0, err
+}
+
+// ListAiAnalysisHistory 用户最近查看的分析，按查看时间倒序
+func ListAiAnalysisHistory(ctx context.Context, userId int64, limit int) ([]*model.AiAnalysisHistory, error) {
+	var list []*model.AiAnalysisHistory
+	err := connector.GetDB().WithContext(ctx).
+		Table("t_ai_analysis_view AS v").
+		Select("a.f_id, a.f_asset, a.f_ts_code, a.f_name, a.f_trade_date, a.f_model, v.f_viewed_at").
+		Joins("JOIN t_ai_analysis AS a ON a.f_id = v.f_analysis_id").
+		Where("v.f_user_id = ?", userId).
+		Order("v.f_viewed_at DESC").Limit(limit).
+		Scan(&list).Error
+
+	return list, err
+}
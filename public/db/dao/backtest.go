@@ -0,0 +1,54 @@
+package dao
+
+import (
+	"context"
+	"financia/public"
+	"financia/public/db/connector"
+	"financia/public/db/model"
+)
+
+func CreateBacktestJob(ctx context.Context, job *model.BacktestJob) error {
+	return connector.GetDB().WithContext(ctx).Create(job).Error
+}
+
+func GetBacktestJob(ctx context.Context, id int64) (*model.BacktestJob, error) {
+	var job model.BacktestJob
+	err := connector.GetDB().WithContext(ctx).Where("f_id = ?", id).First(&job).Error
+
+	return &job, err
+}
+
+// ListBacktestJobs 用户最近的回测任务，不含结果
+func ListBacktestJobs(ctx context.Context, userId int64, limit int) ([]*model.BacktestJob, error) {
+	var list []*model.BacktestJob
+	err := connector.GetDB().WithContext(ctx).Omit("f_result").
+		Where("f_user_id = ?", userId).Order("f_id DESC").Limit(limit).Find(&list).Error
+
+	return list, err
+}
+
+// GetUnfinishedBacktestJobs 排队中或运行中的任务，用于重启后恢复
+func GetUnfinishedBacktestJobs(ctx context.Context) ([]int64, error) {
+	var ids []int64
+	err := connector.GetDB().WithContext(ctx).Model(&model.BacktestJob{}).
+		Where("f_status IN ?", []string{public.JobStatusPending, public.JobStatusRunning}).
+		Order("f_id").Pluck("f_id", &ids).Error
+
+	return ids, err
+}
+
+// CountUnfinishedBacktestJobs 用户排队中或运行中的任务数
+func CountUnfinishedBacktestJobs(ctx context.Context, userId int64) (int64, error) {
+	var count int64
+	err := connector.GetDB().WithContext(ctx).Model(&model.BacktestJob{}).
+		Where("f_user_id = ? AND f_status IN ?", userId, []string{public.JobStatusPending, public.JobStatusRunning}).
+		Count(&count).Error
+
+	return count, err
+}
+
+// UpdateBacktestJob 更新任务状态，同时覆盖错误信息与结果
+func UpdateBacktestJob(ctx context.Context, id int64, status, errMsg, result string) error {
+	return connector.GetDB().WithContext(ctx).Model(&model.BacktestJob{}).Where("f_id = ?", id).
+		Updates(map[string]interface{}{"f_status": status, "f_error": errMsg, "f_result": result}).Error
+}
@@ -0,0 +1,68 @@
+package dao
+
+import (
+	"context"
+	"financia/public/db/connector"
+	"financia/public/db/model"
+	"gorm.io/gorm"
+	"time"
+)
+
+func CreateAiChat(ctx context.Context, chat *model.AiChat) error {
+	return connector.GetDB().WithContext(ctx).Create(chat).Error
+}
+
+// GetAiChat 用户的对话，不属于该用户时返回 gorm.ErrRecordNotFound
+func GetAiChat(ctx context.Context, userId, chatId int64) (*model.AiChat, error) {
+	var chat model.AiChat
+	err := connector.GetDB().WithContext(ctx).
+		Where("f_id = ? AND f_user_id = ?", chatId, userId).First(&chat).Error
+
+	return &chat, err
+}
+
+// ListAiChats 用户的对话，按最近更新倒序
+func ListAiChats(ctx context.Context, userId int64, limit int) ([]*model.AiChat, error) {
+	var list []*model.AiChat
+	err := connector.GetDB().WithContext(ctx).
+		Where("f_user_id = ?", userId).Order("f_updated_at DESC").Limit(limit).Find(&list).Error
+
+	return list, err
+}
+
+// GetAiChatMessages 对话的全部消息，按时间升序
+func GetAiChatMessages(ctx context.Context, chatId int64) ([]*model.AiChatMessage, error) {
+	var list []*model.AiChatMessage
+	err := connector.GetDB().WithContext(ctx).
+		Where("f_chat_id = ?", chatId).Order("f_id").Find(&list).Error
+
+	return list, err
+}
+
+// AppendAiChatMessages 保存一轮问答并更新对话时间
+func AppendAiChatMessages(ctx context.Context, chatId int64, list []*model.AiChatMessage) error {
+	return connector.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, v := range list {
+			v.ChatId = chatId
+		}
+		if err := tx.Create(list).Error; err != nil {
+			return err
+		}
+		return tx.Model(&model.AiChat{}).Where("f_id = ?", chatId).Update("f_updated_at", time.Now()).Error
+	})
+}
+
+// DeleteAiChat 删除用户的对话及其消息，返回是否删除
+func DeleteAiChat(ctx context.Context, userId, chatId int64) (bool, error) {
+	var deleted bool
+	err := connector.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("f_id = ? AND f_user_id = ?", chatId, userId).Delete(&model.AiChat{})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		deleted = true
+		return tx.Where("f_chat_id = ?", chatId).Delete(&model.AiChatMessage{}).Error
+	})
+
+	return deleted, err
+}
@@ -0,0 +1,58 @@
+package dao
+
+import (
+	"context"
+	"financia/public/db/connector"
+	"fmt"
+	"gorm.io/gorm/clause"
+)
+
+// dailyUpdateColumns 日线数据 (f_ts_code, f_trade_date) 冲突时覆盖的列
+var dailyUpdateColumns = []string{
+	"f_open", "f_high", "f_low", "f_close", "f_pre_close", "f_change", "f_pct_chg", "f_vol", "f_amount",
+}
+
+// upsertDaily 以 (f_ts_code, f_trade_date) 为唯一键写入一张物理分表
+// 分表插件无法解析 ON DUPLICATE KEY UPDATE ... VALUES()，会原样下发到逻辑表，
+// 因此这里直接指定物理分表，主键由调用方生成
+func upsertDaily(ctx context.Context, shard string, rows interface{}) error {
+	return connector.GetDB().WithContext(ctx).Table(shard).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "f_ts_code"}, {Name: "f_trade_date"}},
+		DoUpdates: clause.AssignmentColumns(dailyUpdateColumns),
+	}).CreateInBatches(rows, 1000).Error
+}
+
+// dailyUniqueIndex 日线分表 (f_ts_code, f_trade_date) 唯一索引名，与模型标签一致
+const dailyUniqueIndex = "uk_ts_code_trade_date"
+
+// CountDailyDuplicates 统计一张物理分表中重复的 (f_ts_code, f_trade_date) 多出的行数
+func CountDailyDuplicates(ctx context.Context, shard string) (int64, error) {
+	var count int64
+	err := connector.GetDB().WithContext(ctx).Raw(fmt.Sprintf(
+		"SELECT COALESCE(SUM(c - 1), 0) FROM (SELECT count(*) AS c FROM %s GROUP BY f_ts_code, f_trade_date HAVING c > 1) t",
+		shard)).Scan(&count).Error
+	return count, err
+}
+
+// DeleteDailyDuplicates 删除一张物理分表中的重复日线，每组保留主键最大（最新写入）的一行
+func DeleteDailyDuplicates(ctx context.Context, shard string) (int64, error) {
+	res := connector.GetDB().WithContext(ctx).Exec(fmt.Sprintf(
+		"DELETE a FROM %[1]s a JOIN %[1]s b ON a.f_ts_code = b.f_ts_code AND a.f_trade_date = b.f_trade_date AND a.id < b.id",
+		shard))
+	return res.RowsAffected, res.Error
+}
+
+// EnsureDailyUniqueIndex 为物理分表添加 (f_ts_code, f_trade_date) 唯一索引，已存在时跳过
+func EnsureDailyUniqueIndex(ctx context.Context, shard string) (bool, error) {
+	db := connector.GetDB().WithContext(ctx)
+
+	var count int64
+	err := db.Raw("SELECT count(*) FROM information_schema.statistics WHERE table_schema = DATABASE() AND table_name = ? AND index_name = ?",
+		shard, dailyUniqueIndex).Scan(&count).Error
+	if err != nil || count > 0 {
+		return false, err
+	}
+
+	err = db.Exec(fmt.Sprintf("ALTER TABLE %s ADD UNIQUE INDEX %s (f_ts_code, f_trade_date)", shard, dailyUniqueIndex)).Error
+	return err == nil, err
+}
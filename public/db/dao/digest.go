@@ -0,0 +1,69 @@
+package dao
+
+import (
+	"context"
+	"financia/public/db/connector"
+	"financia/public/db/model"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+// GetDigestSubscription 用户的订阅，未订阅过时返回 gorm.ErrRecordNotFound
+func GetDigestSubscription(ctx context.Context, userId int64) (*model.DigestSubscription, error) {
+	var sub model.DigestSubscription
+	err := connector.GetDB().WithContext(ctx).Where("f_user_id = ?", userId).First(&sub).Error
+
+	return &sub, err
+}
+
+// SaveDigestSubscription 开启或关闭订阅并设置发送时间，已有订阅时保留原来的令牌
+func SaveDigestSubscription(ctx context.Context, sub *model.DigestSubscription) error {
+	return connector.GetDB().WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "f_user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"f_enabled", "f_send_hour", "f_updated_at"}),
+	}).Create(sub).Error
+}
+
+// DisableDigestSubscription 关闭用户的订阅
+func DisableDigestSubscription(ctx context.Context, userId int64) error {
+	return connector.GetDB().WithContext(ctx).Model(&model.DigestSubscription{}).
+		Where("f_user_id = ?", userId).Update("f_enabled", false).Error
+}
+
+// GetDigestSubscriptionByToken 按退订令牌查找订阅，令牌不存在时返回 gorm.ErrRecordNotFound
+func GetDigestSubscriptionByToken(ctx context.Context, token string) (*model.DigestSubscription, error) {
+	var sub model.DigestSubscription
+	err := connector.GetDB().WithContext(ctx).Where("f_token = ?", token).First(&sub).Error
+
+	return &sub, err
+}
+
+// UnsubscribeDigest 按退订令牌关闭订阅，令牌不存在时返回 gorm.ErrRecordNotFound
+func UnsubscribeDigest(ctx context.Context, token string) error {
+	db := connector.GetDB().WithContext(ctx)
+	var sub model.DigestSubscription
+	if err := db.Where("f_token = ?", token).First(&sub).Error; err != nil {
+		return err
+	}
+	return db.Model(&sub).Update("f_enabled", false).Error
+}
+
+// GetDueDigestSubscriptions 在该整点及之前发送、还没有发送 tradeDate 数据的已开启订阅
+func GetDueDigestSubscriptions(ctx context.Context, hour int, tradeDate time.Time) ([]*model.DigestSubscription, error) {
+	var list []*model.DigestSubscription
+	err := connector.GetDB().WithContext(ctx).
+		Where("f_enabled = ? AND f_send_hour <= ?", true, hour).
+		Where("f_last_trade_date IS NULL OR f_last_trade_date < ?", tradeDate.Format(time.DateOnly)).
+		Order("f_id").Find(&list).Error
+
+	return list, err
+}
+
+// SetDigestSent 记录本次发送的时间与数据的交易日
+func SetDigestSent(ctx context.Context, id int64, tradeDate time.Time) error {
+	return connector.GetDB().WithContext(ctx).Model(&model.DigestSubscription{Id: id}).
+		Updates(map[string]any{
+			"f_last_trade_date": tradeDate,
+			"f_last_sent_at":    time.Now(),
+		}).Error
+}
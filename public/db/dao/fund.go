@@ -93,21 +93,48 @@ func CheckFundData(ctx context.Context, tsCode string) (bool, error) {
 	return count > 0, err
 }
 
-// InsertFundData 插入基金数据
-func InsertFundData(ctx context.Context, data []*model.FundData) error {
-	// 分批插入
-	for i := 0; i < len(data); i += 1000 {
-		end := i + 1000
-		if end > len(data) {
-			end = len(data)
+// UpsertFundData 写入基金日线，同一代码同一交易日重复写入时覆盖旧数据
+func UpsertFundData(ctx context.Context, data []*model.FundData) error {
+	// 按分表分组，直接写物理分表
+	table := model.FundData{}.TableName()
+	groups := make(map[string][]*model.FundData)
+	for _, v := range data {
+		if v.Id == 0 {
+			v.Id = int(connector.GenerateID())
 		}
-		if err := connector.GetDB().WithContext(ctx).Create(data[i:end]).Error; err != nil {
+		shard := table + connector.ShardSuffix(v.TsCode)
+		groups[shard] = append(groups[shard], v)
+	}
+
+	for shard, group := range groups {
+		if err := upsertDaily(ctx, shard, group); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
+// GetFundDataCodesByDate 获取某个交易日已入库的基金代码，遍历所有分表
+func GetFundDataCodesByDate(ctx context.Context, tradeDate time.Time) (map[string]struct{}, error) {
+	return dataCodesByDate(ctx, model.FundData{}.TableName(), tradeDate)
+}
+
+// GetFundCodes 获取所有基金代码
+func GetFundCodes(ctx context.Context) (map[string]struct{}, error) {
+	var codes []string
+	err := connector.GetDB().WithContext(ctx).Model(&model.FundInfo{}).
+		Pluck("f_ts_code", &codes).Error
+	if err != nil {
+		return nil, err
+	}
+
+	set := make(map[string]struct{}, len(codes))
+	for _, v := range codes {
+		set[v] = struct{}{}
+	}
+	return set, nil
+}
+
 func UpdateFund(ctx context.Context, fund *model.FundInfo) error {
 	return connector.GetDB().WithContext(ctx).Model(&model.FundInfo{}).
 		Where("id = ?", fund.Id).Updates(fund).Error
@@ -124,9 +151,14 @@ func GetFundData(ctx context.Context, tsCode, start, end string) ([]*model.FundD
 }
 
 func GetFundDataLimit30(ctx context.Context, tsCode string) ([]*model.FundData, error) {
+	return GetFundDataLimit(ctx, tsCode, 31)
+}
+
+// GetFundDataLimit 最近 limit 个交易日的数据，按日期倒序，同时缓存最新收盘价
+func GetFundDataLimit(ctx context.Context, tsCode string, limit int) ([]*model.FundData, error) {
 	fundData := make([]*model.FundData, 0)
 	err := connector.GetDB().WithContext(ctx).
-		Raw("SELECT * FROM t_fund_data WHERE f_ts_code = ? order by f_trade_date desc limit 31", tsCode).
+		Raw("SELECT * FROM t_fund_data WHERE f_ts_code = ? order by f_trade_date desc limit ?", tsCode, limit).
 		Scan(&fundData).Error
 
 	if len(fundData) == 0 {
@@ -0,0 +1,53 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"financia/public/db/connector"
+	"financia/public/db/model"
+	"fmt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+// GetIngestCheckpoint 获取表的拉取进度，没有记录时返回零值
+func GetIngestCheckpoint(ctx context.Context, table string) (time.Time, error) {
+	var checkpoint model.IngestCheckpoint
+	err := connector.GetDB().WithContext(ctx).
+		Where("f_table = ?", table).First(&checkpoint).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return time.Time{}, nil
+	}
+
+	return checkpoint.LastDate, err
+}
+
+// SetIngestCheckpoint 更新表的拉取进度
+func SetIngestCheckpoint(ctx context.Context, table string, lastDate time.Time) error {
+	return connector.GetDB().WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "f_table"}},
+		DoUpdates: clause.AssignmentColumns([]string{"f_last_date", "f_updated_at"}),
+	}).Create(&model.IngestCheckpoint{
+		Table:    table,
+		LastDate: lastDate,
+	}).Error
+}
+
+// dataCodesByDate 遍历所有分表，查询某个交易日已存在的代码
+func dataCodesByDate(ctx context.Context, table string, tradeDate time.Time) (map[string]struct{}, error) {
+	set := make(map[string]struct{})
+	for _, shard := range connector.ShardTables(table) {
+		var codes []string
+		err := connector.GetDB().WithContext(ctx).
+			Raw(fmt.Sprintf("SELECT f_ts_code FROM %s WHERE f_trade_date = ?", shard), tradeDate.Format(time.DateOnly)).
+			Scan(&codes).Error
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range codes {
+			set[v] = struct{}{}
+		}
+	}
+	return set, nil
+}
@@ -0,0 +1,66 @@
+package dao
+
+import (
+	"context"
+	"financia/public"
+	"financia/public/db/connector"
+	"financia/public/db/model"
+	"time"
+)
+
+func CreateNotifications(ctx context.Context, list []*model.Notification) error {
+	return connector.GetDB().WithContext(ctx).Create(list).Error
+}
+
+// GetDueNotifications 到了发送时间的待发送记录，按下次发送时间升序
+func GetDueNotifications(ctx context.Context, now time.Time, limit int) ([]*model.Notification, error) {
+	var list []*model.Notification
+	err := connector.GetDB().WithContext(ctx).
+		Where("f_status = ? AND f_next_attempt_at <= ?", public.NotifyStatusPending, now).
+		Order("f_next_attempt_at, f_id").Limit(limit).Find(&list).Error
+
+	return list, err
+}
+
+// UpdateNotification 保存一次发送的结果
+func UpdateNotification(ctx context.Context, n *model.Notification) error {
+	return connector.GetDB().WithContext(ctx).Model(n).
+		Updates(map[string]any{
+			"f_status":          n.Status,
+			"f_attempts":        n.Attempts,
+			"f_last_error":      n.LastError,
+			"f_next_attempt_at": n.NextAttemptAt,
+			"f_sent_at":         n.SentAt,
+		}).Error
+}
+
+func CreateInboxMessage(ctx context.Context, message *model.InboxMessage) error {
+	return connector.GetDB().WithContext(ctx).Create(message).Error
+}
+
+// ListInboxMessages 用户最近的站内信，按时间倒序
+func ListInboxMessages(ctx context.Context, userId int64, limit int) ([]*model.InboxMessage, error) {
+	var list []*model.InboxMessage
+	err := connector.GetDB().WithContext(ctx).
+		Where("f_user_id = ?", userId).Order("f_id DESC").Limit(limit).Find(&list).Error
+
+	return list, err
+}
+
+func CountUnreadInboxMessages(ctx context.Context, userId int64) (int64, error) {
+	var count int64
+	err := connector.GetDB().WithContext(ctx).Model(&model.InboxMessage{}).
+		Where("f_user_id = ? AND f_read = ?", userId, false).Count(&count).Error
+
+	return count, err
+}
+
+// ReadInboxMessages 将用户的站内信标记为已读，ids 为空时标记全部
+func ReadInboxMessages(ctx context.Context, userId int64, ids []int64) error {
+	db := connector.GetDB().WithContext(ctx).Model(&model.InboxMessage{}).
+		Where("f_user_id = ? AND f_read = ?", userId, false)
+	if len(ids) > 0 {
+		db = db.Where("f_id IN ?", ids)
+	}
+	return db.Update("f_read", true).Error
+}
@@ -0,0 +1,68 @@
+package dao
+
+import (
+	"context"
+	"financia/public/db/connector"
+	"financia/public/db/model"
+	"gorm.io/gorm"
+)
+
+func CreatePortfolio(ctx context.Context, portfolio *model.Portfolio) error {
+	return connector.GetDB().WithContext(ctx).Create(portfolio).Error
+}
+
+// GetPortfolio 用户的组合，不属于该用户时返回 gorm.ErrRecordNotFound
+func GetPortfolio(ctx context.Context, userId, id int64) (*model.Portfolio, error) {
+	var portfolio model.Portfolio
+	err := connector.GetDB().WithContext(ctx).
+		Where("f_id = ? AND f_user_id = ?", id, userId).First(&portfolio).Error
+
+	return &portfolio, err
+}
+
+func ListPortfolios(ctx context.Context, userId int64) ([]*model.Portfolio, error) {
+	var list []*model.Portfolio
+	err := connector.GetDB().WithContext(ctx).
+		Where("f_user_id = ?", userId).Order("f_id").Find(&list).Error
+
+	return list, err
+}
+
+// DeletePortfolio 删除用户的组合及其交易，返回是否删除
+func DeletePortfolio(ctx context.Context, userId, id int64) (bool, error) {
+	var deleted bool
+	err := connector.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("f_id = ? AND f_user_id = ?", id, userId).Delete(&model.Portfolio{})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		deleted = true
+		return tx.Where("f_portfolio_id = ?", id).Delete(&model.PortfolioTransaction{}).Error
+	})
+
+	return deleted, err
+}
+
+// GetPortfolioTransactions 组合的全部交易，按交易日期与录入顺序升序
+func GetPortfolioTransactions(ctx context.Context, portfolioId int64) ([]*model.PortfolioTransaction, error) {
+	var list []*model.PortfolioTransaction
+	err := connector.GetDB().WithContext(ctx).
+		Where("f_portfolio_id = ?", portfolioId).Order("f_trade_date, f_id").Find(&list).Error
+
+	return list, err
+}
+
+func CreatePortfolioTransaction(ctx context.Context, transaction *model.PortfolioTransaction) error {
+	return connector.GetDB().WithContext(ctx).Create(transaction).Error
+}
+
+func GetPortfolioTransaction(ctx context.Context, id int64) (*model.PortfolioTransaction, error) {
+	var transaction model.PortfolioTransaction
+	err := connector.GetDB().WithContext(ctx).Where("f_id = ?", id).First(&transaction).Error
+
+	return &transaction, err
+}
+
+func DeletePortfolioTransaction(ctx context.Context, id int64) error {
+	return connector.GetDB().WithContext(ctx).Where("f_id = ?", id).Delete(&model.PortfolioTransaction{}).Error
+}
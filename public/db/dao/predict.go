@@ -0,0 +1,154 @@
+package dao
+
+import (
+	"context"
+	"financia/public/db/connector"
+	"financia/public/db/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+// predictConflict 同一代码、同一窗口、同一模型与步数的预测重复写入时覆盖
+var predictConflict = clause.OnConflict{
+	Columns: []clause.Column{{Name: "f_ts_code"}, {Name: "f_trade_date"}, {Name: "f_model"}, {Name: "f_horizon"}},
+	DoUpdates: clause.AssignmentColumns([]string{
+		"f_model_version", "f_last_close", "f_predict", "f_lower", "f_upper", "f_created_at",
+	}),
+}
+
+// UpsertStockPredict 写入股票预测
+func UpsertStockPredict(ctx context.Context, data []*model.StockPredict) error {
+	if len(data) == 0 {
+		return nil
+	}
+	return connector.GetDB().WithContext(ctx).Clauses(predictConflict).Create(data).Error
+}
+
+// UpsertFundPredict 写入基金预测
+func UpsertFundPredict(ctx context.Context, data []*model.FundPredict) error {
+	if len(data) == 0 {
+		return nil
+	}
+	return connector.GetDB().WithContext(ctx).Clauses(predictConflict).Create(data).Error
+}
+
+// GetPendingStockPredicts since 之后尚未回填实际值的股票预测
+func GetPendingStockPredicts(ctx context.Context, since time.Time) ([]*model.StockPredict, error) {
+	var list []*model.StockPredict
+	err := connector.GetDB().WithContext(ctx).
+		Where("f_actual IS NULL AND f_trade_date >= ?", since).Find(&list).Error
+
+	return list, err
+}
+
+// GetPendingFundPredicts since 之后尚未回填实际值的基金预测
+func GetPendingFundPredicts(ctx context.Context, since time.Time) ([]*model.FundPredict, error) {
+	var list []*model.FundPredict
+	err := connector.GetDB().WithContext(ctx).
+		Where("f_actual IS NULL AND f_trade_date >= ?", since).Find(&list).Error
+
+	return list, err
+}
+
+// SetStockPredictActual 回填股票预测的实际值与目标交易日
+func SetStockPredictActual(ctx context.Context, list []*model.StockPredict) error {
+	return connector.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, v := range list {
+			err := tx.Model(&model.StockPredict{}).Where("f_id = ?", v.Id).
+				Updates(map[string]interface{}{"f_actual": v.Actual, "f_target_date": v.TargetDate}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// SetFundPredictActual 回填基金预测的实际值与目标交易日
+func SetFundPredictActual(ctx context.Context, list []*model.FundPredict) error {
+	return connector.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, v := range list {
+			err := tx.Model(&model.FundPredict{}).Where("id = ?", v.Id).
+				Updates(map[string]interface{}{"f_actual": v.Actual, "f_target_date": v.TargetDate}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetEvaluatedStockPredicts since 之后已回填实际值的股票预测
+func GetEvaluatedStockPredicts(ctx context.Context, since time.Time) ([]*model.StockPredict, error) {
+	var list []*model.StockPredict
+	err := connector.GetDB().WithContext(ctx).
+		Where("f_actual IS NOT NULL AND f_trade_date >= ?", since).Find(&list).Error
+
+	return list, err
+}
+
+// GetEvaluatedFundPredicts since 之后已回填实际值的基金预测
+func GetEvaluatedFundPredicts(ctx context.Context, since time.Time) ([]*model.FundPredict, error) {
+	var list []*model.FundPredict
+	err := connector.GetDB().WithContext(ctx).
+		Where("f_actual IS NOT NULL AND f_trade_date >= ?", since).Find(&list).Error
+
+	return list, err
+}
+
+// GetRecentStockPredicts 某只股票最近 limit 条已实现的预测，按窗口日期倒序
+func GetRecentStockPredicts(ctx context.Context, tsCode string, horizon, limit int) ([]*model.StockPredict, error) {
+	var list []*model.StockPredict
+	err := connector.GetDB().WithContext(ctx).
+		Where("f_ts_code = ? AND f_horizon = ? AND f_actual IS NOT NULL", tsCode, horizon).
+		Order("f_trade_date DESC").Limit(limit).Find(&list).Error
+
+	return list, err
+}
+
+// UpsertPredictAccuracy 写入准确率统计
+func UpsertPredictAccuracy(ctx context.Context, data []*model.PredictAccuracy) error {
+	if len(data) == 0 {
+		return nil
+	}
+
+	return connector.GetDB().WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "f_asset"}, {Name: "f_ts_code"}, {Name: "f_model"}, {Name: "f_horizon"}},
+		DoUpdates: clause.AssignmentColumns([]string{"f_samples", "f_hit_rate", "f_mae", "f_mape", "f_updated_at"}),
+	}).CreateInBatches(data, 1000).Error
+}
+
+// GetPredictAccuracy 某个代码的准确率，tsCode 为空时返回各模型的汇总
+func GetPredictAccuracy(ctx context.Context, asset, tsCode string) ([]*model.PredictAccuracy, error) {
+	var list []*model.PredictAccuracy
+	err := connector.GetDB().WithContext(ctx).
+		Where("f_asset = ? AND f_ts_code = ?", asset, tsCode).
+		Order("f_model, f_horizon").Find(&list).Error
+
+	return list, err
+}
+
+// GetStockLastTradeDate 股票最新一条日线的交易日，没有数据时为零值
+func GetStockLastTradeDate(ctx context.Context, tsCode string) (time.Time, error) {
+	var date *time.Time
+	err := connector.GetDB().WithContext(ctx).
+		Raw("SELECT MAX(f_trade_date) FROM t_stock_data WHERE f_ts_code = ?", tsCode).
+		Scan(&date).Error
+	if err != nil || date == nil {
+		return time.Time{}, err
+	}
+	return *date, nil
+}
+
+// GetFundLastTradeDate 基金最新一条日线的交易日，没有数据时为零值
+func GetFundLastTradeDate(ctx context.Context, tsCode string) (time.Time, error) {
+	var date *time.Time
+	err := connector.GetDB().WithContext(ctx).
+		Raw("SELECT MAX(f_trade_date) FROM t_fund_data WHERE f_ts_code = ?", tsCode).
+		Scan(&date).Error
+	if err != nil || date == nil {
+		return time.Time{}, err
+	}
+	return *date, nil
+}
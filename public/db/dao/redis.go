@@ -2,36 +2,47 @@ package dao
 
 import (
 	"context"
-	"financia/public"
+	"encoding/json"
+	"errors"
 	"financia/public/db/connector"
-	"fmt"
-	"github.com/spf13/cast"
-	"go.uber.org/zap"
+	"financia/util"
+	"github.com/go-redis/redis/v8"
 	"time"
 )
 
+// EmailCodeTTL 验证码的有效期，也是再次获取的间隔
+const EmailCodeTTL = 60 * time.Second
+
 func SetEmailCode(ctx context.Context, email, code string) error {
-	return connector.GetRedis().Set(ctx, email, code, 60*time.Second).Err()
+	return connector.GetRedis().Set(ctx, email, code, EmailCodeTTL).Err()
+}
+
+func DelEmailCode(ctx context.Context, email string) error {
+	return connector.GetRedis().Del(ctx, email).Err()
 }
 
 func GetEmailCode(ctx context.Context, email string) (string, error) {
 	return connector.GetRedis().Get(ctx, email).Result()
 }
 
-func GetFollowList(c context.Context, userId int64) ([]int, []int, error) {
-	rdb := connector.GetRedis().WithContext(c)
-
-	pipe := rdb.Pipeline()
-	stockFollowCmd := pipe.SMembers(c, fmt.Sprintf(public.RedisKeyStockFollow, userId))
-	fundFollowCmd := pipe.SMembers(c, fmt.Sprintf(public.RedisKeyFundFollow, userId))
-
-	if _, err := pipe.Exec(c); err != nil {
-		zap.S().Error("[Info] [Pipeline] [err] = ", err.Error())
-		return nil, nil, err
+// CachedList 读取列表数据的缓存，未命中时通过 fetch 拉取并缓存到当天结束
+func CachedList[T any](ctx context.Context, key string, fetch func(ctx context.Context) ([]*T, error)) ([]*T, error) {
+	rdb := connector.GetRedis().WithContext(ctx)
+	result, err := rdb.Get(ctx, key).Result()
+	if err == nil {
+		var list []*T
+		err = json.Unmarshal([]byte(result), &list)
+		return list, err
+	}
+	if !errors.Is(err, redis.Nil) {
+		return nil, err
 	}
 
-	stockResult, _ := stockFollowCmd.Result()
-	fundResult, _ := fundFollowCmd.Result()
-
-	return cast.ToIntSlice(stockResult), cast.ToIntSlice(fundResult), nil
+	list, err := fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	data, _ := json.Marshal(list)
+	rdb.Set(ctx, key, data, time.Duration(util.SecondsUntilMidnight())*time.Second)
+	return list, nil
 }
@@ -91,6 +91,14 @@ func GetStockInfo(ctx context.Context, id int) (*model.StockInfo, error) {
 	return &stockInfo, err
 }
 
+func GetStockInfoByTsCode(ctx context.Context, tsCode string) (*model.StockInfo, error) {
+	var stockInfo model.StockInfo
+	err := connector.GetDB().WithContext(ctx).Model(&model.StockInfo{}).
+		Where("f_ts_code = ?", tsCode).First(&stockInfo).Error
+
+	return &stockInfo, err
+}
+
 func GetStockInfos(ctx context.Context, ids []int) ([]*model.StockInfo, error) {
 	var stockInfos []*model.StockInfo
 	err := connector.GetDB().WithContext(ctx).Model(&model.StockInfo{}).
@@ -99,6 +107,16 @@ func GetStockInfos(ctx context.Context, ids []int) ([]*model.StockInfo, error) {
 	return stockInfos, err
 }
 
+// GetStockPeers 同行业的上市公司，不含 tsCode 自身
+func GetStockPeers(ctx context.Context, industry, tsCode string, limit int) ([]*model.StockInfo, error) {
+	var stockInfos []*model.StockInfo
+	err := connector.GetDB().WithContext(ctx).Model(&model.StockInfo{}).
+		Where("f_industry = ? AND f_ts_code <> ? AND f_list_status = ?", industry, tsCode, "L").
+		Order("f_id").Limit(limit).Find(&stockInfos).Error
+
+	return stockInfos, err
+}
+
 func GetStockList(ctx context.Context, search string, isHs, exchange, market []string, page, pageSize int) ([]*model.StockInfo, int64, error) {
 	var stockList []*model.StockInfo
 	db := connector.GetDB().Model(&model.StockInfo{})
@@ -141,9 +159,14 @@ func GetAllStockData(ctx context.Context, tsCode string) ([]*model.StockData, er
 }
 
 func GetStockDataLimit30(ctx context.Context, tsCode string) ([]*model.StockData, error) {
+	return GetStockDataLimit(ctx, tsCode, 31)
+}
+
+// GetStockDataLimit 最近 limit 个交易日的数据，按日期倒序，同时缓存最新收盘价
+func GetStockDataLimit(ctx context.Context, tsCode string, limit int) ([]*model.StockData, error) {
 	stockData := make([]*model.StockData, 0)
 	err := connector.GetDB().WithContext(ctx).
-		Raw("SELECT * FROM t_stock_data WHERE f_ts_code = ? order by f_trade_date desc limit 31", tsCode).
+		Raw("SELECT * FROM t_stock_data WHERE f_ts_code = ? order by f_trade_date desc limit ?", tsCode, limit).
 		Scan(&stockData).Error
 
 	if len(stockData) == 0 {
@@ -165,16 +188,44 @@ func CheckStockData(ctx context.Context, tsCode string) (bool, error) {
 	return count > 0, err
 }
 
-func InsertStockData(ctx context.Context, data []*model.StockData) error {
-	// 分批插入
-	for i := 0; i < len(data); i += 1000 {
-		end := i + 1000
-		if end > len(data) {
-			end = len(data)
+// UpsertStockData 写入股票日线，同一代码同一交易日重复写入时覆盖旧数据
+func UpsertStockData(ctx context.Context, data []*model.StockData) error {
+	// 按分表分组，直接写物理分表
+	table := model.StockData{}.TableName()
+	groups := make(map[string][]*model.StockData)
+	for _, v := range data {
+		if v.Id == 0 {
+			v.Id = int(connector.GenerateID())
 		}
-		if err := connector.GetDB().WithContext(ctx).Create(data[i:end]).Error; err != nil {
+		shard := table + connector.ShardSuffix(v.TsCode)
+		groups[shard] = append(groups[shard], v)
+	}
+
+	for shard, group := range groups {
+		if err := upsertDaily(ctx, shard, group); err != nil {
 			return err
 		}
 	}
 	return nil
 }
+
+// GetStockDataCodesByDate 获取某个交易日已入库的股票代码，遍历所有分表
+func GetStockDataCodesByDate(ctx context.Context, tradeDate time.Time) (map[string]struct{}, error) {
+	return dataCodesByDate(ctx, model.StockData{}.TableName(), tradeDate)
+}
+
+// GetListedStockCodes 获取上市状态的股票代码
+func GetListedStockCodes(ctx context.Context) (map[string]struct{}, error) {
+	var codes []string
+	err := connector.GetDB().WithContext(ctx).Model(&model.StockInfo{}).
+		Where("f_list_status = ?", "L").Pluck("f_ts_code", &codes).Error
+	if err != nil {
+		return nil, err
+	}
+
+	set := make(map[string]struct{}, len(codes))
+	for _, v := range codes {
+		set[v] = struct{}{}
+	}
+	return set, nil
+}
@@ -0,0 +1,237 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"financia/public"
+	"financia/public/db/connector"
+	"financia/public/db/model"
+	"fmt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// DefaultWatchlistName 关注按钮自动创建的默认列表名称
+const DefaultWatchlistName = "我的关注"
+
+// ListWatchlists 用户的自选列表，按排序升序
+func ListWatchlists(ctx context.Context, userId int64) ([]*model.Watchlist, error) {
+	var list []*model.Watchlist
+	err := connector.GetDB().WithContext(ctx).
+		Where("f_user_id = ?", userId).Order("f_sort, f_id").Find(&list).Error
+
+	return list, err
+}
+
+// ListWatchlistItems 用户所有列表中的条目，按排序升序
+func ListWatchlistItems(ctx context.Context, userId int64) ([]*model.WatchlistItem, error) {
+	var list []*model.WatchlistItem
+	err := connector.GetDB().WithContext(ctx).
+		Where("f_user_id = ?", userId).Order("f_sort, f_id").Find(&list).Error
+
+	return list, err
+}
+
+// GetWatchlist 用户的自选列表，不属于该用户时返回 gorm.ErrRecordNotFound
+func GetWatchlist(ctx context.Context, userId, id int64) (*model.Watchlist, error) {
+	var watchlist model.Watchlist
+	err := connector.GetDB().WithContext(ctx).
+		Where("f_id = ? AND f_user_id = ?", id, userId).First(&watchlist).Error
+
+	return &watchlist, err
+}
+
+// WatchlistNameExists 用户是否已有同名列表，excludeId 为重命名的列表自身
+func WatchlistNameExists(ctx context.Context, userId int64, name string, excludeId int64) (bool, error) {
+	var count int64
+	err := connector.GetDB().WithContext(ctx).Model(&model.Watchlist{}).
+		Where("f_user_id = ? AND f_name = ? AND f_id <> ?", userId, name, excludeId).Count(&count).Error
+
+	return count > 0, err
+}
+
+// CreateWatchlist 创建列表，排在用户已有列表之后
+func CreateWatchlist(ctx context.Context, watchlist *model.Watchlist) error {
+	db := connector.GetDB().WithContext(ctx)
+	if err := db.Model(&model.Watchlist{}).Where("f_user_id = ?", watchlist.UserId).
+		Select("COALESCE(MAX(f_sort), -1) + 1").Scan(&watchlist.Sort).Error; err != nil {
+		return err
+	}
+	return db.Create(watchlist).Error
+}
+
+// GetDefaultWatchlist 用户的默认列表，不存在时创建
+// 已有同名列表（用户自建或并发关注时另一个请求刚创建）时将其设为默认列表
+func GetDefaultWatchlist(ctx context.Context, userId int64) (*model.Watchlist, error) {
+	db := connector.GetDB().WithContext(ctx)
+	var watchlist model.Watchlist
+	err := db.Where("f_user_id = ? AND f_is_default = ?", userId, true).First(&watchlist).Error
+	if err == nil {
+		return &watchlist, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	watchlist = model.Watchlist{UserId: userId, Name: DefaultWatchlistName, IsDefault: true}
+	if err := db.Model(&model.Watchlist{}).Where("f_user_id = ?", userId).
+		Select("COALESCE(MAX(f_sort), -1) + 1").Scan(&watchlist.Sort).Error; err != nil {
+		return nil, err
+	}
+	result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&watchlist)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected > 0 {
+		return &watchlist, nil
+	}
+
+	watchlist = model.Watchlist{}
+	if err := db.Where("f_user_id = ? AND f_name = ?", userId, DefaultWatchlistName).First(&watchlist).Error; err != nil {
+		return nil, err
+	}
+	if !watchlist.IsDefault {
+		if err := db.Model(&watchlist).Update("f_is_default", true).Error; err != nil {
+			return nil, err
+		}
+	}
+	return &watchlist, nil
+}
+
+// RenameWatchlist 重命名用户的列表，返回是否存在该列表
+func RenameWatchlist(ctx context.Context, userId, id int64, name string) (bool, error) {
+	watchlist, err := GetWatchlist(ctx, userId, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	// 名称不变时影响行数为 0，存在与否以上面的查询为准
+	return true, connector.GetDB().WithContext(ctx).Model(watchlist).Update("f_name", name).Error
+}
+
+// DeleteWatchlist 删除用户的列表及其条目，返回是否删除
+func DeleteWatchlist(ctx context.Context, userId, id int64) (bool, error) {
+	var deleted bool
+	err := connector.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("f_id = ? AND f_user_id = ?", id, userId).Delete(&model.Watchlist{})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		deleted = true
+		return tx.Where("f_watchlist_id = ?", id).Delete(&model.WatchlistItem{}).Error
+	})
+
+	return deleted, err
+}
+
+// SortWatchlists 按 ids 的顺序重排用户的列表，不属于该用户的 ID 忽略
+func SortWatchlists(ctx context.Context, userId int64, ids []int64) error {
+	return connector.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for i, id := range ids {
+			err := tx.Model(&model.Watchlist{}).Where("f_id = ? AND f_user_id = ?", id, userId).
+				Update("f_sort", i).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// AddWatchlistItem 条目加入列表末尾，列表中已有同一代码时不重复加入
+func AddWatchlistItem(ctx context.Context, item *model.WatchlistItem) error {
+	db := connector.GetDB().WithContext(ctx)
+	if err := db.Model(&model.WatchlistItem{}).Where("f_watchlist_id = ?", item.WatchlistId).
+		Select("COALESCE(MAX(f_sort), -1) + 1").Scan(&item.Sort).Error; err != nil {
+		return err
+	}
+	return db.Clauses(clause.OnConflict{DoNothing: true}).Create(item).Error
+}
+
+// UpdateWatchlistItemNote 修改用户条目的备注，返回是否存在该条目
+func UpdateWatchlistItemNote(ctx context.Context, userId, id int64, note string) (bool, error) {
+	db := connector.GetDB().WithContext(ctx)
+	var item model.WatchlistItem
+	err := db.Select("f_id").Where("f_id = ? AND f_user_id = ?", id, userId).First(&item).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	// 备注不变时影响行数为 0，存在与否以上面的查询为准
+	return true, db.Model(&item).Update("f_note", note).Error
+}
+
+// DeleteWatchlistItem 删除用户的条目，返回是否删除
+func DeleteWatchlistItem(ctx context.Context, userId, id int64) (bool, error) {
+	result := connector.GetDB().WithContext(ctx).
+		Where("f_id = ? AND f_user_id = ?", id, userId).Delete(&model.WatchlistItem{})
+
+	return result.RowsAffected > 0, result.Error
+}
+
+// SortWatchlistItems 按 ids 的顺序重排列表中的条目，不属于该列表的 ID 忽略
+func SortWatchlistItems(ctx context.Context, userId, watchlistId int64, ids []int64) error {
+	return connector.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for i, id := range ids {
+			err := tx.Model(&model.WatchlistItem{}).
+				Where("f_id = ? AND f_watchlist_id = ? AND f_user_id = ?", id, watchlistId, userId).
+				Update("f_sort", i).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// IsFollowed 股票或基金是否在用户的任一列表中
+func IsFollowed(ctx context.Context, userId int64, asset string, refId int) (bool, error) {
+	var count int64
+	err := connector.GetDB().WithContext(ctx).Model(&model.WatchlistItem{}).
+		Where("f_user_id = ? AND f_asset = ? AND f_ref_id = ?", userId, asset, refId).Count(&count).Error
+
+	return count > 0, err
+}
+
+// Unfollow 从用户的所有列表中移除股票或基金
+func Unfollow(ctx context.Context, userId int64, asset string, refId int) error {
+	return connector.GetDB().WithContext(ctx).
+		Where("f_user_id = ? AND f_asset = ? AND f_ref_id = ?", userId, asset, refId).
+		Delete(&model.WatchlistItem{}).Error
+}
+
+// GetFollowList 用户关注的股票与基金 ID，按列表与条目的排序去重
+func GetFollowList(ctx context.Context, userId int64) ([]int, []int, error) {
+	var items []*model.WatchlistItem
+	err := connector.GetDB().WithContext(ctx).Model(&model.WatchlistItem{}).
+		Joins("JOIN t_watchlist ON t_watchlist.f_id = t_watchlist_item.f_watchlist_id").
+		Where("t_watchlist_item.f_user_id = ? AND t_watchlist_item.f_asset IN ?", userId, []string{public.AssetStock, public.AssetFund}).
+		Order("t_watchlist.f_sort, t_watchlist_item.f_sort, t_watchlist_item.f_id").
+		Select("t_watchlist_item.f_asset, t_watchlist_item.f_ref_id").
+		Find(&items).Error
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var stockIds, fundIds []int
+	seen := make(map[string]bool, len(items))
+	for _, v := range items {
+		key := fmt.Sprintf("%s:%d", v.Asset, v.RefId)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		if v.Asset == public.AssetStock {
+			stockIds = append(stockIds, v.RefId)
+		} else {
+			fundIds = append(fundIds, v.RefId)
+		}
+	}
+	return stockIds, fundIds, nil
+}
@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// PredictAccuracy 预测的样本外准确率，由每日任务按最近一段时间已实现的预测滚动计算
+// TsCode 为空表示该模型在全部代码上的汇总
+type PredictAccuracy struct {
+	Id        int       `gorm:"primaryKey;autoIncrement;column:f_id" json:"id"`
+	Asset     string    `gorm:"type:varchar(10);column:f_asset;uniqueIndex:uk_accuracy,priority:1" json:"asset"`
+	TsCode    string    `gorm:"type:varchar(20);column:f_ts_code;uniqueIndex:uk_accuracy,priority:2" json:"tsCode"`
+	Model     string    `gorm:"type:varchar(32);column:f_model;uniqueIndex:uk_accuracy,priority:3" json:"model"`
+	Horizon   int       `gorm:"type:int;column:f_horizon;uniqueIndex:uk_accuracy,priority:4" json:"horizon"`
+	Samples   int       `gorm:"type:int;column:f_samples" json:"samples"`
+	HitRate   float64   `gorm:"type:decimal(6,4);column:f_hit_rate" json:"hitRate"` // 方向命中率，0~1
+	MAE       float64   `gorm:"type:decimal(12,4);column:f_mae" json:"mae"`
+	MAPE      float64   `gorm:"type:decimal(10,4);column:f_mape" json:"mape"` // 0~1
+	UpdatedAt time.Time `gorm:"column:f_updated_at;autoUpdateTime" json:"updatedAt"`
+}
+
+func (PredictAccuracy) TableName() string {
+	return "t_predict_accuracy"
+}
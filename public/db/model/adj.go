@@ -0,0 +1,60 @@
+package model
+
+import (
+	"sort"
+	"time"
+)
+
+// AdjustStockData 按复权因子调整价格，成交量与涨跌幅不变
+// base 为基准因子：前复权取最新因子，后复权取 1
+// factors 需按交易日升序，缺失因子的交易日沿用之前最近的因子
+func AdjustStockData(list []*StockData, factors []*StockAdjFactor, base float64) {
+	if len(factors) == 0 || base == 0 {
+		return
+	}
+
+	for _, v := range list {
+		ratio := AdjFactorAt(factors, v.TradeDate) / base
+		v.Open *= ratio
+		v.High *= ratio
+		v.Low *= ratio
+		v.Close *= ratio
+		v.PreClose *= ratio
+		v.Change *= ratio
+	}
+}
+
+// AdjustStockPredicts 将预测记录的价格从 TradeDate 当天的价格口径转为复权价格，base 同 AdjustStockData
+func AdjustStockPredicts(list []*StockPredict, factors []*StockAdjFactor, base float64) {
+	if len(factors) == 0 || base == 0 {
+		return
+	}
+
+	for _, v := range list {
+		ratio := AdjFactorAt(factors, v.TradeDate) / base
+		v.LastClose *= ratio
+		v.Predict *= ratio
+		v.Lower *= ratio
+		v.Upper *= ratio
+		if v.Actual != nil {
+			actual := *v.Actual * ratio
+			v.Actual = &actual
+		}
+	}
+}
+
+// AdjFactorAt 最后一个不晚于 date 的因子，早于所有因子时取第一个，没有因子时为 1
+// factors 需按交易日升序
+func AdjFactorAt(factors []*StockAdjFactor, date time.Time) float64 {
+	if len(factors) == 0 {
+		return 1
+	}
+
+	i := sort.Search(len(factors), func(i int) bool {
+		return factors[i].TradeDate.After(date)
+	})
+	if i > 0 {
+		i--
+	}
+	return factors[i].AdjFactor
+}
@@ -0,0 +1,71 @@
+package model
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func date(s string) time.Time {
+	t, _ := time.Parse(time.DateOnly, s)
+	return t
+}
+
+func TestAdjustStockData(t *testing.T) {
+	// 01-03 除权，因子从 1 变为 2
+	factors := []*StockAdjFactor{
+		{TradeDate: date("2024-01-02"), AdjFactor: 1},
+		{TradeDate: date("2024-01-03"), AdjFactor: 2},
+	}
+	newList := func() []*StockData {
+		return []*StockData{
+			{TradeDate: date("2024-01-01"), Close: 20},
+			{TradeDate: date("2024-01-02"), Close: 20},
+			{TradeDate: date("2024-01-03"), Close: 10, PreClose: 10},
+			{TradeDate: date("2024-01-04"), Close: 11, PreClose: 10, Change: 1, PctChg: 10},
+		}
+	}
+
+	tests := []struct {
+		name string
+		base float64
+		want []float64
+	}{
+		{"qfq", 2, []float64{10, 10, 10, 11}},
+		{"hfq", 1, []float64{20, 20, 20, 22}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list := newList()
+			AdjustStockData(list, factors, tt.base)
+			for i, v := range list {
+				if math.Abs(v.Close-tt.want[i]) > 1e-9 {
+					t.Errorf("close[%d] = %v, want %v", i, v.Close, tt.want[i])
+				}
+			}
+			if last := list[len(list)-1]; last.PctChg != 10 {
+				t.Errorf("pctChg = %v, want unchanged", last.PctChg)
+			}
+		})
+	}
+}
+
+func TestAdjustStockPredicts(t *testing.T) {
+	factors := []*StockAdjFactor{
+		{TradeDate: date("2024-01-02"), AdjFactor: 1},
+		{TradeDate: date("2024-01-03"), AdjFactor: 2},
+	}
+	actual := 21.0
+	list := []*StockPredict{
+		{TradeDate: date("2024-01-02"), LastClose: 20, Predict: 22, Actual: &actual},
+		{TradeDate: date("2024-01-03"), LastClose: 10, Predict: 11},
+	}
+
+	AdjustStockPredicts(list, factors, 2)
+	if list[0].LastClose != 10 || list[0].Predict != 11 || *list[0].Actual != 10.5 || list[1].Predict != 11 {
+		t.Errorf("qfq = %+v, %+v", list[0], list[1])
+	}
+	if actual != 21 {
+		t.Errorf("actual modified in place: %v", actual)
+	}
+}
@@ -0,0 +1,40 @@
+package model
+
+import "time"
+
+// AlertRule 用户设置的提醒规则，收盘数据入库后判断
+type AlertRule struct {
+	Id            int64      `gorm:"primaryKey;autoIncrement;column:f_id" json:"id"`
+	UserId        int64      `gorm:"column:f_user_id;index" json:"-"`
+	Asset         string     `gorm:"type:varchar(10);column:f_asset" json:"asset"`
+	RefId         int        `gorm:"column:f_ref_id" json:"refId"` // 股票、基金信息表的 ID
+	TsCode        string     `gorm:"type:varchar(20);column:f_ts_code" json:"tsCode"`
+	Name          string     `gorm:"type:varchar(100);column:f_name" json:"name"`
+	Type          string     `gorm:"type:varchar(20);column:f_type" json:"type"` // 见 server/alert 中的规则类型
+	Threshold     float64    `gorm:"column:f_threshold" json:"threshold"`
+	Period        int        `gorm:"column:f_period" json:"period,omitempty"` // RSI 周期
+	Cooldown      int        `gorm:"column:f_cooldown" json:"cooldown"`       // 两次提醒的最短间隔，单位小时
+	Enabled       bool       `gorm:"column:f_enabled;index" json:"enabled"`
+	LastFiredAt   *time.Time `gorm:"column:f_last_fired_at" json:"lastFiredAt"`
+	LastTradeDate *time.Time `gorm:"type:date;column:f_last_trade_date" json:"-"` // 上次提醒所用数据的交易日，同一交易日不重复提醒
+	CreatedAt     time.Time  `gorm:"column:f_created_at;autoCreateTime" json:"createdAt"`
+}
+
+func (AlertRule) TableName() string {
+	return "t_alert_rule"
+}
+
+// AlertFire 规则的一次触发
+type AlertFire struct {
+	Id        int64     `gorm:"primaryKey;autoIncrement;column:f_id" json:"id"`
+	RuleId    int64     `gorm:"column:f_rule_id;index" json:"ruleId"`
+	UserId    int64     `gorm:"column:f_user_id;index" json:"-"`
+	TradeDate time.Time `gorm:"type:date;column:f_trade_date" json:"tradeDate"`
+	Value     float64   `gorm:"column:f_value" json:"value"` // 与阈值比较的值
+	Message   string    `gorm:"type:varchar(200);column:f_message" json:"message"`
+	CreatedAt time.Time `gorm:"column:f_created_at;autoCreateTime" json:"createdAt"`
+}
+
+func (AlertFire) TableName() string {
+	return "t_alert_fire"
+}
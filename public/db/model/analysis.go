@@ -0,0 +1,44 @@
+package model
+
+import "time"
+
+// AiAnalysis 生成完成的 AI 分析
+// 同一代码、同一交易日、同一提示词版本与模型只生成一次，之后的请求直接回放
+type AiAnalysis struct {
+	Id            int64     `gorm:"primaryKey;autoIncrement;column:f_id" json:"id"`
+	Asset         string    `gorm:"type:varchar(10);column:f_asset;uniqueIndex:uk_analysis,priority:1" json:"asset"`
+	TsCode        string    `gorm:"type:varchar(20);column:f_ts_code;uniqueIndex:uk_analysis,priority:2" json:"tsCode"`
+	TradeDate     time.Time `gorm:"type:date;column:f_trade_date;uniqueIndex:uk_analysis,priority:3" json:"tradeDate"`
+	PromptVersion string    `gorm:"type:varchar(32);column:f_prompt_version;uniqueIndex:uk_analysis,priority:4" json:"promptVersion"`
+	Model         string    `gorm:"type:varchar(64);column:f_model;uniqueIndex:uk_analysis,priority:5" json:"model"`
+	Name          string    `gorm:"type:varchar(100);column:f_name" json:"name"`
+	Content       string    `gorm:"type:text;column:f_content" json:"content"`
+	CreatedAt     time.Time `gorm:"column:f_created_at;autoCreateTime" json:"createdAt"`
+}
+
+func (AiAnalysis) TableName() string {
+	return "t_ai_analysis"
+}
+
+// AiAnalysisView 用户查看过的分析，重复查看只更新时间
+type AiAnalysisView struct {
+	Id         int64     `gorm:"primaryKey;autoIncrement;column:f_id" json:"id"`
+	UserId     int64     `gorm:"column:f_user_id;uniqueIndex:uk_user_analysis,priority:1" json:"-"`
+	AnalysisId int64     `gorm:"column:f_analysis_id;uniqueIndex:uk_user_analysis,priority:2" json:"analysisId"`
+	ViewedAt   time.Time `gorm:"column:f_viewed_at" json:"viewedAt"`
+}
+
+func (AiAnalysisView) TableName() string {
+	return "t_ai_analysis_view"
+}
+
+// AiAnalysisHistory 用户的分析历史，不含正文
+type AiAnalysisHistory struct {
+	Id        int64     `gorm:"column:f_id" json:"id"`
+	Asset     string    `gorm:"column:f_asset" json:"asset"`
+	TsCode    string    `gorm:"column:f_ts_code" json:"tsCode"`
+	Name      string    `gorm:"column:f_name" json:"name"`
+	TradeDate time.Time `gorm:"column:f_trade_date" json:"tradeDate"`
+	Model     string    `gorm:"column:f_model" json:"model"`
+	ViewedAt  time.Time `gorm:"column:f_viewed_at" json:"viewedAt"`
+}
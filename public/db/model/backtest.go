@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+// BacktestJob 异步回测任务，Params 为 backtest.Params、Result 为 backtest.Report 的 JSON
+type BacktestJob struct {
+	Id        int64     `gorm:"primaryKey;autoIncrement;column:f_id" json:"id"`
+	UserId    int64     `gorm:"column:f_user_id;index" json:"-"`
+	Asset     string    `gorm:"type:varchar(10);column:f_asset" json:"asset"`
+	TsCode    string    `gorm:"type:varchar(20);column:f_ts_code" json:"tsCode"`
+	StartDate time.Time `gorm:"type:date;column:f_start_date" json:"startDate"`
+	EndDate   time.Time `gorm:"type:date;column:f_end_date" json:"endDate"`
+	Params    string    `gorm:"type:text;column:f_params" json:"params"`
+	Status    string    `gorm:"type:varchar(10);column:f_status;index" json:"status"`
+	Error     string    `gorm:"type:varchar(512);column:f_error" json:"error"`
+	Result    string    `gorm:"type:longtext;column:f_result" json:"-"`
+	CreatedAt time.Time `gorm:"column:f_created_at;autoCreateTime" json:"createdAt"`
+	UpdatedAt time.Time `gorm:"column:f_updated_at;autoUpdateTime" json:"updatedAt"`
+}
+
+func (BacktestJob) TableName() string {
+	return "t_backtest_job"
+}
@@ -0,0 +1,43 @@
+package model
+
+import "financia/util"
+
+// StockBars 股票日线转为 K 线
+func StockBars(list []*StockData) []*util.Bar {
+	bars := make([]*util.Bar, 0, len(list))
+	for _, v := range list {
+		bars = append(bars, &util.Bar{
+			Date:     v.TradeDate,
+			Open:     v.Open,
+			High:     v.High,
+			Low:      v.Low,
+			Close:    v.Close,
+			PreClose: v.PreClose,
+			Change:   v.Change,
+			PctChg:   v.PctChg,
+			Vol:      float64(v.Vol),
+			Amount:   v.Amount,
+		})
+	}
+	return bars
+}
+
+// FundBars 基金日线转为 K 线
+func FundBars(list []*FundData) []*util.Bar {
+	bars := make([]*util.Bar, 0, len(list))
+	for _, v := range list {
+		bars = append(bars, &util.Bar{
+			Date:     v.TradeDate,
+			Open:     v.Open,
+			High:     v.High,
+			Low:      v.Low,
+			Close:    v.Close,
+			PreClose: v.PreClose,
+			Change:   v.Change,
+			PctChg:   v.PctChg,
+			Vol:      v.Vol,
+			Amount:   v.Amount,
+		})
+	}
+	return bars
+}
@@ -0,0 +1,33 @@
+package model
+
+import "time"
+
+// AiChat 用户针对某个代码的 AI 对话
+// Context 为创建时渲染的行情数据与提示词，作为第一条消息固定发送
+type AiChat struct {
+	Id        int64     `gorm:"primaryKey;autoIncrement;column:f_id" json:"id"`
+	UserId    int64     `gorm:"column:f_user_id;index:idx_user_updated,priority:1" json:"-"`
+	Asset     string    `gorm:"type:varchar(10);column:f_asset" json:"asset"`
+	TsCode    string    `gorm:"type:varchar(20);column:f_ts_code" json:"tsCode"`
+	Name      string    `gorm:"type:varchar(100);column:f_name" json:"name"`
+	Context   string    `gorm:"type:mediumtext;column:f_context" json:"-"`
+	CreatedAt time.Time `gorm:"column:f_created_at;autoCreateTime" json:"createdAt"`
+	UpdatedAt time.Time `gorm:"column:f_updated_at;autoUpdateTime;index:idx_user_updated,priority:2" json:"updatedAt"`
+}
+
+func (AiChat) TableName() string {
+	return "t_ai_chat"
+}
+
+// AiChatMessage 对话中的一条消息
+type AiChatMessage struct {
+	Id        int64     `gorm:"primaryKey;autoIncrement;column:f_id" json:"id"`
+	ChatId    int64     `gorm:"column:f_chat_id;index" json:"-"`
+	Role      string    `gorm:"type:varchar(16);column:f_role" json:"role"` // user 或 assistant
+	Content   string    `gorm:"type:text;column:f_content" json:"content"`
+	CreatedAt time.Time `gorm:"column:f_created_at;autoCreateTime" json:"createdAt"`
+}
+
+func (AiChatMessage) TableName() string {
+	return "t_ai_chat_message"
+}
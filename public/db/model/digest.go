@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+// DigestSubscription 用户订阅的每日摘要邮件
+type DigestSubscription struct {
+	Id            int64      `gorm:"primaryKey;autoIncrement;column:f_id" json:"-"`
+	UserId        int64      `gorm:"column:f_user_id;uniqueIndex" json:"-"`
+	Enabled       bool       `gorm:"column:f_enabled;index:idx_enabled_hour,priority:1" json:"enabled"`
+	SendHour      int        `gorm:"column:f_send_hour;index:idx_enabled_hour,priority:2" json:"sendHour"` // 发送的整点，北京时间
+	Token         string     `gorm:"type:varchar(64);column:f_token;uniqueIndex" json:"-"`                 // 退订链接中的令牌
+	LastTradeDate *time.Time `gorm:"type:date;column:f_last_trade_date" json:"-"`                          // 上次发送的数据的交易日，同一交易日只发送一次
+	LastSentAt    *time.Time `gorm:"column:f_last_sent_at" json:"lastSentAt"`
+	CreatedAt     time.Time  `gorm:"column:f_created_at;autoCreateTime" json:"createdAt"`
+	UpdatedAt     time.Time  `gorm:"column:f_updated_at;autoUpdateTime" json:"updatedAt"`
+}
+
+func (DigestSubscription) TableName() string {
+	return "t_digest_subscription"
+}
@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+// IngestCheckpoint 日线数据拉取进度，每张表一条
+type IngestCheckpoint struct {
+	Id        int       `gorm:"primaryKey;autoIncrement;column:f_id" json:"id"`
+	Table     string    `gorm:"type:varchar(50);column:f_table;uniqueIndex" json:"table"`
+	LastDate  time.Time `gorm:"type:date;column:f_last_date" json:"lastDate"`
+	UpdatedAt time.Time `gorm:"column:f_updated_at;autoUpdateTime" json:"updatedAt"`
+}
+
+func (IngestCheckpoint) TableName() string {
+	return "t_ingest_checkpoint"
+}
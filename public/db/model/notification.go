@@ -0,0 +1,42 @@
+package model
+
+import "time"
+
+// Notification 一条消息在一个渠道上的发送记录
+type Notification struct {
+	Id            int64      `gorm:"primaryKey;autoIncrement;column:f_id" json:"id"`
+	UserId        int64      `gorm:"column:f_user_id;index" json:"-"`
+	Channel       string     `gorm:"type:varchar(10);column:f_channel" json:"channel"` // email、webhook 或 inbox
+	Kind          string     `gorm:"type:varchar(20);column:f_kind" json:"kind"`       // 模板名称
+	Target        string     `gorm:"type:varchar(100);column:f_target" json:"target"`  // 邮件的收件地址
+	Subject       string     `gorm:"type:varchar(200);column:f_subject" json:"subject"`
+	Text          string     `gorm:"type:mediumtext;column:f_text" json:"-"`
+	HTML          string     `gorm:"type:mediumtext;column:f_html" json:"-"`
+	Unsubscribe   string     `gorm:"type:varchar(300);column:f_unsubscribe" json:"-"` // 邮件的一键退订地址
+	Status        string     `gorm:"type:varchar(10);column:f_status;index:idx_status_next,priority:1" json:"status"`
+	Attempts      int        `gorm:"column:f_attempts" json:"attempts"`
+	LastError     string     `gorm:"type:varchar(500);column:f_last_error" json:"lastError"`
+	NextAttemptAt time.Time  `gorm:"column:f_next_attempt_at;index:idx_status_next,priority:2" json:"nextAttemptAt"`
+	SentAt        *time.Time `gorm:"column:f_sent_at" json:"sentAt"`
+	CreatedAt     time.Time  `gorm:"column:f_created_at;autoCreateTime" json:"createdAt"`
+}
+
+func (Notification) TableName() string {
+	return "t_notification"
+}
+
+// InboxMessage 站内信
+type InboxMessage struct {
+	Id        int64     `gorm:"primaryKey;autoIncrement;column:f_id" json:"id"`
+	UserId    int64     `gorm:"column:f_user_id;index:idx_user_read,priority:1" json:"-"`
+	Kind      string    `gorm:"type:varchar(20);column:f_kind" json:"kind"`
+	Subject   string    `gorm:"type:varchar(200);column:f_subject" json:"subject"`
+	Text      string    `gorm:"type:mediumtext;column:f_text" json:"text"`
+	HTML      string    `gorm:"type:mediumtext;column:f_html" json:"html"`
+	Read      bool      `gorm:"column:f_read;index:idx_user_read,priority:2" json:"read"`
+	CreatedAt time.Time `gorm:"column:f_created_at;autoCreateTime" json:"createdAt"`
+}
+
+func (InboxMessage) TableName() string {
+	return "t_inbox_message"
+}
@@ -0,0 +1,36 @@
+package model
+
+import "time"
+
+// Portfolio 用户记录的模拟持仓组合
+type Portfolio struct {
+	Id        int64     `gorm:"primaryKey;autoIncrement;column:f_id" json:"id"`
+	UserId    int64     `gorm:"column:f_user_id;index" json:"-"`
+	Name      string    `gorm:"type:varchar(50);column:f_name" json:"name"`
+	Method    string    `gorm:"type:varchar(10);column:f_method" json:"method"`       // 成本计算方法：fifo 或 average
+	Benchmark string    `gorm:"type:varchar(20);column:f_benchmark" json:"benchmark"` // 基准指数代码
+	CreatedAt time.Time `gorm:"column:f_created_at;autoCreateTime" json:"createdAt"`
+}
+
+func (Portfolio) TableName() string {
+	return "t_portfolio"
+}
+
+// PortfolioTransaction 组合中的一笔买卖
+type PortfolioTransaction struct {
+	Id          int64     `gorm:"primaryKey;autoIncrement;column:f_id" json:"id"`
+	PortfolioId int64     `gorm:"column:f_portfolio_id;index" json:"portfolioId"`
+	Asset       string    `gorm:"type:varchar(10);column:f_asset" json:"asset"`
+	TsCode      string    `gorm:"type:varchar(20);column:f_ts_code" json:"tsCode"`
+	Name        string    `gorm:"type:varchar(100);column:f_name" json:"name"`
+	Side        string    `gorm:"type:varchar(4);column:f_side" json:"side"` // buy 或 sell
+	Quantity    float64   `gorm:"column:f_quantity" json:"quantity"`
+	Price       float64   `gorm:"column:f_price" json:"price"`
+	Fee         float64   `gorm:"column:f_fee" json:"fee"`
+	TradeDate   time.Time `gorm:"type:date;column:f_trade_date" json:"tradeDate"`
+	CreatedAt   time.Time `gorm:"column:f_created_at;autoCreateTime" json:"createdAt"`
+}
+
+func (PortfolioTransaction) TableName() string {
+	return "t_portfolio_transaction"
+}
@@ -27,30 +27,53 @@ func (StockInfo) TableName() string {
 
 type StockData struct {
 	Id        int       `gorm:"primaryKey;autoIncrement;column:id" json:"id"`
-	TsCode    string    `gorm:"type:varchar(20);column:f_ts_code" json:"tsCode"`
-	TradeDate time.Time `gorm:"type:date;column:f_trade_date" json:"tradeDate"`
-	Open      float64   `gorm:"type:decimal(10,2);column:f_open" json:"open"`
-	High      float64   `gorm:"type:decimal(10,2);column:f_high" json:"high"`
-	Low       float64   `gorm:"type:decimal(10,2);column:f_low" json:"low"`
-	Close     float64   `gorm:"type:decimal(10,2);column:f_close" json:"close"`
-	PreClose  float64   `gorm:"type:decimal(10,2);column:f_pre_close" json:"preClose"`
-	Change    float64   `gorm:"type:decimal(10,2);column:f_change" json:"change"`
-	PctChg    float64   `gorm:"type:decimal(5,2);column:f_pct_chg" json:"pctChg"`
-	Vol       int64     `gorm:"type:bigint;column:f_vol" json:"vol"`
-	Amount    float64   `gorm:"type:decimal(20,2);column:f_amount" json:"amount"`
+	TsCode    string    `gorm:"type:varchar(20);column:f_ts_code;uniqueIndex:uk_ts_code_trade_date,priority:1" json:"tsCode" tushare:"ts_code"`
+	TradeDate time.Time `gorm:"type:date;column:f_trade_date;uniqueIndex:uk_ts_code_trade_date,priority:2" json:"tradeDate" tushare:"trade_date"`
+	Open      float64   `gorm:"type:decimal(10,2);column:f_open" json:"open" tushare:"open"`
+	High      float64   `gorm:"type:decimal(10,2);column:f_high" json:"high" tushare:"high"`
+	Low       float64   `gorm:"type:decimal(10,2);column:f_low" json:"low" tushare:"low"`
+	Close     float64   `gorm:"type:decimal(10,2);column:f_close" json:"close" tushare:"close"`
+	PreClose  float64   `gorm:"type:decimal(10,2);column:f_pre_close" json:"preClose" tushare:"pre_close"`
+	Change    float64   `gorm:"type:decimal(10,2);column:f_change" json:"change" tushare:"change"`
+	PctChg    float64   `gorm:"type:decimal(5,2);column:f_pct_chg" json:"pctChg" tushare:"pct_chg"`
+	Vol       int64     `gorm:"type:bigint;column:f_vol" json:"vol" tushare:"vol"`
+	Amount    float64   `gorm:"type:decimal(20,2);column:f_amount" json:"amount" tushare:"amount"`
 }
 
 func (StockData) TableName() string {
 	return "t_stock_data"
 }
 
+// StockPredict 股票多步预测，每次预测按模型与步数各写一条
+// TradeDate 为输入窗口的最后一个交易日，Horizon 为向后的交易日数
+// Actual 为第 Horizon 个交易日的实际收盘价（按 TradeDate 当天的价格口径复权），由每日准确率任务回填
 type StockPredict struct {
-	Id        int       `gorm:"primaryKey;autoIncrement;column:f_id" json:"id"`
-	TsCode    string    `gorm:"type:varchar(20);column:f_ts_code" json:"tsCode"`
-	TradeDate time.Time `gorm:"type:date;column:f_trade_date" json:"tradeDate"`
-	Predict   float64   `gorm:"type:decimal(10,2);column:f_predict" json:"predict"`
+	Id           int        `gorm:"primaryKey;autoIncrement;column:f_id" json:"id"`
+	TsCode       string     `gorm:"type:varchar(20);column:f_ts_code;uniqueIndex:uk_predict,priority:1" json:"tsCode"`
+	TradeDate    time.Time  `gorm:"type:date;column:f_trade_date;uniqueIndex:uk_predict,priority:2" json:"tradeDate"`
+	Model        string     `gorm:"type:varchar(32);column:f_model;uniqueIndex:uk_predict,priority:3" json:"model"`
+	Horizon      int        `gorm:"type:int;column:f_horizon;uniqueIndex:uk_predict,priority:4" json:"horizon"`
+	ModelVersion string     `gorm:"type:varchar(64);column:f_model_version" json:"modelVersion"`
+	LastClose    float64    `gorm:"type:decimal(10,2);column:f_last_close" json:"lastClose"`
+	Predict      float64    `gorm:"type:decimal(10,2);column:f_predict" json:"predict"`
+	Lower        float64    `gorm:"type:decimal(10,2);column:f_lower" json:"lower"`
+	Upper        float64    `gorm:"type:decimal(10,2);column:f_upper" json:"upper"`
+	Actual       *float64   `gorm:"type:decimal(10,2);column:f_actual" json:"actual"`
+	TargetDate   *time.Time `gorm:"type:date;column:f_target_date" json:"targetDate"`
+	CreatedAt    time.Time  `gorm:"column:f_created_at;autoCreateTime" json:"createdAt"`
 }
 
 func (StockPredict) TableName() string {
 	return "t_stock_predict"
 }
+
+type StockAdjFactor struct {
+	Id        int       `gorm:"primaryKey;autoIncrement;column:f_id" json:"id"`
+	TsCode    string    `gorm:"type:varchar(20);column:f_ts_code;uniqueIndex:uk_ts_code_trade_date,priority:1" json:"tsCode" tushare:"ts_code"`
+	TradeDate time.Time `gorm:"type:date;column:f_trade_date;uniqueIndex:uk_ts_code_trade_date,priority:2" json:"tradeDate" tushare:"trade_date"`
+	AdjFactor float64   `gorm:"type:decimal(16,6);column:f_adj_factor" json:"adjFactor" tushare:"adj_factor"`
+}
+
+func (StockAdjFactor) TableName() string {
+	return "t_stock_adj_factor"
+}
@@ -0,0 +1,37 @@
+package model
+
+import "time"
+
+// Watchlist 用户的自选列表
+type Watchlist struct {
+	Id        int64     `gorm:"primaryKey;autoIncrement;column:f_id" json:"id"`
+	UserId    int64     `gorm:"column:f_user_id;uniqueIndex:uk_user_name,priority:1" json:"-"`
+	Name      string    `gorm:"type:varchar(50);column:f_name;uniqueIndex:uk_user_name,priority:2" json:"name"`
+	Sort      int       `gorm:"column:f_sort" json:"sort"`
+	IsDefault bool      `gorm:"column:f_is_default" json:"isDefault"` // 关注按钮加入的列表
+	CreatedAt time.Time `gorm:"column:f_created_at;autoCreateTime" json:"createdAt"`
+	UpdatedAt time.Time `gorm:"column:f_updated_at;autoUpdateTime" json:"updatedAt"`
+}
+
+func (Watchlist) TableName() string {
+	return "t_watchlist"
+}
+
+// WatchlistItem 自选列表中的股票、基金或期货品种
+type WatchlistItem struct {
+	Id          int64     `gorm:"primaryKey;autoIncrement;column:f_id" json:"id"`
+	WatchlistId int64     `gorm:"column:f_watchlist_id;uniqueIndex:uk_list_item,priority:1" json:"watchlistId"`
+	UserId      int64     `gorm:"column:f_user_id;index:idx_user_asset,priority:1" json:"-"`
+	Asset       string    `gorm:"type:varchar(10);column:f_asset;uniqueIndex:uk_list_item,priority:2;index:idx_user_asset,priority:2" json:"asset"`
+	Code        string    `gorm:"type:varchar(20);column:f_code;uniqueIndex:uk_list_item,priority:3" json:"code"` // 股票、基金为 ts_code，期货为品种代码
+	RefId       int       `gorm:"column:f_ref_id" json:"refId"`                                                   // 股票、基金信息表的 ID，期货为 0
+	Name        string    `gorm:"type:varchar(100);column:f_name" json:"name"`
+	Sort        int       `gorm:"column:f_sort" json:"sort"`
+	Note        string    `gorm:"type:varchar(500);column:f_note" json:"note"`
+	AddedPrice  *float64  `gorm:"column:f_added_price" json:"addedPrice"` // 加入时的最新收盘价，未知时为空
+	CreatedAt   time.Time `gorm:"column:f_created_at;autoCreateTime" json:"createdAt"`
+}
+
+func (WatchlistItem) TableName() string {
+	return "t_watchlist_item"
+}
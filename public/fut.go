@@ -0,0 +1,42 @@
+package public
+
+// FutProduct 支持的期货品种
+type FutProduct struct {
+	Prd  string
+	Name string
+}
+
+var FutProducts = []*FutProduct{
+	{Prd: "CU", Name: "铜"},
+	{Prd: "SR", Name: "白糖"},
+	{Prd: "CF", Name: "棉花"},
+	{Prd: "AL", Name: "铝"},
+	{Prd: "ZN", Name: "锌"},
+	{Prd: "JD", Name: "鸡蛋"},
+	{Prd: "FG", Name: "玻璃"},
+	{Prd: "AP", Name: "苹果"},
+	{Prd: "PP", Name: "聚丙烯"},
+	{Prd: "RB", Name: "螺纹钢"},
+	{Prd: "RO", Name: "菜籽油"},
+	{Prd: "M", Name: "豆粕"},
+	{Prd: "JM", Name: "焦煤"},
+	{Prd: "ZC", Name: "动力煤"},
+	{Prd: "Y", Name: "豆油"},
+	{Prd: "SS", Name: "不锈钢"},
+	{Prd: "BU", Name: "沥青"},
+	{Prd: "C", Name: "玉米"},
+	{Prd: "AU", Name: "黄金"},
+	{Prd: "RU", Name: "天胶"},
+	{Prd: "RR", Name: "粳米"},
+	{Prd: "RS", Name: "油菜籽"},
+}
+
+// FutProductName 期货品种的名称，不支持的品种返回 false
+func FutProductName(prd string) (string, bool) {
+	for _, v := range FutProducts {
+		if v.Prd == prd {
+			return v.Name, true
+		}
+	}
+	return "", false
+}
@@ -5,14 +5,21 @@ import (
 	"financia/config"
 	"financia/public/middleware"
 	"financia/public/vaildator"
+	"financia/service/alert"
+	"financia/service/analysis"
+	"financia/service/backtest"
 	"financia/service/common"
 	"financia/service/company"
+	"financia/service/digest"
 	"financia/service/economics"
 	"financia/service/fund"
 	"financia/service/fut"
+	"financia/service/inbox"
+	"financia/service/portfolio"
 	"financia/service/stock"
 
 	"financia/service/user"
+	"financia/service/watchlist"
 	"fmt"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -51,6 +58,9 @@ func HTTPRouter() {
 		v1.POST("/login", user.Login)
 		v1.POST("/register", user.Register)
 		v1.GET("/code", user.Code)
+		// 每日摘要 - 邮件中的退订链接，GET 显示确认页面，POST 退订（含一键退订）
+		v1.GET("/digest/unsubscribe", digest.UnsubscribePage)
+		v1.POST("/digest/unsubscribe", digest.Unsubscribe)
 	}
 
 	free := v1.Use(middleware.AuthSet())
@@ -89,6 +99,8 @@ func HTTPRouter() {
 		free.GET("/stock/rank", stock.RankStock)
 		// 股票 - 预测准确率
 		free.GET("/stock/accuracy", stock.AccuracyStock)
+		// 股票 - 技术指标
+		free.GET("/stock/indicators", stock.IndicatorsStock)
 
 		// 公募基金 - 筛选参数
 		free.GET("/fund/query", fund.QueryFund)
@@ -100,6 +112,8 @@ func HTTPRouter() {
 		free.GET("/fund/data", fund.DataFund)
 		// 公募基金 - 首页图表
 		free.GET("/fund/graph", fund.GraphFund)
+		// 公募基金 - 技术指标
+		free.GET("/fund/indicators", fund.IndicatorsFund)
 		// 公募基金 - 预测数准确率
 
 		// 期货 - 筛选参数
@@ -144,8 +158,87 @@ func HTTPRouter() {
 		// 公募基金 - AI分析
 		auth.GET("/fund/ai", fund.AiFund)
 
+		// AI分析 - 历史记录
+		auth.GET("/ai/history", analysis.ListAnalysis)
+		// AI分析 - 历史分析内容
+		auth.GET("/ai/analysis", analysis.GetAnalysis)
+		// AI对话 - 创建
+		auth.POST("/ai/chat", analysis.CreateChat)
+		// AI对话 - 消息记录
+		auth.GET("/ai/chat", analysis.GetChat)
+		// AI对话 - 列表
+		auth.GET("/ai/chat/list", analysis.ListChat)
+		// AI对话 - 追问，回复为 SSE
+		auth.POST("/ai/chat/message", analysis.SendChat)
+		// AI对话 - 删除
+		auth.DELETE("/ai/chat", analysis.DeleteChat)
+
+		// 自选 - 全部列表及条目
+		auth.GET("/watchlist", watchlist.ListWatchlist)
+		// 自选 - 创建列表
+		auth.POST("/watchlist", watchlist.CreateWatchlist)
+		// 自选 - 重命名列表
+		auth.PUT("/watchlist", watchlist.RenameWatchlist)
+		// 自选 - 删除列表及其条目
+		auth.DELETE("/watchlist", watchlist.DeleteWatchlist)
+		// 自选 - 列表排序
+		auth.POST("/watchlist/sort", watchlist.SortWatchlist)
+		// 自选 - 添加条目
+		auth.POST("/watchlist/item", watchlist.AddItem)
+		// 自选 - 修改条目备注
+		auth.PUT("/watchlist/item", watchlist.UpdateItem)
+		// 自选 - 删除条目
+		auth.DELETE("/watchlist/item", watchlist.DeleteItem)
+		// 自选 - 条目排序
+		auth.POST("/watchlist/item/sort", watchlist.SortItem)
+
 		// 个人 - 信息提示确认
 		auth.POST("/user/tip/confirm", user.TipConfirm)
+
+		// 回测 - 创建任务
+		auth.POST("/backtest", backtest.CreateBacktest)
+		// 回测 - 任务状态与结果
+		auth.GET("/backtest", backtest.GetBacktest)
+		// 回测 - 任务列表
+		auth.GET("/backtest/list", backtest.ListBacktest)
+
+		// 提醒 - 创建规则
+		auth.POST("/alert", alert.CreateRule)
+		// 提醒 - 规则列表
+		auth.GET("/alert/list", alert.ListRule)
+		// 提醒 - 修改规则
+		auth.PUT("/alert", alert.UpdateRule)
+		// 提醒 - 删除规则及其记录
+		auth.DELETE("/alert", alert.DeleteRule)
+		// 提醒 - 触发记录
+		auth.GET("/alert/history", alert.History)
+
+		// 每日摘要 - 订阅状态
+		auth.GET("/digest", digest.GetDigest)
+		// 每日摘要 - 订阅或修改发送时间
+		auth.POST("/digest", digest.Subscribe)
+		// 每日摘要 - 取消订阅
+		auth.DELETE("/digest", digest.CancelDigest)
+
+		// 站内信 - 列表与未读数
+		auth.GET("/inbox", inbox.ListInbox)
+		// 站内信 - 标记已读
+		auth.POST("/inbox/read", inbox.ReadInbox)
+
+		// 模拟组合 - 创建
+		auth.POST("/portfolio", portfolio.CreatePortfolio)
+		// 模拟组合 - 列表
+		auth.GET("/portfolio/list", portfolio.ListPortfolio)
+		// 模拟组合 - 删除组合及其交易
+		auth.DELETE("/portfolio", portfolio.DeletePortfolio)
+		// 模拟组合 - 录入交易
+		auth.POST("/portfolio/transaction", portfolio.AddTransaction)
+		// 模拟组合 - 交易记录
+		auth.GET("/portfolio/transaction/list", portfolio.ListTransaction)
+		// 模拟组合 - 删除交易
+		auth.DELETE("/portfolio/transaction", portfolio.DeleteTransaction)
+		// 模拟组合 - 持仓、盈亏与收益
+		auth.GET("/portfolio/report", portfolio.Report)
 	}
 
 	httpAddr := fmt.Sprintf("%s:%s", config.Configs.App.IP, config.Configs.App.Port)
@@ -0,0 +1,191 @@
+package server
+
+import (
+	"context"
+	"financia/public"
+	"financia/public/db/dao"
+	"financia/public/db/model"
+	"financia/server/predictor"
+	"go.uber.org/zap"
+	"time"
+)
+
+// accuracyDays 只统计输入窗口在最近 accuracyDays 个自然日内的预测
+const accuracyDays = 90
+
+// accuracyKey 准确率的统计维度，TsCode 为空表示模型在全部代码上的汇总
+type accuracyKey struct {
+	TsCode  string
+	Model   string
+	Horizon int
+}
+
+// DailyAccuracy 收盘数据入库后回填已到期预测的实际收盘价，并按代码、模型与步数统计滚动准确率
+func DailyAccuracy() {
+	ctx := context.Background()
+	since := time.Now().AddDate(0, 0, -accuracyDays)
+
+	if err := evaluateStockPredicts(ctx, since); err != nil {
+		zap.S().Errorf("[DailyAccuracy] [evaluateStockPredicts] [err] = %s", err.Error())
+	}
+	if err := evaluateFundPredicts(ctx, since); err != nil {
+		zap.S().Errorf("[DailyAccuracy] [evaluateFundPredicts] [err] = %s", err.Error())
+	}
+
+	stockList, err := dao.GetEvaluatedStockPredicts(ctx, since)
+	if err != nil {
+		zap.S().Errorf("[DailyAccuracy] [GetEvaluatedStockPredicts] [err] = %s", err.Error())
+	} else {
+		outcomes := make(map[accuracyKey][]*predictor.Outcome)
+		for _, v := range stockList {
+			addOutcome(outcomes, v.TsCode, v.Model, v.Horizon, &predictor.Outcome{Last: v.LastClose, Predict: v.Predict, Actual: *v.Actual})
+		}
+		if err := dao.UpsertPredictAccuracy(ctx, accuracyRows(public.AssetStock, outcomes)); err != nil {
+			zap.S().Errorf("[DailyAccuracy] [UpsertPredictAccuracy] [err] = %s", err.Error())
+		}
+	}
+
+	fundList, err := dao.GetEvaluatedFundPredicts(ctx, since)
+	if err != nil {
+		zap.S().Errorf("[DailyAccuracy] [GetEvaluatedFundPredicts] [err] = %s", err.Error())
+	} else {
+		outcomes := make(map[accuracyKey][]*predictor.Outcome)
+		for _, v := range fundList {
+			addOutcome(outcomes, v.TsCode, v.Model, v.Horizon, &predictor.Outcome{Last: v.LastClose, Predict: v.Predict, Actual: *v.Actual})
+		}
+		if err := dao.UpsertPredictAccuracy(ctx, accuracyRows(public.AssetFund, outcomes)); err != nil {
+			zap.S().Errorf("[DailyAccuracy] [UpsertPredictAccuracy] [err] = %s", err.Error())
+		}
+	}
+}
+
+// evaluateStockPredicts 回填股票预测的实际值
+// 实际收盘价换算到输入窗口最后一天的价格口径，期间除权除息不计入误差
+func evaluateStockPredicts(ctx context.Context, since time.Time) error {
+	pending, err := dao.GetPendingStockPredicts(ctx, since)
+	if err != nil {
+		return err
+	}
+
+	byCode := make(map[string][]*model.StockPredict)
+	for _, v := range pending {
+		byCode[v.TsCode] = append(byCode[v.TsCode], v)
+	}
+
+	today := time.Now().Format(time.DateOnly)
+	done := make([]*model.StockPredict, 0, len(pending))
+	for tsCode, list := range byCode {
+		start := list[0].TradeDate
+		for _, v := range list {
+			if v.TradeDate.Before(start) {
+				start = v.TradeDate
+			}
+		}
+
+		data, err := dao.GetStockData(ctx, tsCode, start.Format(time.DateOnly), today)
+		if err != nil {
+			return err
+		}
+		factors, err := dao.GetStockAdjFactors(ctx, tsCode)
+		if err != nil {
+			return err
+		}
+
+		index := make(map[string]int, len(data))
+		for i, v := range data {
+			index[v.TradeDate.Format(time.DateOnly)] = i
+		}
+
+		for _, v := range list {
+			i, ok := index[v.TradeDate.Format(time.DateOnly)]
+			if !ok || i+v.Horizon >= len(data) {
+				continue
+			}
+
+			target := data[i+v.Horizon]
+			actual := target.Close * model.AdjFactorAt(factors, target.TradeDate) / model.AdjFactorAt(factors, v.TradeDate)
+			v.Actual = &actual
+			v.TargetDate = &target.TradeDate
+			done = append(done, v)
+		}
+	}
+
+	zap.S().Infof("[DailyAccuracy] [stock] %d of %d pending predictions realized", len(done), len(pending))
+	return dao.SetStockPredictActual(ctx, done)
+}
+
+// evaluateFundPredicts 回填基金预测的实际值
+func evaluateFundPredicts(ctx context.Context, since time.Time) error {
+	pending, err := dao.GetPendingFundPredicts(ctx, since)
+	if err != nil {
+		return err
+	}
+
+	byCode := make(map[string][]*model.FundPredict)
+	for _, v := range pending {
+		byCode[v.TsCode] = append(byCode[v.TsCode], v)
+	}
+
+	today := time.Now().Format(time.DateOnly)
+	done := make([]*model.FundPredict, 0, len(pending))
+	for tsCode, list := range byCode {
+		start := list[0].TradeDate
+		for _, v := range list {
+			if v.TradeDate.Before(start) {
+				start = v.TradeDate
+			}
+		}
+
+		data, err := dao.GetFundData(ctx, tsCode, start.Format(time.DateOnly), today)
+		if err != nil {
+			return err
+		}
+
+		index := make(map[string]int, len(data))
+		for i, v := range data {
+			index[v.TradeDate.Format(time.DateOnly)] = i
+		}
+
+		for _, v := range list {
+			i, ok := index[v.TradeDate.Format(time.DateOnly)]
+			if !ok || i+v.Horizon >= len(data) {
+				continue
+			}
+
+			target := data[i+v.Horizon]
+			v.Actual = &target.Close
+			v.TargetDate = &target.TradeDate
+			done = append(done, v)
+		}
+	}
+
+	zap.S().Infof("[DailyAccuracy] [fund] %d of %d pending predictions realized", len(done), len(pending))
+	return dao.SetFundPredictActual(ctx, done)
+}
+
+// addOutcome 同时计入单个代码与模型汇总
+func addOutcome(outcomes map[accuracyKey][]*predictor.Outcome, tsCode, modelName string, horizon int, o *predictor.Outcome) {
+	key := accuracyKey{TsCode: tsCode, Model: modelName, Horizon: horizon}
+	outcomes[key] = append(outcomes[key], o)
+
+	key.TsCode = ""
+	outcomes[key] = append(outcomes[key], o)
+}
+
+func accuracyRows(asset string, outcomes map[accuracyKey][]*predictor.Outcome) []*model.PredictAccuracy {
+	rows := make([]*model.PredictAccuracy, 0, len(outcomes))
+	for key, list := range outcomes {
+		m := predictor.Evaluate(list)
+		rows = append(rows, &model.PredictAccuracy{
+			Asset:   asset,
+			TsCode:  key.TsCode,
+			Model:   key.Model,
+			Horizon: key.Horizon,
+			Samples: m.Samples,
+			HitRate: m.HitRate,
+			MAE:     m.MAE,
+			MAPE:    m.MAPE,
+		})
+	}
+	return rows
+}
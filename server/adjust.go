@@ -0,0 +1,57 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"financia/public"
+	"financia/public/db/dao"
+	"financia/public/db/model"
+	"financia/server/tushare"
+)
+
+// AdjustStockData 将日线数据按 adj 复权，none 或空字符串不做处理
+// 本地复权因子不能覆盖 list 的起始日期时（例如只有每日任务拉取的近期因子），从 tushare 拉取该股票的全部因子
+func AdjustStockData(ctx context.Context, tsCode string, list []*model.StockData, adj string) error {
+	if adj == "" || adj == public.AdjNone || len(list) == 0 {
+		return nil
+	}
+
+	factors, err := dao.GetStockAdjFactors(ctx, tsCode)
+	if err != nil {
+		return err
+	}
+
+	first := list[0].TradeDate
+	for _, v := range list {
+		if v.TradeDate.Before(first) {
+			first = v.TradeDate
+		}
+	}
+
+	if len(factors) == 0 || factors[0].TradeDate.After(first) {
+		factors, err = tushare.AdjFactor(ctx, &tushare.DailyReq{
+			TsCode: tsCode,
+		})
+		if errors.Is(err, tushare.ErrEmptyResult) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := dao.UpsertStockAdjFactor(ctx, factors); err != nil {
+			return err
+		}
+		factors, err = dao.GetStockAdjFactors(ctx, tsCode)
+		if err != nil {
+			return err
+		}
+	}
+
+	base := 1.0
+	if adj == public.AdjQfq && len(factors) > 0 {
+		base = factors[len(factors)-1].AdjFactor
+	}
+	model.AdjustStockData(list, factors, base)
+
+	return nil
+}
@@ -0,0 +1,169 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"financia/public"
+	"financia/public/db/dao"
+	"financia/public/db/model"
+	"financia/server/alert"
+	"financia/server/forecast"
+	"financia/server/notify"
+	"financia/server/predictor"
+	"fmt"
+	"go.uber.org/zap"
+	"sort"
+	"time"
+)
+
+// alertInput 一个代码最新的收盘数据与预测值
+type alertInput struct {
+	*alert.Input
+	TradeDate time.Time
+}
+
+// DailyAlert 收盘数据入库后判断全部启用的规则，触发的提醒按用户合并为一条消息发送
+// 冷却期内或上次提醒后没有新交易日数据的规则跳过
+// runAt 为定时任务的触发时间，作为上次提醒时间保存，不受入库耗时的影响，每天触发的规则间隔稳定
+func DailyAlert(runAt time.Time) {
+	ctx := context.Background()
+	rules, err := dao.GetEnabledAlertRules(ctx)
+	if err != nil {
+		zap.S().Errorf("[DailyAlert] [GetEnabledAlertRules] [err] = %s", err.Error())
+		return
+	}
+
+	// 同一代码的规则共用一次查询
+	var (
+		codes  []string
+		groups = make(map[string][]*model.AlertRule)
+	)
+	for _, v := range rules {
+		key := v.Asset + ":" + v.TsCode
+		if _, ok := groups[key]; !ok {
+			codes = append(codes, key)
+		}
+		groups[key] = append(groups[key], v)
+	}
+
+	fired := make(map[int64][]*model.AlertFire)
+	var users []int64
+	for _, key := range codes {
+		group := groups[key]
+		lookback := 0
+		for _, v := range group {
+			lookback = max(lookback, alert.Lookback(alertRule(v)))
+		}
+		input, err := loadAlertInput(ctx, group[0], lookback)
+		if err != nil {
+			zap.S().Errorf("[DailyAlert] [loadAlertInput] [%s] [err] = %s", key, err.Error())
+			continue
+		}
+
+		for _, v := range group {
+			if alert.Cooling(v.LastFiredAt, time.Duration(v.Cooldown)*time.Hour, runAt) {
+				continue
+			}
+			if v.LastTradeDate != nil && !input.TradeDate.After(*v.LastTradeDate) {
+				continue
+			}
+			rule := alertRule(v)
+			result, err := alert.Evaluate(rule, input.Input)
+			if errors.Is(err, alert.ErrNoData) {
+				continue
+			}
+			if err != nil {
+				zap.S().Errorf("[DailyAlert] [Evaluate] [rule %d] [err] = %s", v.Id, err.Error())
+				continue
+			}
+			if !result.Fired {
+				continue
+			}
+
+			fire := &model.AlertFire{
+				RuleId:    v.Id,
+				UserId:    v.UserId,
+				TradeDate: input.TradeDate,
+				Value:     result.Value,
+				Message:   fmt.Sprintf("%s（%s）%s", v.Name, v.TsCode, alert.Describe(rule, result.Value)),
+			}
+			if err := dao.CreateAlertFire(ctx, fire, runAt); err != nil {
+				zap.S().Errorf("[DailyAlert] [CreateAlertFire] [rule %d] [err] = %s", v.Id, err.Error())
+				continue
+			}
+			if _, ok := fired[v.UserId]; !ok {
+				users = append(users, v.UserId)
+			}
+			fired[v.UserId] = append(fired[v.UserId], fire)
+		}
+	}
+
+	for _, userId := range users {
+		user, err := dao.GetUser(ctx, userId)
+		if err != nil {
+			zap.S().Errorf("[DailyAlert] [GetUser] [user %d] [err] = %s", userId, err.Error())
+			continue
+		}
+		data := &notify.AlertData{}
+		for _, v := range fired[userId] {
+			data.Items = append(data.Items, &notify.AlertItem{Date: v.TradeDate.Format(time.DateOnly), Message: v.Message})
+		}
+		if err := Notify(ctx, userId, user.Email, notify.KindAlert, data); err != nil {
+			zap.S().Errorf("[DailyAlert] [Notify] [user %d] [err] = %s", userId, err.Error())
+		}
+	}
+}
+
+func alertRule(v *model.AlertRule) *alert.Rule {
+	return &alert.Rule{Type: v.Type, Threshold: v.Threshold, Period: v.Period}
+}
+
+// loadAlertInput 最近 lookback 个交易日的收盘价与缓存的预测值，股票使用前复权价格
+func loadAlertInput(ctx context.Context, rule *model.AlertRule, lookback int) (*alertInput, error) {
+	var (
+		dates  []time.Time
+		closes []float64
+		path   []*predictor.Forecast
+	)
+	switch rule.Asset {
+	case public.AssetStock:
+		list, err := dao.GetStockDataLimit(ctx, rule.TsCode, lookback)
+		if err != nil {
+			return nil, err
+		}
+		if err := AdjustStockData(ctx, rule.TsCode, list, public.AdjQfq); err != nil {
+			return nil, err
+		}
+		sort.Slice(list, func(i, j int) bool {
+			return list[i].TradeDate.Before(list[j].TradeDate)
+		})
+		for _, v := range list {
+			dates, closes = append(dates, v.TradeDate), append(closes, v.Close)
+		}
+		if path, err = forecast.CachedStock(ctx, rule.RefId); err != nil {
+			return nil, err
+		}
+	case public.AssetFund:
+		list, err := dao.GetFundDataLimit(ctx, rule.TsCode, lookback)
+		if err != nil {
+			return nil, err
+		}
+		sort.Slice(list, func(i, j int) bool {
+			return list[i].TradeDate.Before(list[j].TradeDate)
+		})
+		for _, v := range list {
+			dates, closes = append(dates, v.TradeDate), append(closes, v.Close)
+		}
+		if path, err = forecast.CachedFund(ctx, rule.RefId); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown asset %q", rule.Asset)
+	}
+
+	input := &alertInput{Input: &alert.Input{Closes: closes}, TradeDate: dates[len(dates)-1]}
+	if path != nil {
+		input.Predict = &path[0].Val
+	}
+	return input, nil
+}
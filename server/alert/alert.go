@@ -0,0 +1,142 @@
+// Package alert 按最新收盘数据判断提醒规则是否触发
+//
+// 股票的收盘价使用前复权，最新一天与实际价格相同，穿越、涨跌幅与 RSI 不受除权影响；
+// 缓存的预测值同样基于前复权数据。
+package alert
+
+import (
+	"errors"
+	"financia/util/indicator"
+	"fmt"
+	"math"
+	"time"
+)
+
+// 规则类型
+const (
+	TypeCrossAbove    = "cross_above"    // 收盘价由下向上穿过 Threshold
+	TypeCrossBelow    = "cross_below"    // 收盘价由上向下穿过 Threshold
+	TypePctChange     = "pct_change"     // 当日涨跌幅的绝对值不低于 Threshold%
+	TypePredictChange = "predict_change" // 下一交易日预测涨跌幅的绝对值不低于 Threshold%
+	TypeRSIAbove      = "rsi_above"      // RSI 不低于 Threshold
+	TypeRSIBelow      = "rsi_below"      // RSI 不高于 Threshold
+)
+
+// Types 支持的规则类型
+var Types = []string{TypeCrossAbove, TypeCrossBelow, TypePctChange, TypePredictChange, TypeRSIAbove, TypeRSIBelow}
+
+// DefaultRSIPeriod 未指定时的 RSI 周期
+const DefaultRSIPeriod = 14
+
+// DefaultCooldown 未指定时同一规则两次提醒的最短间隔
+const DefaultCooldown = 24 * time.Hour
+
+// ErrNoData 收盘数据不足以计算，或没有预测值
+var ErrNoData = errors.New("alert: not enough data")
+
+// Rule 一条提醒规则
+type Rule struct {
+	Type      string
+	Threshold float64
+	Period    int // RSI 周期
+}
+
+// Input 判断规则使用的数据
+type Input struct {
+	Closes  []float64 // 按日期升序
+	Predict *float64  // 下一交易日的预测收盘价，未缓存时为空
+}
+
+// Result 判断结果，Value 为与阈值比较的值：收盘价、涨跌幅（%）或 RSI
+type Result struct {
+	Fired bool
+	Value float64
+}
+
+// Lookback 判断规则需要的收盘价数量，RSI 多取几个周期使平滑结果稳定
+func Lookback(r *Rule) int {
+	switch r.Type {
+	case TypeRSIAbove, TypeRSIBelow:
+		return period(r)*4 + 1
+	case TypePredictChange:
+		return 1
+	default:
+		return 2
+	}
+}
+
+func period(r *Rule) int {
+	if r.Period > 0 {
+		return r.Period
+	}
+	return DefaultRSIPeriod
+}
+
+// Evaluate 按最新一天的数据判断规则是否触发
+func Evaluate(r *Rule, in *Input) (*Result, error) {
+	n := len(in.Closes)
+	if n < Lookback(r) || (n > 0 && in.Closes[n-1] <= 0) {
+		return nil, ErrNoData
+	}
+	last := in.Closes[n-1]
+
+	switch r.Type {
+	case TypeCrossAbove:
+		return &Result{Fired: in.Closes[n-2] < r.Threshold && last >= r.Threshold, Value: last}, nil
+	case TypeCrossBelow:
+		return &Result{Fired: in.Closes[n-2] > r.Threshold && last <= r.Threshold, Value: last}, nil
+	case TypePctChange:
+		prev := in.Closes[n-2]
+		if prev <= 0 {
+			return nil, ErrNoData
+		}
+		pct := (last/prev - 1) * 100
+		return &Result{Fired: math.Abs(pct) >= r.Threshold, Value: pct}, nil
+	case TypePredictChange:
+		if in.Predict == nil {
+			return nil, ErrNoData
+		}
+		pct := (*in.Predict/last - 1) * 100
+		return &Result{Fired: math.Abs(pct) >= r.Threshold, Value: pct}, nil
+	case TypeRSIAbove, TypeRSIBelow:
+		line := indicator.RSI(in.Closes, period(r))
+		rsi := line[len(line)-1]
+		if math.IsNaN(rsi) {
+			return nil, ErrNoData
+		}
+		if r.Type == TypeRSIAbove {
+			return &Result{Fired: rsi >= r.Threshold, Value: rsi}, nil
+		}
+		return &Result{Fired: rsi <= r.Threshold, Value: rsi}, nil
+	default:
+		return nil, fmt.Errorf("alert: unknown type %q", r.Type)
+	}
+}
+
+// cooldownSlack 定时任务触发时间的误差，间隔与冷却时间相差不超过该值时视为已过冷却期
+const cooldownSlack = 5 * time.Minute
+
+// Cooling 上次提醒后是否仍在冷却期内，从未提醒时为 false
+func Cooling(lastFired *time.Time, cooldown time.Duration, now time.Time) bool {
+	return lastFired != nil && now.Sub(*lastFired) < cooldown-cooldownSlack
+}
+
+// Describe 触发时的说明文字
+func Describe(r *Rule, value float64) string {
+	switch r.Type {
+	case TypeCrossAbove:
+		return fmt.Sprintf("收盘价 %.2f 向上突破 %.2f", value, r.Threshold)
+	case TypeCrossBelow:
+		return fmt.Sprintf("收盘价 %.2f 向下跌破 %.2f", value, r.Threshold)
+	case TypePctChange:
+		return fmt.Sprintf("当日涨跌幅 %.2f%%，超过 ±%.2f%%", value, r.Threshold)
+	case TypePredictChange:
+		return fmt.Sprintf("预测下一交易日涨跌幅 %.2f%%，超过 ±%.2f%%", value, r.Threshold)
+	case TypeRSIAbove:
+		return fmt.Sprintf("RSI(%d) 为 %.2f，高于 %.2f", period(r), value, r.Threshold)
+	case TypeRSIBelow:
+		return fmt.Sprintf("RSI(%d) 为 %.2f，低于 %.2f", period(r), value, r.Threshold)
+	default:
+		return fmt.Sprintf("%s %.2f", r.Type, value)
+	}
+}
@@ -0,0 +1,111 @@
+package alert
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestEvaluateCross(t *testing.T) {
+	cases := []struct {
+		rule   *Rule
+		closes []float64
+		fired  bool
+	}{
+		{&Rule{Type: TypeCrossAbove, Threshold: 10}, []float64{9.5, 10}, true},
+		{&Rule{Type: TypeCrossAbove, Threshold: 10}, []float64{10, 10.5}, false}, // 前一天已在阈值之上
+		{&Rule{Type: TypeCrossBelow, Threshold: 10}, []float64{10.5, 9.9}, true},
+		{&Rule{Type: TypeCrossBelow, Threshold: 10}, []float64{9.8, 9.7}, false},
+	}
+	for i, c := range cases {
+		r, err := Evaluate(c.rule, &Input{Closes: c.closes})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.Fired != c.fired || r.Value != c.closes[len(c.closes)-1] {
+			t.Errorf("case %d: got %+v, want fired %v", i, r, c.fired)
+		}
+	}
+}
+
+func TestEvaluatePct(t *testing.T) {
+	rule := &Rule{Type: TypePctChange, Threshold: 5}
+	r, err := Evaluate(rule, &Input{Closes: []float64{10, 9.4}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !r.Fired || math.Abs(r.Value+6) > 1e-9 {
+		t.Errorf("got %+v, want fired at -6%%", r)
+	}
+
+	predict := 10.3
+	rule = &Rule{Type: TypePredictChange, Threshold: 5}
+	r, err = Evaluate(rule, &Input{Closes: []float64{10}, Predict: &predict})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Fired || math.Abs(r.Value-3) > 1e-9 {
+		t.Errorf("got %+v, want not fired at 3%%", r)
+	}
+	if _, err := Evaluate(rule, &Input{Closes: []float64{10}}); !errors.Is(err, ErrNoData) {
+		t.Errorf("missing predict: got %v, want ErrNoData", err)
+	}
+}
+
+func TestEvaluateRSI(t *testing.T) {
+	rule := &Rule{Type: TypeRSIAbove, Threshold: 70, Period: 3}
+	if _, err := Evaluate(rule, &Input{Closes: []float64{1, 2, 3}}); !errors.Is(err, ErrNoData) {
+		t.Fatalf("short input: got %v, want ErrNoData", err)
+	}
+
+	// 一路上涨，RSI 为 100
+	closes := make([]float64, Lookback(rule))
+	for i := range closes {
+		closes[i] = float64(10 + i)
+	}
+	r, err := Evaluate(rule, &Input{Closes: closes})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !r.Fired || r.Value != 100 {
+		t.Errorf("got %+v, want fired at 100", r)
+	}
+	r, _ = Evaluate(&Rule{Type: TypeRSIBelow, Threshold: 30, Period: 3}, &Input{Closes: closes})
+	if r.Fired {
+		t.Errorf("rsi_below fired on rising prices: %+v", r)
+	}
+}
+
+func TestCooling(t *testing.T) {
+	now := time.Date(2024, 3, 1, 17, 30, 0, 0, time.UTC)
+	last := now.Add(-23 * time.Hour)
+	if Cooling(nil, DefaultCooldown, now) {
+		t.Error("never fired should not be cooling")
+	}
+	if !Cooling(&last, DefaultCooldown, now) {
+		t.Error("fired 23h ago should be cooling")
+	}
+	if Cooling(&last, 12*time.Hour, now) {
+		t.Error("fired 23h ago with 12h cooldown should not be cooling")
+	}
+}
+
+// 每天同一时间运行，触发时间有毫秒级误差，每天触发的规则在默认冷却时间下每天都提醒
+func TestCoolingDailyRuns(t *testing.T) {
+	first := time.Date(2024, 3, 1, 17, 30, 0, 3e6, time.UTC)
+	last := &first
+	for day := 1; day <= 5; day++ {
+		runAt := time.Date(2024, 3, 1+day, 17, 30, 0, int(day%2)*1e6, time.UTC)
+		if Cooling(last, DefaultCooldown, runAt) {
+			t.Fatalf("day %d: run at %v cooling after %v", day, runAt, *last)
+		}
+		last = &runAt
+	}
+
+	// 同一天再次运行仍在冷却期内
+	again := last.Add(2 * time.Hour)
+	if !Cooling(last, DefaultCooldown, again) {
+		t.Error("second run on the same day should be cooling")
+	}
+}
@@ -0,0 +1,259 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"financia/config"
+	"financia/public"
+	"financia/public/db/connector"
+	"financia/public/db/dao"
+	"financia/public/db/model"
+	"financia/server/forecast"
+	"financia/server/llm"
+	"financia/server/predictor"
+	"financia/util"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/cast"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"sort"
+	"sync"
+	"time"
+)
+
+// aiDailyQuota 未配置时每个用户每天触发生成的次数
+const aiDailyQuota = 20
+
+// ErrQuotaExceeded 用户当日的生成次数已用完
+var ErrQuotaExceeded = errors.New("ai analysis quota exceeded")
+
+// Analysis 一次 AI 分析请求
+type Analysis struct {
+	UserId    int64
+	Asset     string
+	TradeDate time.Time // 输入数据的最后交易日
+	Sections  []string  // 附带的参考资料，见 ContextSections
+	Data      *llm.PromptData
+}
+
+// Analyze 推送 AI 分析：已有同一交易日、提示词版本与模型的分析时直接回放，否则调用模型生成并保存
+// 返回错误时尚未向前端写入任何内容；开始推送后的错误以 error 事件告知前端，只记录日志
+// 不带预测值的分析不完整，不保存，预测完成后再次请求时重新生成
+func Analyze(c *gin.Context, a *Analysis) error {
+	version, err := llm.PromptVersion(a.Asset, a.Sections)
+	if err != nil {
+		return err
+	}
+	p := llm.Default()
+
+	if a.Data.Predict != nil {
+		// 同一分析只由一个请求生成，其余请求等待生成结束后回放，生成失败时由其中一个请求重新生成
+		key := fmt.Sprintf("%s:%s:%s:%s:%s", a.Asset, a.Data.TsCode, a.TradeDate.Format(time.DateOnly), version, p.Model())
+		for {
+			if ok, err := replayAnalysis(c, a, version, p.Model()); ok || err != nil {
+				return err
+			}
+			g, leader := joinGeneration(key)
+			if leader {
+				defer finishGeneration(key, g)
+				// 上一次生成可能刚在查询之后结束
+				if ok, err := replayAnalysis(c, a, version, p.Model()); ok || err != nil {
+					return err
+				}
+				break
+			}
+			select {
+			case <-g.done:
+			case <-c.Request.Context().Done():
+				return c.Request.Context().Err()
+			}
+		}
+	}
+
+	if err := takeAiQuota(c, a.UserId); err != nil {
+		return err
+	}
+
+	// 参考资料只在需要生成时获取
+	a.Data.Context = buildContext(c, a.Data.TsCode, a.Sections)
+	messages, err := llm.Render(a.Asset, a.Data)
+	if err != nil {
+		releaseAiQuota(c, a.UserId)
+		return err
+	}
+
+	content, err := llm.Stream(c, p, &llm.Request{
+		User:     cast.ToString(a.UserId),
+		Messages: messages,
+	})
+	if err != nil {
+		// 生成失败不计入次数
+		releaseAiQuota(c, a.UserId)
+		zap.S().Errorf("[Analyze] [llm.Stream] [err] = %s", err.Error())
+		return nil
+	}
+	// 连接中断等原因的不完整回复已返回错误；空回复同样不保存
+	if a.Data.Predict == nil || content == "" {
+		return nil
+	}
+
+	analysis := &model.AiAnalysis{
+		Asset:         a.Asset,
+		TsCode:        a.Data.TsCode,
+		TradeDate:     a.TradeDate,
+		PromptVersion: version,
+		Model:         p.Model(),
+		Name:          a.Data.Name,
+		Content:       content,
+	}
+	if err := dao.CreateAiAnalysis(c, analysis); err != nil {
+		zap.S().Errorf("[Analyze] [CreateAiAnalysis] [err] = %s", err.Error())
+		return nil
+	}
+	saveAnalysisView(c, a.UserId, analysis.Id)
+	return nil
+}
+
+// LoadPromptData 最近 30 个交易日的收盘价与缓存的预测值，同时返回最后交易日
+// 只使用缓存的预测值，未缓存时提交预测任务，本次分析不带预测值
+func LoadPromptData(ctx context.Context, asset string, id int) (*llm.PromptData, time.Time, error) {
+	var (
+		name, tsCode string
+		bars         []*util.Bar
+		path         []*predictor.Forecast
+	)
+	switch asset {
+	case public.AssetStock:
+		info, err := dao.GetStockInfo(ctx, id)
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+		limit30, err := dao.GetStockDataLimit30(ctx, info.TsCode)
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+		// 与缓存的预测结果一致，使用前复权数据
+		if err := AdjustStockData(ctx, info.TsCode, limit30, public.AdjQfq); err != nil {
+			return nil, time.Time{}, err
+		}
+		if path, err = forecast.CachedStock(ctx, id); err != nil {
+			return nil, time.Time{}, err
+		}
+		name, tsCode, bars = info.Name, info.TsCode, model.StockBars(limit30)
+	case public.AssetFund:
+		info, err := dao.GetFundInfo(ctx, id)
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+		limit30, err := dao.GetFundDataLimit30(ctx, info.TsCode)
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+		if path, err = forecast.CachedFund(ctx, id); err != nil {
+			return nil, time.Time{}, err
+		}
+		name, tsCode, bars = info.Name, info.TsCode, model.FundBars(limit30)
+	default:
+		return nil, time.Time{}, fmt.Errorf("unknown asset %q", asset)
+	}
+	if len(bars) == 0 {
+		return nil, time.Time{}, fmt.Errorf("%s %s: no data", asset, tsCode)
+	}
+
+	sort.Slice(bars, func(i, j int) bool {
+		return bars[i].Date.Before(bars[j].Date)
+	})
+	closes := make([]float64, 0, len(bars))
+	for _, v := range bars {
+		closes = append(closes, v.Close)
+	}
+
+	var predict *float64
+	if path != nil {
+		predict = &path[0].Val
+	} else if _, err := EnqueuePredict(ctx, asset, id, tsCode); err != nil {
+		zap.S().Errorf("[LoadPromptData] [EnqueuePredict] [err] = %s", err.Error())
+	}
+	return llm.NewPromptData(asset, name, tsCode, closes, predict), bars[len(bars)-1].Date, nil
+}
+
+// replayAnalysis 已有保存的分析时记录查看并回放
+func replayAnalysis(c *gin.Context, a *Analysis, version, model string) (bool, error) {
+	analysis, err := dao.GetAiAnalysisByKey(c, a.Asset, a.Data.TsCode, a.TradeDate, version, model)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	saveAnalysisView(c, a.UserId, analysis.Id)
+	llm.Replay(c, analysis.Content)
+	return true, nil
+}
+
+// generation 正在生成的分析，生成结束后关闭 done
+type generation struct {
+	done chan struct{}
+}
+
+var (
+	generationMu sync.Mutex
+	generations  = make(map[string]*generation)
+)
+
+// joinGeneration 加入键对应的生成，没有正在进行的生成时由调用方负责生成并返回 leader 为 true
+func joinGeneration(key string) (*generation, bool) {
+	generationMu.Lock()
+	defer generationMu.Unlock()
+	if g, ok := generations[key]; ok {
+		return g, false
+	}
+	g := &generation{done: make(chan struct{})}
+	generations[key] = g
+	return g, true
+}
+
+func finishGeneration(key string, g *generation) {
+	generationMu.Lock()
+	delete(generations, key)
+	generationMu.Unlock()
+	close(g.done)
+}
+
+func saveAnalysisView(c *gin.Context, userId, analysisId int64) {
+	if err := dao.SaveAiAnalysisView(c, userId, analysisId); err != nil {
+		zap.S().Errorf("[Analyze] [SaveAiAnalysisView] [err] = %s", err.Error())
+	}
+}
+
+func aiQuotaKey(userId int64) string {
+	return fmt.Sprintf(public.RedisKeyAiQuota, userId, time.Now().Format(time.DateOnly))
+}
+
+// takeAiQuota 占用一次当日生成次数，次数在次日零点重置
+func takeAiQuota(c *gin.Context, userId int64) error {
+	quota := config.Configs.LLM.DailyQuota
+	if quota <= 0 {
+		quota = aiDailyQuota
+	}
+
+	rdb := connector.GetRedis().WithContext(c)
+	key := aiQuotaKey(userId)
+	n, err := rdb.Incr(c, key).Result()
+	if err != nil {
+		return err
+	}
+	if n == 1 {
+		rdb.Expire(c, key, time.Duration(util.SecondsUntilMidnight())*time.Second)
+	}
+	if n > int64(quota) {
+		rdb.Decr(c, key)
+		return ErrQuotaExceeded
+	}
+	return nil
+}
+
+func releaseAiQuota(c *gin.Context, userId int64) {
+	connector.GetRedis().WithContext(c).Decr(c, aiQuotaKey(userId))
+}
@@ -0,0 +1,168 @@
+package server
+
+import (
+	"context"
+	"financia/public"
+	"financia/public/db/connector"
+	"financia/public/db/dao"
+	"financia/public/db/model"
+	"financia/server/llm"
+	"financia/server/tushare"
+	"fmt"
+	"github.com/spf13/cast"
+	"go.uber.org/zap"
+	"slices"
+	"sort"
+)
+
+// peerLimit 同行业公司最多取的数量
+const peerLimit = 20
+
+// contextSections 各资产类型支持的参考资料，顺序即放入提示词与截断的优先级
+var contextSections = map[string][]string{
+	public.AssetStock: {llm.SectionMacro, llm.SectionForecast, llm.SectionIncome, llm.SectionHolder, llm.SectionPeer},
+	public.AssetFund:  {llm.SectionMacro},
+}
+
+// ContextSections 从请求打开的开关中取资产类型支持的参考资料，按优先级排列
+func ContextSections(asset string, enabled map[string]bool) []string {
+	var list []string
+	for _, name := range contextSections[asset] {
+		if enabled[name] {
+			list = append(list, name)
+		}
+	}
+	return list
+}
+
+// peer 同行业公司
+type peer struct {
+	Name   string   `json:"name"`
+	TsCode string   `json:"tsCode"`
+	Close  *float64 `json:"close,omitempty"` // 最新收盘价，未缓存时为空
+}
+
+// buildContext 获取参考资料并按预算截断，单项获取失败只记录日志并跳过
+func buildContext(ctx context.Context, tsCode string, sections []string) []*llm.Section {
+	list := make([]*llm.Section, 0, len(sections))
+	for _, name := range sections {
+		section, err := fetchSection(ctx, name, tsCode)
+		if err != nil {
+			zap.S().Errorf("[buildContext] [%s %s] [err] = %s", name, tsCode, err.Error())
+			continue
+		}
+		if len(section.Rows) > 0 {
+			list = append(list, section)
+		}
+	}
+	return llm.Fit(list, llm.ContextTokens())
+}
+
+func fetchSection(ctx context.Context, name, tsCode string) (*llm.Section, error) {
+	section := &llm.Section{Name: name}
+	switch name {
+	case llm.SectionIncome:
+		section.Title = "利润表（按公告日期倒序）"
+		list, err := tushare.StockIncome(ctx, tsCode)
+		if err != nil {
+			return nil, err
+		}
+		sort.Slice(list, func(i, j int) bool {
+			return list[i].AnnDate > list[j].AnnDate
+		})
+		section.Rows = rows(list)
+	case llm.SectionForecast:
+		section.Title = "业绩预告（按公告日期倒序）"
+		list, err := tushare.StockForecast(ctx, tsCode)
+		if err != nil {
+			return nil, err
+		}
+		sort.Slice(list, func(i, j int) bool {
+			return list[i].AnnDate > list[j].AnnDate
+		})
+		section.Rows = rows(list)
+	case llm.SectionHolder:
+		section.Title = "前十大股东（最新一期，holdChange 为较上期的变动）"
+		list, err := tushare.StockHolderTop10(ctx, tsCode)
+		if err != nil {
+			return nil, err
+		}
+		var latest string
+		for _, v := range list {
+			latest = max(latest, v.AnnDate)
+		}
+		list = slices.DeleteFunc(list, func(v *tushare.StockTop10Resp) bool {
+			return v.AnnDate != latest
+		})
+		sort.SliceStable(list, func(i, j int) bool {
+			return list[i].HoldRatio > list[j].HoldRatio
+		})
+		section.Rows = rows(list)
+	case llm.SectionPeer:
+		info, err := dao.GetStockInfoByTsCode(ctx, tsCode)
+		if err != nil {
+			return nil, err
+		}
+		if info.Industry == "" {
+			return section, nil
+		}
+		section.Title = fmt.Sprintf("同行业（%s）公司", info.Industry)
+		list, err := dao.GetStockPeers(ctx, info.Industry, tsCode, peerLimit)
+		if err != nil {
+			return nil, err
+		}
+		section.Rows = peers(ctx, list)
+	case llm.SectionMacro:
+		section.Title = "宏观数据（最新一期 SHIBOR 与 CPI）"
+		shibor, err := dao.CachedList(ctx, public.RedisKeyShiborDaily, tushare.EconomicsShibor)
+		if err != nil {
+			return nil, err
+		}
+		if v := latestBy(shibor, func(v *tushare.EconomicsShiborResp) string { return v.Date }); v != nil {
+			section.Rows = append(section.Rows, v)
+		}
+		cpi, err := dao.CachedList(ctx, public.RedisKeyCnCpiDaily, tushare.EconomicsCnCPI)
+		if err != nil {
+			return nil, err
+		}
+		if v := latestBy(cpi, func(v *tushare.EconomicsCnCPIResp) string { return v.Month }); v != nil {
+			section.Rows = append(section.Rows, v)
+		}
+	default:
+		return nil, fmt.Errorf("unknown section %q", name)
+	}
+	return section, nil
+}
+
+func rows[T any](list []*T) []any {
+	out := make([]any, len(list))
+	for i, v := range list {
+		out[i] = v
+	}
+	return out
+}
+
+func peers(ctx context.Context, list []*model.StockInfo) []any {
+	rdb := connector.GetRedis().WithContext(ctx)
+	out := make([]any, 0, len(list))
+	for _, v := range list {
+		p := &peer{Name: v.Name, TsCode: v.TsCode}
+		if result, err := rdb.Get(ctx, fmt.Sprintf(public.RedisKeyStockToday, v.TsCode)).Result(); err == nil {
+			closePrice := cast.ToFloat64(result)
+			p.Close = &closePrice
+		}
+		out = append(out, p)
+	}
+	return out
+}
+
+// latestBy 按日期字段取最新的一条
+func latestBy[T any](list []*T, date func(*T) string) *T {
+	var latest *T
+	for _, v := range list {
+		if latest == nil || date(v) > date(latest) {
+			latest = v
+		}
+	}
+	return latest
+}
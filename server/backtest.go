@@ -0,0 +1,133 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"financia/public"
+	"financia/public/db/dao"
+	"financia/public/db/model"
+	"financia/server/backtest"
+	"financia/util"
+	"fmt"
+	"go.uber.org/zap"
+	"time"
+)
+
+const (
+	// backtestWorkers 同时运行的回测任务数
+	backtestWorkers = 2
+	// backtestTimeout 单个回测任务的最长运行时间
+	backtestTimeout = 10 * time.Minute
+)
+
+// ErrBacktestBusy 回测队列已满
+var ErrBacktestBusy = errors.New("backtest queue is full")
+
+var backtestQueue = make(chan int64, 100)
+
+// BacktestWorker 启动回测工作协程，并重新排队上次退出时未完成的任务
+func BacktestWorker() {
+	for i := 0; i < backtestWorkers; i++ {
+		go func() {
+			for id := range backtestQueue {
+				runBacktestJob(id)
+			}
+		}()
+	}
+
+	// 恢复的任务已在库中，队列满时等待工作协程空出位置
+	ids, err := dao.GetUnfinishedBacktestJobs(context.Background())
+	if err != nil {
+		zap.S().Errorf("[BacktestWorker] [GetUnfinishedBacktestJobs] [err] = %s", err.Error())
+	}
+	for _, id := range ids {
+		backtestQueue <- id
+	}
+}
+
+// SubmitBacktest 回测任务入队，队列已满时返回 ErrBacktestBusy
+func SubmitBacktest(id int64) error {
+	select {
+	case backtestQueue <- id:
+		return nil
+	default:
+		return ErrBacktestBusy
+	}
+}
+
+func runBacktestJob(id int64) {
+	ctx, cancel := context.WithTimeout(context.Background(), backtestTimeout)
+	defer cancel()
+
+	job, err := dao.GetBacktestJob(ctx, id)
+	if err != nil {
+		zap.S().Errorf("[runBacktestJob] [GetBacktestJob] [err] = %s", err.Error())
+		return
+	}
+	if err := dao.UpdateBacktestJob(ctx, id, public.JobStatusRunning, "", ""); err != nil {
+		zap.S().Errorf("[runBacktestJob] [UpdateBacktestJob] [err] = %s", err.Error())
+		return
+	}
+
+	report, err := RunBacktest(ctx, job)
+	if err != nil {
+		zap.S().Errorf("[runBacktestJob] [%d] [err] = %s", id, err.Error())
+		if err := dao.UpdateBacktestJob(context.Background(), id, public.JobStatusFailed, err.Error(), ""); err != nil {
+			zap.S().Errorf("[runBacktestJob] [UpdateBacktestJob] [err] = %s", err.Error())
+		}
+		return
+	}
+
+	result, err := json.Marshal(report)
+	if err != nil {
+		zap.S().Errorf("[runBacktestJob] [json.Marshal] [err] = %s", err.Error())
+		return
+	}
+	if err := dao.UpdateBacktestJob(ctx, id, public.JobStatusDone, "", string(result)); err != nil {
+		zap.S().Errorf("[runBacktestJob] [UpdateBacktestJob] [err] = %s", err.Error())
+	}
+}
+
+// RunBacktest 按任务参数加载数据并运行回测，股票使用前复权价格
+func RunBacktest(ctx context.Context, job *model.BacktestJob) (*backtest.Report, error) {
+	var params backtest.Params
+	if err := json.Unmarshal([]byte(job.Params), &params); err != nil {
+		return nil, fmt.Errorf("params: %w", err)
+	}
+	strategy, err := backtest.NewStrategy(&params)
+	if err != nil {
+		return nil, err
+	}
+
+	// 向前多取数据用于策略预热，约 1.5 个自然日对应 1 个交易日
+	from := job.StartDate.AddDate(0, 0, -(strategy.Lookback()*3/2 + 10)).Format(time.DateOnly)
+	end := job.EndDate.Format(time.DateOnly)
+
+	var bars []*util.Bar
+	switch job.Asset {
+	case public.AssetStock:
+		list, err := dao.GetStockData(ctx, job.TsCode, from, end)
+		if err != nil {
+			return nil, err
+		}
+		if err := AdjustStockData(ctx, job.TsCode, list, public.AdjQfq); err != nil {
+			return nil, err
+		}
+		bars = model.StockBars(list)
+	case public.AssetFund:
+		list, err := dao.GetFundData(ctx, job.TsCode, from, end)
+		if err != nil {
+			return nil, err
+		}
+		bars = model.FundBars(list)
+	default:
+		return nil, fmt.Errorf("unknown asset %q", job.Asset)
+	}
+
+	return backtest.Run(ctx, bars, strategy, &backtest.Config{
+		Start:      job.StartDate,
+		Commission: params.Commission,
+		Slippage:   params.Slippage,
+	})
+}
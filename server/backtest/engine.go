@@ -0,0 +1,173 @@
+// Package backtest 逐日回放历史 K 线，按策略的目标仓位模拟交易并统计收益与风险指标
+//
+// 第 i 个交易日收盘后按策略仓位调仓，持有到下一交易日收盘，
+// 调仓按变动的仓位比例扣除手续费与滑点。
+package backtest
+
+import (
+	"context"
+	"errors"
+	"financia/util"
+	"math"
+	"time"
+)
+
+// tradingDays 年化使用的每年交易日数
+const tradingDays = 252
+
+// ErrNotEnoughData 回测区间内的 K 线不足两根
+var ErrNotEnoughData = errors.New("backtest: not enough data")
+
+// Config 回测区间与交易成本
+type Config struct {
+	Start      time.Time // 之前的 K 线只用于策略预热，不参与交易
+	Commission float64   // 手续费率，按成交金额计
+	Slippage   float64   // 滑点，按成交金额的比例计
+}
+
+// Point 权益曲线上的一个交易日
+type Point struct {
+	Date     string  `json:"date"`
+	Equity   float64 `json:"equity"`   // 初始权益为 1
+	Position float64 `json:"position"` // 当日收盘后的仓位
+}
+
+// Report 回测结果
+type Report struct {
+	Strategy    string   `json:"strategy"`
+	Start       string   `json:"start"`
+	End         string   `json:"end"`
+	TotalReturn float64  `json:"totalReturn"`
+	CAGR        float64  `json:"cagr"`
+	MaxDrawdown float64  `json:"maxDrawdown"` // 正数，如 0.2 表示最大回撤 20%
+	Sharpe      float64  `json:"sharpe"`      // 年化，无风险利率取 0
+	Turnover    float64  `json:"turnover"`    // 年化换手，仓位从 0 到 1 再回到 0 计 2
+	Trades      int      `json:"trades"`      // 开仓次数
+	WinRate     float64  `json:"winRate"`     // 盈利的交易占比，未平仓的按最后收盘计算
+	Equity      []*Point `json:"equity"`
+}
+
+// Run 按日期升序的 K 线运行回测
+func Run(ctx context.Context, bars []*util.Bar, s Strategy, cfg *Config) (*Report, error) {
+	first := 0
+	for first < len(bars) && bars[first].Date.Before(cfg.Start) {
+		first++
+	}
+	if len(bars)-first < 2 {
+		return nil, ErrNotEnoughData
+	}
+
+	positions, err := s.Positions(ctx, bars)
+	if err != nil {
+		return nil, err
+	}
+
+	cost := cfg.Commission + cfg.Slippage
+	report := &Report{
+		Strategy: s.Name(),
+		Start:    bars[first].Date.Format(time.DateOnly),
+		End:      bars[len(bars)-1].Date.Format(time.DateOnly),
+		Equity:   make([]*Point, 0, len(bars)-first),
+	}
+
+	var (
+		equity   = 1.0
+		pos      float64
+		turnover float64
+		entry    float64 // 开仓后的权益
+		wins     int
+		returns  = make([]float64, 0, len(bars)-first)
+	)
+	for i := first; i < len(bars); i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		prev := equity
+		if i > first {
+			equity *= 1 + pos*(bars[i].Close/bars[i-1].Close-1)
+		}
+
+		target := position(positions[i])
+		if delta := math.Abs(target - pos); delta > 0 {
+			equity *= 1 - delta*cost
+			turnover += delta
+
+			if pos == 0 {
+				report.Trades++
+				entry = equity
+			} else if target == 0 && equity > entry {
+				wins++
+			}
+		}
+		pos = target
+
+		if i > first {
+			returns = append(returns, equity/prev-1)
+		}
+		report.Equity = append(report.Equity, &Point{
+			Date:     bars[i].Date.Format(time.DateOnly),
+			Equity:   equity,
+			Position: pos,
+		})
+	}
+	// 未平仓的交易按最后收盘计算盈亏
+	if pos > 0 && equity > entry {
+		wins++
+	}
+
+	years := float64(len(returns)) / tradingDays
+	report.TotalReturn = equity - 1
+	if years > 0 && equity > 0 {
+		report.CAGR = math.Pow(equity, 1/years) - 1
+		report.Turnover = turnover / years
+	}
+	report.MaxDrawdown = maxDrawdown(report.Equity)
+	report.Sharpe = sharpe(returns)
+	if report.Trades > 0 {
+		report.WinRate = float64(wins) / float64(report.Trades)
+	}
+
+	return report, nil
+}
+
+// position 仓位限制在 [0, 1]，NaN 视为空仓
+func position(v float64) float64 {
+	if math.IsNaN(v) {
+		return 0
+	}
+	return max(0, min(1, v))
+}
+
+func maxDrawdown(points []*Point) float64 {
+	var peak, dd float64
+	for _, v := range points {
+		peak = max(peak, v.Equity)
+		if peak > 0 {
+			dd = max(dd, 1-v.Equity/peak)
+		}
+	}
+	return dd
+}
+
+func sharpe(returns []float64) float64 {
+	if len(returns) < 2 {
+		return 0
+	}
+
+	var mean float64
+	for _, v := range returns {
+		mean += v
+	}
+	mean /= float64(len(returns))
+
+	var variance float64
+	for _, v := range returns {
+		variance += (v - mean) * (v - mean)
+	}
+	std := math.Sqrt(variance / float64(len(returns)-1))
+	if std == 0 {
+		return 0
+	}
+	return mean / std * math.Sqrt(tradingDays)
+}
@@ -0,0 +1,185 @@
+package backtest
+
+import (
+	"context"
+	"financia/server/predictor"
+	"financia/util"
+	"math"
+	"testing"
+	"time"
+)
+
+func bars(closes ...float64) []*util.Bar {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	list := make([]*util.Bar, len(closes))
+	for i, v := range closes {
+		list[i] = &util.Bar{Date: start.AddDate(0, 0, i), Open: v, High: v, Low: v, Close: v}
+	}
+	return list
+}
+
+// fixed 按给定仓位交易，用于校验引擎的记账
+type fixed []float64
+
+func (fixed) Name() string  { return "fixed" }
+func (fixed) Lookback() int { return 0 }
+
+func (f fixed) Positions(context.Context, []*util.Bar) ([]float64, error) {
+	return f, nil
+}
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestRunBuyAndHold(t *testing.T) {
+	list := bars(10, 11, 12, 9, 13)
+	r, err := Run(context.Background(), list, fixed{1, 1, 1, 1, 1}, &Config{Commission: 0.001, Slippage: 0.001})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := (1 - 0.002) * 1.3
+	if !near(r.TotalReturn, want-1) {
+		t.Errorf("total return = %v, want %v", r.TotalReturn, want-1)
+	}
+	// 12 回撤到 9
+	if !near(r.MaxDrawdown, 0.25) {
+		t.Errorf("max drawdown = %v, want 0.25", r.MaxDrawdown)
+	}
+	if r.Trades != 1 || r.WinRate != 1 {
+		t.Errorf("trades = %d, win rate = %v", r.Trades, r.WinRate)
+	}
+	if len(r.Equity) != 5 || r.Start != "2024-01-01" || r.End != "2024-01-05" {
+		t.Errorf("equity = %d points, %s ~ %s", len(r.Equity), r.Start, r.End)
+	}
+	// 4 个交易日的收益，换手 1 次
+	if !near(r.Turnover, 1/(4.0/tradingDays)) {
+		t.Errorf("turnover = %v", r.Turnover)
+	}
+	if cagr := math.Pow(want, tradingDays/4.0) - 1; math.Abs(r.CAGR/cagr-1) > 1e-9 {
+		t.Errorf("cagr = %v", r.CAGR)
+	}
+}
+
+func TestRunTrades(t *testing.T) {
+	// 第 1 天收盘买入，第 3 天收盘卖出（亏损），第 4 天收盘再买入并持有到最后（盈利）
+	list := bars(10, 10, 9, 9, 9, 12)
+	r, err := Run(context.Background(), list, fixed{0, 1, 1, 0, 1, 1}, &Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := 1.0 * 9 / 10 * 12 / 9
+	if !near(r.TotalReturn, want-1) {
+		t.Errorf("total return = %v, want %v", r.TotalReturn, want-1)
+	}
+	if r.Trades != 2 {
+		t.Errorf("trades = %d, want 2", r.Trades)
+	}
+	if !near(r.WinRate, 0.5) {
+		t.Errorf("win rate = %v, want 0.5", r.WinRate)
+	}
+	if p := r.Equity[3].Position; p != 0 {
+		t.Errorf("position on day 3 = %v, want 0", p)
+	}
+}
+
+func TestRunWarmup(t *testing.T) {
+	list := bars(10, 11, 12, 13, 14)
+	r, err := Run(context.Background(), list, fixed{1, 1, 1, 1, 1}, &Config{Start: list[2].Date})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 预热期不交易，区间从第 3 天开始
+	if r.Start != "2024-01-03" || len(r.Equity) != 3 || !near(r.TotalReturn, 14.0/12-1) {
+		t.Errorf("start = %s, points = %d, return = %v", r.Start, len(r.Equity), r.TotalReturn)
+	}
+
+	if _, err := Run(context.Background(), list, fixed{1, 1, 1, 1, 1}, &Config{Start: list[4].Date}); err != ErrNotEnoughData {
+		t.Errorf("err = %v, want ErrNotEnoughData", err)
+	}
+}
+
+func TestMACross(t *testing.T) {
+	list := bars(10, 11, 12, 13, 14, 13, 12, 11, 10, 9)
+	s, err := NewStrategy(&Params{Strategy: StrategyMACross, Fast: 2, Slow: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	positions, _ := s.Positions(context.Background(), list)
+
+	for i := 0; i < 3; i++ {
+		if !math.IsNaN(positions[i]) {
+			t.Errorf("position %d = %v, want NaN", i, positions[i])
+		}
+	}
+	if positions[3] != 1 || positions[4] != 1 {
+		t.Errorf("rising positions = %v", positions[3:5])
+	}
+	if positions[7] != 0 || positions[9] != 0 {
+		t.Errorf("falling positions = %v", positions[6:])
+	}
+}
+
+func TestRSIHold(t *testing.T) {
+	// 连续下跌触发买入，随后反弹到超买前维持持仓
+	list := bars(20, 19, 18, 17, 16, 15, 15.5, 16, 17, 18, 19, 20)
+	s := &RSI{Period: 3, Lower: 30, Upper: 90}
+	positions, _ := s.Positions(context.Background(), list)
+
+	if positions[5] != 1 {
+		t.Errorf("position after decline = %v, want 1", positions[5])
+	}
+	if positions[6] != 1 {
+		t.Errorf("position on rebound = %v, want hold 1", positions[6])
+	}
+	if positions[11] != 0 {
+		t.Errorf("position when overbought = %v, want 0", positions[11])
+	}
+}
+
+func TestPredictStrategy(t *testing.T) {
+	list := bars(1, 2, 3, 4, 5, 6, 7, 8)
+
+	// 线性外推在上升趋势中一直看涨
+	s := &Predict{Predictor: predictor.Linear{}, Window: 3}
+	positions, err := s.Positions(context.Background(), list)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !math.IsNaN(positions[1]) || positions[2] != 1 || positions[7] != 1 {
+		t.Errorf("linear positions = %v", positions)
+	}
+
+	// 最后收盘价不高于当天收盘，始终空仓
+	s = &Predict{Predictor: predictor.Naive{}, Window: 3}
+	r, err := Run(context.Background(), list, s, &Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Trades != 0 || r.TotalReturn != 0 || r.Strategy != "predict:naive" {
+		t.Errorf("naive = %+v", r)
+	}
+}
+
+func TestNewStrategyDefaults(t *testing.T) {
+	p := &Params{Strategy: StrategyRSI}
+	if _, err := NewStrategy(p); err != nil {
+		t.Fatal(err)
+	}
+	if p.Period != 14 || p.Lower != 30 || p.Upper != 70 {
+		t.Errorf("defaults = %+v", p)
+	}
+
+	for _, p := range []*Params{
+		{Strategy: "unknown"},
+		{Strategy: StrategyMACross, Fast: 20, Slow: 5},
+		{Strategy: StrategyPredict, Predictor: "missing"},
+		{Strategy: StrategyPredict, Predictor: "python"},
+	} {
+		if _, err := NewStrategy(p); err == nil {
+			t.Errorf("%+v: expected error", p)
+		}
+	}
+}
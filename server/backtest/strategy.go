@@ -0,0 +1,220 @@
+package backtest
+
+import (
+	"context"
+	"errors"
+	"financia/server/predictor"
+	"financia/util"
+	"financia/util/indicator"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+)
+
+// 策略名称
+const (
+	StrategyPredict = "predict" // 预测上涨时持有，否则空仓
+	StrategyMACross = "macross" // 快线在慢线之上时持有
+	StrategyRSI     = "rsi"     // 超卖买入、超买卖出
+)
+
+// predictors predict 策略可用的预测器，只允许本地计算的基线模型，
+// 逐日调用 python 预测器会发起上千次 gRPC 请求并触发共享的熔断
+var predictors = []string{"naive", "linear", "holtwinters"}
+
+// Strategy 根据 K 线给出每个交易日收盘后的目标仓位，0 为空仓、1 为满仓
+// 第 i 个仓位只能依据 bars[:i+1] 计算，NaN 视为空仓
+type Strategy interface {
+	Name() string
+	// Lookback 给出第一个有效仓位需要的 K 线数量，用于向前多取数据
+	Lookback() int
+	Positions(ctx context.Context, bars []*util.Bar) ([]float64, error)
+}
+
+// Params 创建策略的参数与交易成本，序列化后随回测任务保存
+type Params struct {
+	Strategy   string  `json:"strategy"`
+	Predictor  string  `json:"predictor,omitempty"`
+	Window     int     `json:"window,omitempty"`
+	Threshold  float64 `json:"threshold,omitempty"`
+	Fast       int     `json:"fast,omitempty"`
+	Slow       int     `json:"slow,omitempty"`
+	Period     int     `json:"period,omitempty"`
+	Lower      float64 `json:"lower,omitempty"`
+	Upper      float64 `json:"upper,omitempty"`
+	Commission float64 `json:"commission"`
+	Slippage   float64 `json:"slippage"`
+}
+
+// NewStrategy 按参数创建策略，未填写的参数使用默认值并回写到 p
+func NewStrategy(p *Params) (Strategy, error) {
+	switch p.Strategy {
+	case StrategyPredict:
+		if p.Predictor == "" {
+			p.Predictor = "linear"
+		}
+		if p.Window == 0 {
+			p.Window = 31
+		}
+		if !slices.Contains(predictors, p.Predictor) {
+			return nil, fmt.Errorf("backtest: predictor %q is not supported", p.Predictor)
+		}
+		pr, err := predictor.Lookup(p.Predictor)
+		if err != nil {
+			return nil, err
+		}
+		return &Predict{Predictor: pr, Window: p.Window, Threshold: p.Threshold}, nil
+	case StrategyMACross:
+		if p.Fast == 0 {
+			p.Fast = 5
+		}
+		if p.Slow == 0 {
+			p.Slow = 20
+		}
+		if p.Fast >= p.Slow {
+			return nil, fmt.Errorf("backtest: fast period %d must be less than slow period %d", p.Fast, p.Slow)
+		}
+		return &MACross{Fast: p.Fast, Slow: p.Slow}, nil
+	case StrategyRSI:
+		if p.Period == 0 {
+			p.Period = 14
+		}
+		if p.Lower == 0 {
+			p.Lower = 30
+		}
+		if p.Upper == 0 {
+			p.Upper = 70
+		}
+		if p.Lower >= p.Upper {
+			return nil, fmt.Errorf("backtest: rsi lower %v must be less than upper %v", p.Lower, p.Upper)
+		}
+		return &RSI{Period: p.Period, Lower: p.Lower, Upper: p.Upper}, nil
+	}
+	return nil, fmt.Errorf("backtest: unknown strategy %q", p.Strategy)
+}
+
+// Predict 逐日用截至当天的 Window 根 K 线预测下一交易日收盘价
+// 预测涨幅超过 Threshold 时持有，否则空仓
+type Predict struct {
+	Predictor predictor.Predictor
+	Window    int
+	Threshold float64
+}
+
+func (s *Predict) Name() string {
+	return StrategyPredict + ":" + s.Predictor.Name()
+}
+
+func (s *Predict) Lookback() int {
+	return s.Window
+}
+
+func (s *Predict) Positions(ctx context.Context, bars []*util.Bar) ([]float64, error) {
+	points := make([]*predictor.Point, len(bars))
+	for i, v := range bars {
+		points[i] = &predictor.Point{
+			Date:  v.Date,
+			Open:  v.Open,
+			High:  v.High,
+			Low:   v.Low,
+			Close: v.Close,
+			Vol:   v.Vol,
+		}
+	}
+
+	positions := make([]float64, len(bars))
+	for i := range bars {
+		if i+1 < s.Window {
+			positions[i] = math.NaN()
+			continue
+		}
+
+		val, err := s.Predictor.Predict(ctx, points[i+1-s.Window:i+1])
+		if errors.Is(err, predictor.ErrNotEnoughData) {
+			positions[i] = math.NaN()
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("predict %s: %w", bars[i].Date.Format(time.DateOnly), err)
+		}
+		if val > bars[i].Close*(1+s.Threshold) {
+			positions[i] = 1
+		}
+	}
+	return positions, nil
+}
+
+// MACross 快速均线在慢速均线之上时持有
+type MACross struct {
+	Fast int
+	Slow int
+}
+
+func (s *MACross) Name() string {
+	return fmt.Sprintf("%s:%d:%d", StrategyMACross, s.Fast, s.Slow)
+}
+
+func (s *MACross) Lookback() int {
+	return s.Slow
+}
+
+func (s *MACross) Positions(_ context.Context, bars []*util.Bar) ([]float64, error) {
+	closes := barCloses(bars)
+	fast := indicator.SMA(closes, s.Fast)
+	slow := indicator.SMA(closes, s.Slow)
+
+	positions := make([]float64, len(bars))
+	for i := range bars {
+		switch {
+		case math.IsNaN(slow[i]):
+			positions[i] = math.NaN()
+		case fast[i] > slow[i]:
+			positions[i] = 1
+		}
+	}
+	return positions, nil
+}
+
+// RSI RSI 低于 Lower 时买入，高于 Upper 时卖出，之间维持原仓位
+type RSI struct {
+	Period int
+	Lower  float64
+	Upper  float64
+}
+
+func (s *RSI) Name() string {
+	return fmt.Sprintf("%s:%d:%g:%g", StrategyRSI, s.Period, s.Lower, s.Upper)
+}
+
+func (s *RSI) Lookback() int {
+	return s.Period + 1
+}
+
+func (s *RSI) Positions(_ context.Context, bars []*util.Bar) ([]float64, error) {
+	rsi := indicator.RSI(barCloses(bars), s.Period)
+
+	positions := make([]float64, len(bars))
+	var pos float64
+	for i := range bars {
+		switch {
+		case math.IsNaN(rsi[i]):
+			positions[i] = math.NaN()
+			continue
+		case rsi[i] < s.Lower:
+			pos = 1
+		case rsi[i] > s.Upper:
+			pos = 0
+		}
+		positions[i] = pos
+	}
+	return positions, nil
+}
+
+func barCloses(bars []*util.Bar) []float64 {
+	closes := make([]float64, len(bars))
+	for i, v := range bars {
+		closes[i] = v.Close
+	}
+	return closes
+}
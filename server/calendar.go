@@ -0,0 +1,27 @@
+package server
+
+import (
+	"context"
+	"financia/public"
+	"financia/public/db/dao"
+	"financia/server/tushare"
+	"financia/util"
+	"fmt"
+	"time"
+)
+
+// TradeCalendar start 至本季度结束（再留一周跨季的周线）的上交所交易日历，缓存到当天结束
+func TradeCalendar(ctx context.Context, start time.Time) (util.Calendar, error) {
+	now := time.Now()
+	end := time.Date(now.Year(), time.Month((int(now.Month())-1)/3*3+4), 0, 0, 0, 0, 0, now.Location()).AddDate(0, 0, 7)
+	startStr, endStr := start.Format(util.TimeDateOnlyWithOutSep), end.Format(util.TimeDateOnlyWithOutSep)
+
+	key := fmt.Sprintf(public.RedisKeyTradeCal, startStr, endStr)
+	days, err := dao.CachedList(ctx, key, func(ctx context.Context) ([]*tushare.FutTradeCalResp, error) {
+		return tushare.TradeCal(ctx, "SSE", startStr, endStr)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return openDays(days), nil
+}
@@ -0,0 +1,79 @@
+package server
+
+import (
+	"context"
+	"financia/public/db/dao"
+	"financia/public/db/model"
+	"financia/server/llm"
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/cast"
+	"go.uber.org/zap"
+)
+
+// chatSuffix 附加在固定上下文之后，说明之后的消息是追问
+const chatSuffix = "\n\n之后我会就这些数据继续提问，请结合数据与之前的对话用中文回答。"
+
+// CreateChat 创建对话，渲染行情数据与参考资料作为固定的第一条消息
+// 对话中的数据停留在创建时，不随行情更新
+func CreateChat(ctx context.Context, userId int64, asset string, id int, sections []string) (*model.AiChat, error) {
+	data, _, err := LoadPromptData(ctx, asset, id)
+	if err != nil {
+		return nil, err
+	}
+	data.Context = buildContext(ctx, data.TsCode, sections)
+	messages, err := llm.Render(asset, data)
+	if err != nil {
+		return nil, err
+	}
+
+	chat := &model.AiChat{
+		UserId:  userId,
+		Asset:   asset,
+		TsCode:  data.TsCode,
+		Name:    data.Name,
+		Context: messages[0].Content + "\n\n" + messages[1].Content + chatSuffix,
+	}
+	if err := dao.CreateAiChat(ctx, chat); err != nil {
+		return nil, err
+	}
+	return chat, nil
+}
+
+// Chat 发送追问并推送回复，固定上下文之后附带按预算截断的历史
+// 返回错误时尚未向前端写入任何内容；回复成功后才保存这一轮问答
+func Chat(c *gin.Context, chat *model.AiChat, question string) error {
+	history, err := dao.GetAiChatMessages(c, chat.Id)
+	if err != nil {
+		return err
+	}
+	if err := takeAiQuota(c, chat.UserId); err != nil {
+		return err
+	}
+
+	list := make([]llm.Message, 0, len(history))
+	for _, v := range history {
+		list = append(list, llm.Message{Role: v.Role, Content: v.Content})
+	}
+	messages := []llm.Message{{Role: "system", Content: chat.Context}}
+	messages = append(messages, llm.TrimHistory(list, llm.HistoryTokens())...)
+	messages = append(messages, llm.Message{Role: "user", Content: question})
+
+	reply, err := llm.Stream(c, llm.Default(), &llm.Request{
+		User:     cast.ToString(chat.UserId),
+		Messages: messages,
+	})
+	if err != nil {
+		releaseAiQuota(c, chat.UserId)
+		zap.S().Errorf("[Chat] [llm.Stream] [err] = %s", err.Error())
+		return nil
+	}
+
+	err = dao.AppendAiChatMessages(c, chat.Id, []*model.AiChatMessage{
+		{Role: "user", Content: question},
+		{Role: "assistant", Content: reply},
+	})
+	if err != nil {
+		zap.S().Errorf("[Chat] [AppendAiChatMessages] [err] = %s", err.Error())
+	}
+	return nil
+}
@@ -3,18 +3,17 @@ package server
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"financia/public"
 	"financia/public/db/connector"
 	"financia/public/db/dao"
 	"financia/public/db/model"
-	"financia/server/python"
 	"financia/server/tushare"
 	"financia/service/fut"
 	"financia/util"
 	"fmt"
 	"github.com/robfig/cron/v3"
 	"go.uber.org/zap"
-	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -36,7 +35,11 @@ func CronDailyWorker() {
 			Sse:  make([]*tushare.FutTradeCalResp, 0),
 			Szse: make([]*tushare.FutTradeCalResp, 0),
 		}
-		resp.Sse, resp.Szse = tushare.FutTradeCal(ctx)
+		resp.Sse, resp.Szse, err = tushare.FutTradeCal(ctx)
+		if err != nil {
+			zap.S().Errorf("[CalFut] [FutTradeCal] [err] = %s", err.Error())
+			return
+		}
 
 		for i, v := range resp.Sse {
 			resp.Sse[i].CalDate = util.ConvertDateStrToTime(v.CalDate, util.TimeDateOnlyWithOutSep).Format(time.DateOnly)
@@ -55,6 +58,18 @@ func CronDailyWorker() {
 		}
 	})
 
+	// 收盘后拉取全市场日线，入库后判断提醒规则
+	c.AddFunc("30 17 * * *", func() {
+		runAt := time.Now()
+		DailyIngest()
+		DailyAlert(runAt)
+	})
+	// 日线入库后回填预测的实际值并统计准确率
+	c.AddFunc("0 20 * * *", DailyAccuracy)
+	// 每日摘要按订阅的整点发送
+	c.AddFunc(fmt.Sprintf("0 %d-%d * * *", DigestFirstHour, DigestLastHour), func() {
+		DailyDigest(time.Now().In(location).Hour())
+	})
 	c.AddFunc("0 8 * * *", DailyPredictBefore)
 	c.AddFunc("0 10 * * *", DailyPredict)
 
@@ -72,11 +87,15 @@ func DailyPredictBefore() {
 		stockData, _ := dao.GetStockDataLimit30(ctx, tsCode)
 		last := stockData[0]
 		date := strings.ReplaceAll(last.TradeDate.Add(time.Hour*24).Format(time.DateOnly), "-", "")
-		data := tushare.DailyStockAll(ctx, &tushare.DailyReq{
+		data, err := tushare.DailyStockAll(ctx, &tushare.DailyReq{
 			TsCode:    tsCode,
 			StartDate: date,
 		})
-		_ = dao.InsertStockData(ctx, data)
+		if err != nil && !errors.Is(err, tushare.ErrEmptyResult) {
+			zap.S().Errorf("[DailyPredictBefore] [DailyStockAll] [err] = %s", err.Error())
+			continue
+		}
+		_ = dao.UpsertStockData(ctx, data)
 
 		// 异步比较今日已更新
 		wg.Add(1)
@@ -90,6 +109,7 @@ func DailyPredictBefore() {
 	wg.Wait()
 }
 
+// DailyPredict 为预测列表中的股票提交预测任务，由预测工作协程异步完成
 func DailyPredict() {
 	ctx := context.Background()
 	rdb := connector.GetRedis().WithContext(ctx)
@@ -98,10 +118,8 @@ func DailyPredict() {
 	for _, tsCode := range tsCodeList {
 		var id int
 		db.Model(model.StockInfo{}).Select("f_id").Where("f_ts_code = ?", tsCode).Scan(&id)
-		stockData, _ := dao.GetStockDataLimit30(ctx, tsCode)
-		sort.Slice(stockData, func(i, j int) bool {
-			return stockData[i].TradeDate.Before(stockData[j].TradeDate)
-		})
-		_, _ = python.PythonPredictStock(id, stockData)
+		if _, err := EnqueuePredict(ctx, public.AssetStock, id, tsCode); err != nil {
+			zap.S().Errorf("[DailyPredict] [EnqueuePredict] [err] = %s", err.Error())
+		}
 	}
 }
@@ -0,0 +1,204 @@
+package server
+
+import (
+	"context"
+	"financia/config"
+	"financia/public"
+	"financia/public/db/dao"
+	"financia/public/db/model"
+	"financia/server/forecast"
+	"financia/server/notify"
+	"financia/server/predictor"
+	"go.uber.org/zap"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// 每日摘要可选的发送时间（北京时间整点），日线在 17:30 入库
+const (
+	DigestFirstHour   = 18
+	DigestLastHour    = 23
+	DefaultDigestHour = 20
+)
+
+// DailyDigest 发送订阅在 hour 点及之前、还没有发送最新交易日的每日摘要
+// 当天的日线尚未入库时本次不发送，下一个整点再补发；没有关注或上次发送后没有新交易日数据（周末、节假日）时不发送
+func DailyDigest(hour int) {
+	ctx := context.Background()
+	tradeDate, ready, err := digestTradeDate(ctx)
+	if err != nil {
+		zap.S().Errorf("[DailyDigest] [digestTradeDate] [err] = %s", err.Error())
+		return
+	}
+	if !ready {
+		zap.S().Infof("[DailyDigest] %d 点日线尚未入库，下一个整点重试", hour)
+		return
+	}
+
+	list, err := dao.GetDueDigestSubscriptions(ctx, hour, tradeDate)
+	if err != nil {
+		zap.S().Errorf("[DailyDigest] [GetDueDigestSubscriptions] [err] = %s", err.Error())
+		return
+	}
+	for _, v := range list {
+		if err := sendDigest(ctx, v); err != nil {
+			zap.S().Errorf("[DailyDigest] [sendDigest] [user %d] [err] = %s", v.UserId, err.Error())
+		}
+	}
+}
+
+// digestTradeDate 股票与基金日线都已入库的最新交易日，今天是交易日但当天的数据还没有入库时 ready 为 false
+func digestTradeDate(ctx context.Context) (time.Time, bool, error) {
+	var tradeDate time.Time
+	for _, table := range []string{model.StockData{}.TableName(), model.FundData{}.TableName()} {
+		last, err := dao.GetIngestCheckpoint(ctx, table)
+		if err != nil {
+			return time.Time{}, false, err
+		}
+		if tradeDate.IsZero() || last.Before(tradeDate) {
+			tradeDate = last
+		}
+	}
+
+	now := time.Now()
+	if sameDay(tradeDate, now) {
+		return tradeDate, true, nil
+	}
+	cal, err := TradeCalendar(ctx, now)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	open := len(cal) > 0 && sameDay(cal[0], now)
+	return tradeDate, !open, nil
+}
+
+func sendDigest(ctx context.Context, sub *model.DigestSubscription) error {
+	data, tradeDate, err := BuildDigest(ctx, sub.UserId)
+	if err != nil {
+		return err
+	}
+	if len(data.Stocks) == 0 && len(data.Funds) == 0 {
+		return nil
+	}
+	if sub.LastTradeDate != nil && !tradeDate.After(*sub.LastTradeDate) {
+		return nil
+	}
+
+	user, err := dao.GetUser(ctx, sub.UserId)
+	if err != nil {
+		return err
+	}
+	data.UnsubscribeURL = digestUnsubscribeURL(sub.Token)
+	if err := Notify(ctx, user.Id, user.Email, notify.KindDigest, data, notify.ChannelEmail); err != nil {
+		return err
+	}
+	return dao.SetDigestSent(ctx, sub.Id, tradeDate)
+}
+
+// BuildDigest 关注的股票、基金最新的收盘价、涨跌幅与下一交易日的预测，以及当天触发的提醒，同时返回最新的交易日
+// 与 /user/info 一致，只使用缓存的预测值，未缓存时提交预测任务
+func BuildDigest(ctx context.Context, userId int64) (*notify.DigestData, time.Time, error) {
+	stockIds, fundIds, err := dao.GetFollowList(ctx, userId)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	var latest time.Time
+	data := &notify.DigestData{}
+	if len(stockIds) > 0 {
+		infos, err := dao.GetStockInfos(ctx, stockIds)
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+		for _, v := range infos {
+			list, err := dao.GetStockDataLimit(ctx, v.TsCode, 1)
+			if err != nil {
+				zap.S().Errorf("[BuildDigest] [GetStockDataLimit] [%s] [err] = %s", v.TsCode, err.Error())
+				continue
+			}
+			path, err := forecast.CachedStock(ctx, v.Id)
+			if err != nil {
+				zap.S().Errorf("[BuildDigest] [CachedStock] [%s] [err] = %s", v.TsCode, err.Error())
+			}
+			if path == nil {
+				enqueueDigestPredict(ctx, public.AssetStock, v.Id, v.TsCode)
+			}
+			data.Stocks = append(data.Stocks, digestItem(v.Name, v.TsCode, list[0].Close, list[0].PctChg, path))
+			latest = maxTime(latest, list[0].TradeDate)
+		}
+	}
+	if len(fundIds) > 0 {
+		infos, err := dao.GetFundInfos(ctx, fundIds)
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+		for _, v := range infos {
+			list, err := dao.GetFundDataLimit(ctx, v.TsCode, 1)
+			if err != nil {
+				zap.S().Errorf("[BuildDigest] [GetFundDataLimit] [%s] [err] = %s", v.TsCode, err.Error())
+				continue
+			}
+			path, err := forecast.CachedFund(ctx, int(v.Id))
+			if err != nil {
+				zap.S().Errorf("[BuildDigest] [CachedFund] [%s] [err] = %s", v.TsCode, err.Error())
+			}
+			if path == nil {
+				enqueueDigestPredict(ctx, public.AssetFund, int(v.Id), v.TsCode)
+			}
+			data.Funds = append(data.Funds, digestItem(v.Name, v.TsCode, list[0].Close, list[0].PctChg, path))
+			latest = maxTime(latest, list[0].TradeDate)
+		}
+	}
+
+	now := time.Now()
+	fires, err := dao.GetAlertFiresSince(ctx, userId, time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()))
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	for _, v := range fires {
+		data.Signals = append(data.Signals, &notify.AlertItem{Date: v.TradeDate.Format(time.DateOnly), Message: v.Message})
+	}
+
+	data.Date = latest.Format(time.DateOnly)
+	return data, latest, nil
+}
+
+func digestItem(name, tsCode string, closePrice, pctChg float64, path []*predictor.Forecast) *notify.DigestItem {
+	item := &notify.DigestItem{Name: name, TsCode: tsCode, Close: closePrice, PctChg: pctChg}
+	if path == nil {
+		return item
+	}
+	item.Predict = &path[0].Val
+	switch {
+	case path[0].Val > closePrice:
+		item.Direction = "看涨"
+	case path[0].Val < closePrice:
+		item.Direction = "看跌"
+	default:
+		item.Direction = "持平"
+	}
+	return item
+}
+
+func enqueueDigestPredict(ctx context.Context, asset string, id int, tsCode string) {
+	if _, err := EnqueuePredict(ctx, asset, id, tsCode); err != nil {
+		zap.S().Errorf("[BuildDigest] [EnqueuePredict] [%s] [err] = %s", tsCode, err.Error())
+	}
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
+
+// digestUnsubscribeURL 邮件中的退订链接，未配置接口地址时为空
+func digestUnsubscribeURL(token string) string {
+	base := strings.TrimRight(config.Configs.Notify.BaseURL, "/")
+	if base == "" {
+		return ""
+	}
+	return base + "/digest/unsubscribe?token=" + url.QueryEscape(token)
+}
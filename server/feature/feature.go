@@ -0,0 +1,266 @@
+// Package feature 为预测请求构建逐日的命名特征
+//
+// 特征按名称注册，特征集是一组特征名加版本号，版本随特征的组成或计算方式变化，
+// 以 "名称/v版本" 作为 schema 随请求发送，python 服务据此选择训练时使用的列。
+// 特征值按下标与输入的 K 线对齐，预热期或数据缺失的位置为 NaN。
+package feature
+
+import (
+	"financia/config"
+	"financia/util"
+	"financia/util/indicator"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// Input 构建特征所需的数据，除 Bars 外均可为空，缺失时对应特征为 NaN
+type Input struct {
+	Bars     []*util.Bar        // 按日期升序
+	IsHs     string             // 沪深港通标的：H 沪股通、S 深股通、N 否，基金为空
+	Turnover map[string]float64 // 换手率（%），键为 2006-01-02 格式的日期，基金为空
+	HS300    map[string]bool    // 沪深 300 各期成分中是否包含该股票，键为成分公布日期，基金为空
+	Shibor   map[string]float64 // 隔夜 SHIBOR，键为 2006-01-02 格式的日期
+}
+
+// Feature 一个逐日特征
+type Feature struct {
+	Name     string
+	Lookback int // 第一个有效值之前需要的 K 线数量
+	Compute  func(in *Input) []float64
+}
+
+// Set 特征集
+type Set struct {
+	Name     string
+	Version  int
+	Features []string
+}
+
+// Schema 随请求发送的特征集标识
+func (s *Set) Schema() string {
+	return fmt.Sprintf("%s/v%d", s.Name, s.Version)
+}
+
+// Lookback 特征集中所有特征都有效需要向前多取的 K 线数量
+func (s *Set) Lookback() int {
+	var n int
+	for _, name := range s.Features {
+		n = max(n, features[name].Lookback)
+	}
+	return n
+}
+
+// Has 特征集是否包含某个特征
+func (s *Set) Has(name string) bool {
+	for _, v := range s.Features {
+		if v == name {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultSet 未配置时使用的特征集
+const DefaultSet = "basic"
+
+var basic = []string{"open", "high", "low", "close", "vol"}
+
+var technical = append(basic[:len(basic):len(basic)],
+	"amount", "turnover", "ret_1", "ret_5", "ma_bias_5", "ma_bias_20", "rsi_14", "macd_hist", "boll_width", "atr_14")
+
+// technical 与 full 的 v2：v1 的 turnover 实为成交额，改名 amount，turnover 改为换手率；full 增加沪深 300 成分 in_hs300
+var sets = map[string]*Set{
+	// basic 对应原先 DataPoint 中的 co_imf1~4 与 target
+	"basic":     {Name: "basic", Version: 1, Features: basic},
+	"technical": {Name: "technical", Version: 2, Features: technical},
+	"full":      {Name: "full", Version: 2, Features: append(technical[:len(technical):len(technical)], "is_hs", "in_hs300", "shibor_on")},
+}
+
+// Lookup 按名称查找特征集
+func Lookup(name string) (*Set, error) {
+	s, ok := sets[name]
+	if !ok {
+		return nil, fmt.Errorf("feature: unknown set %q", name)
+	}
+	return s, nil
+}
+
+// Configured 配置的特征集，未配置或名称无效时为 DefaultSet
+func Configured() *Set {
+	if s, err := Lookup(config.Configs.Predictor.FeatureSet); err == nil {
+		return s
+	}
+	return sets[DefaultSet]
+}
+
+// Build 按特征集计算每根 K 线的特征，NaN 不写入结果
+func Build(s *Set, in *Input) []map[string]float64 {
+	rows := make([]map[string]float64, len(in.Bars))
+	for i := range rows {
+		rows[i] = make(map[string]float64, len(s.Features))
+	}
+	for _, name := range s.Features {
+		for i, v := range features[name].Compute(in) {
+			if !math.IsNaN(v) {
+				rows[i][name] = v
+			}
+		}
+	}
+	return rows
+}
+
+var features = map[string]*Feature{}
+
+func register(f *Feature) {
+	features[f.Name] = f
+}
+
+func init() {
+	register(&Feature{Name: "open", Compute: field(func(b *util.Bar) float64 { return b.Open })})
+	register(&Feature{Name: "high", Compute: field(func(b *util.Bar) float64 { return b.High })})
+	register(&Feature{Name: "low", Compute: field(func(b *util.Bar) float64 { return b.Low })})
+	register(&Feature{Name: "close", Compute: field(func(b *util.Bar) float64 { return b.Close })})
+	register(&Feature{Name: "vol", Compute: field(func(b *util.Bar) float64 { return b.Vol })})
+	// 成交额（千元）
+	register(&Feature{Name: "amount", Compute: field(func(b *util.Bar) float64 { return b.Amount })})
+	// 换手率取当日的每日指标，停牌或缺失时为 NaN
+	register(&Feature{Name: "turnover", Compute: func(in *Input) []float64 {
+		list := make([]float64, len(in.Bars))
+		for i, v := range in.Bars {
+			rate, ok := in.Turnover[v.Date.Format(time.DateOnly)]
+			if !ok {
+				rate = math.NaN()
+			}
+			list[i] = rate
+		}
+		return list
+	}})
+
+	register(&Feature{Name: "ret_1", Lookback: 1, Compute: returns(1)})
+	register(&Feature{Name: "ret_5", Lookback: 5, Compute: returns(5)})
+	register(&Feature{Name: "ma_bias_5", Lookback: 4, Compute: maBias(5)})
+	register(&Feature{Name: "ma_bias_20", Lookback: 19, Compute: maBias(20)})
+	register(&Feature{Name: "rsi_14", Lookback: 14, Compute: func(in *Input) []float64 {
+		return indicator.RSI(closes(in.Bars), 14)
+	}})
+	register(&Feature{Name: "macd_hist", Lookback: 33, Compute: func(in *Input) []float64 {
+		_, _, hist := indicator.MACD(closes(in.Bars), 12, 26, 9)
+		return hist
+	}})
+	// 布林带宽度 (上轨 - 下轨) / 中轨
+	register(&Feature{Name: "boll_width", Lookback: 19, Compute: func(in *Input) []float64 {
+		mid, upper, lower := indicator.BOLL(closes(in.Bars), 20, 2)
+		list := make([]float64, len(mid))
+		for i := range mid {
+			list[i] = (upper[i] - lower[i]) / mid[i]
+		}
+		return list
+	}})
+	// ATR 除以收盘价，不同价位的股票可比
+	register(&Feature{Name: "atr_14", Lookback: 13, Compute: func(in *Input) []float64 {
+		high := make([]float64, len(in.Bars))
+		low := make([]float64, len(in.Bars))
+		for i, v := range in.Bars {
+			high[i], low[i] = v.High, v.Low
+		}
+		list := indicator.ATR(high, low, closes(in.Bars), 14)
+		for i, v := range in.Bars {
+			list[i] /= v.Close
+		}
+		return list
+	}})
+
+	register(&Feature{Name: "is_hs", Compute: func(in *Input) []float64 {
+		v := math.NaN()
+		switch in.IsHs {
+		case "H", "S":
+			v = 1
+		case "N":
+			v = 0
+		}
+		list := make([]float64, len(in.Bars))
+		for i := range list {
+			list[i] = v
+		}
+		return list
+	}})
+	// 取当日或之前最近一期成分，1 为成分股
+	register(&Feature{Name: "in_hs300", Compute: func(in *Input) []float64 {
+		return asOf(in.Bars, in.HS300, func(member bool) float64 {
+			if member {
+				return 1
+			}
+			return 0
+		})
+	}})
+	// 取当日或之前最近一个公布日的利率
+	register(&Feature{Name: "shibor_on", Compute: func(in *Input) []float64 {
+		return asOf(in.Bars, in.Shibor, func(rate float64) float64 { return rate })
+	}})
+}
+
+// asOf 每根 K 线取当日或之前最近一个日期的值，之前没有数据时为 NaN
+func asOf[T any](bars []*util.Bar, data map[string]T, value func(T) float64) []float64 {
+	dates := make([]string, 0, len(data))
+	for d := range data {
+		dates = append(dates, d)
+	}
+	sort.Strings(dates)
+
+	list := make([]float64, len(bars))
+	for i, v := range bars {
+		j := sort.SearchStrings(dates, v.Date.Format(time.DateOnly)+"\x00")
+		if j == 0 {
+			list[i] = math.NaN()
+			continue
+		}
+		list[i] = value(data[dates[j-1]])
+	}
+	return list
+}
+
+func field(f func(b *util.Bar) float64) func(in *Input) []float64 {
+	return func(in *Input) []float64 {
+		list := make([]float64, len(in.Bars))
+		for i, v := range in.Bars {
+			list[i] = f(v)
+		}
+		return list
+	}
+}
+
+// returns n 日收益率
+func returns(n int) func(in *Input) []float64 {
+	return func(in *Input) []float64 {
+		list := make([]float64, len(in.Bars))
+		for i, v := range in.Bars {
+			if i < n {
+				list[i] = math.NaN()
+				continue
+			}
+			list[i] = v.Close/in.Bars[i-n].Close - 1
+		}
+		return list
+	}
+}
+
+// maBias 收盘价相对 n 日均线的偏离
+func maBias(n int) func(in *Input) []float64 {
+	return func(in *Input) []float64 {
+		list := indicator.SMA(closes(in.Bars), n)
+		for i, v := range in.Bars {
+			list[i] = v.Close/list[i] - 1
+		}
+		return list
+	}
+}
+
+func closes(bars []*util.Bar) []float64 {
+	list := make([]float64, len(bars))
+	for i, v := range bars {
+		list[i] = v.Close
+	}
+	return list
+}
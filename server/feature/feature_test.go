@@ -0,0 +1,107 @@
+package feature
+
+import (
+	"financia/util"
+	"math"
+	"testing"
+	"time"
+)
+
+func bars(n int) []*util.Bar {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	list := make([]*util.Bar, n)
+	for i := range list {
+		c := 10 + math.Sin(float64(i)/3)
+		list[i] = &util.Bar{Date: start.AddDate(0, 0, i), Open: c, High: c + 0.5, Low: c - 0.5, Close: c, Vol: 100, Amount: 1000}
+	}
+	return list
+}
+
+// 每个特征的第一个有效值恰好在 Lookback 处
+func TestLookback(t *testing.T) {
+	in := &Input{
+		Bars:     bars(60),
+		IsHs:     "H",
+		Turnover: make(map[string]float64),
+		HS300:    map[string]bool{"2023-12-29": true},
+		Shibor:   map[string]float64{"2023-12-29": 1.5},
+	}
+	for _, v := range in.Bars {
+		in.Turnover[v.Date.Format(time.DateOnly)] = 1.2
+	}
+	for name, f := range features {
+		list := f.Compute(in)
+		if len(list) != len(in.Bars) {
+			t.Errorf("%s: %d values for %d bars", name, len(list), len(in.Bars))
+			continue
+		}
+		first := -1
+		for i, v := range list {
+			if !math.IsNaN(v) {
+				first = i
+				break
+			}
+		}
+		if first != f.Lookback {
+			t.Errorf("%s: first valid at %d, lookback %d", name, first, f.Lookback)
+		}
+	}
+}
+
+func TestSets(t *testing.T) {
+	for name, s := range sets {
+		for _, f := range s.Features {
+			if _, ok := features[f]; !ok {
+				t.Errorf("set %s: unknown feature %s", name, f)
+			}
+		}
+	}
+	s, _ := Lookup("technical")
+	if s.Schema() != "technical/v2" || s.Lookback() != 33 || s.Has("is_hs") {
+		t.Errorf("technical = %s, lookback %d", s.Schema(), s.Lookback())
+	}
+	if len(basic) != 5 {
+		t.Errorf("basic set modified by append: %v", basic)
+	}
+	if _, err := Lookup("missing"); err == nil {
+		t.Error("expected error for unknown set")
+	}
+	if Configured().Name != DefaultSet {
+		t.Errorf("configured = %s, want %s", Configured().Name, DefaultSet)
+	}
+}
+
+func TestBuild(t *testing.T) {
+	list := bars(3)
+	list[1].Close, list[2].Close = 11, 12.1
+	in := &Input{
+		Bars:     list,
+		Turnover: map[string]float64{"2024-01-01": 0.8, "2024-01-03": 1.1},
+		HS300:    map[string]bool{"2023-12-15": false, "2024-01-02": true},
+		Shibor:   map[string]float64{"2024-01-01": 1.5, "2024-01-03": 1.7},
+	}
+	s := &Set{Name: "test", Version: 1, Features: []string{"close", "ret_1", "is_hs", "turnover", "in_hs300", "shibor_on"}}
+	rows := Build(s, in)
+
+	if _, ok := rows[0]["ret_1"]; ok {
+		t.Error("warm-up value should be omitted")
+	}
+	if _, ok := rows[0]["is_hs"]; ok {
+		t.Error("missing membership should be omitted")
+	}
+	if v := rows[2]["ret_1"]; math.Abs(v-0.1) > 1e-9 {
+		t.Errorf("ret_1 = %v, want 0.1", v)
+	}
+	// 1 月 2 日停牌没有换手率
+	if _, ok := rows[1]["turnover"]; ok || rows[2]["turnover"] != 1.1 {
+		t.Errorf("turnover = %v", rows)
+	}
+	// 1 月 2 日起纳入成分
+	if rows[0]["in_hs300"] != 0 || rows[1]["in_hs300"] != 1 || rows[2]["in_hs300"] != 1 {
+		t.Errorf("in_hs300 = %v, %v, %v", rows[0]["in_hs300"], rows[1]["in_hs300"], rows[2]["in_hs300"])
+	}
+	// 1 月 2 日没有公布，沿用前一日
+	if rows[1]["shibor_on"] != 1.5 || rows[2]["shibor_on"] != 1.7 {
+		t.Errorf("shibor = %v, %v", rows[1]["shibor_on"], rows[2]["shibor_on"])
+	}
+}
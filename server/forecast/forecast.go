@@ -0,0 +1,341 @@
+// Package forecast 股票、基金预测入口
+// 按资产类型选择预测器回退链，缓存当日预测路径并记录关键步数的预测
+package forecast
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"financia/config"
+	"financia/public"
+	"financia/public/db/connector"
+	"financia/public/db/dao"
+	"financia/public/db/model"
+	"financia/server/feature"
+	"financia/server/predictor"
+	"financia/server/python"
+	"financia/server/tushare"
+	"financia/util"
+	"fmt"
+	"github.com/go-redis/redis/v8"
+	"go.uber.org/zap"
+	"slices"
+	"sync"
+	"time"
+)
+
+// defaultChain 未配置时的回退顺序
+var defaultChain = []string{"python", "linear", "naive"}
+
+// Horizons 写入预测表的步数（交易日）
+var Horizons = []int{1, 5, 20}
+
+// fallbackExpire 回退模型的结果只短暂缓存，主模型恢复后尽快替换
+const fallbackExpire = 10 * time.Minute
+
+// Window 传给预测器的交易日数
+const Window = 31
+
+// hs300Code in_hs300 特征使用的沪深 300 指数代码
+const hs300Code = "399300.SZ"
+
+// History 预测需要加载的交易日数，在 Window 之前多取的部分用于计算特征
+func History() int {
+	return Window + feature.Configured().Lookback()
+}
+
+var (
+	once       sync.Once
+	stockChain *predictor.Chain
+	fundChain  *predictor.Chain
+	chainErr   error
+)
+
+func init() {
+	predictor.Register(python.NewPredictor())
+}
+
+func loadChains() error {
+	once.Do(func() {
+		stockNames := config.Configs.Predictor.Stock
+		if len(stockNames) == 0 {
+			stockNames = defaultChain
+		}
+		fundNames := config.Configs.Predictor.Fund
+		if len(fundNames) == 0 {
+			fundNames = stockNames
+		}
+
+		if stockChain, chainErr = predictor.NewChain(stockNames...); chainErr != nil {
+			return
+		}
+		fundChain, chainErr = predictor.NewChain(fundNames...)
+	})
+	return chainErr
+}
+
+// Stock 预测股票未来 predictor.MaxHorizon 个交易日的收盘价路径，缓存并记录关键步数
+// 记录包含实际使用的模型及版本，用于每日统计样本外准确率
+// stockData 为按日期升序的前复权数据，至少 History() 个交易日时所有特征都有效
+func Stock(ctx context.Context, id int, stockData []*model.StockData) ([]*predictor.Forecast, error) {
+	if err := loadChains(); err != nil {
+		return nil, err
+	}
+
+	path, name, err := stockChain.Forecast(ctx, stockFeatures(ctx, stockData), predictor.Path(predictor.MaxHorizon))
+	if err != nil {
+		return nil, err
+	}
+
+	setCache(ctx, fmt.Sprintf(public.RedisKeyStockPredict, id), fmt.Sprintf(public.RedisKeyStockForecast, id), path, name == stockChain.Primary())
+
+	last := stockData[len(stockData)-1]
+	rows := make([]*model.StockPredict, 0, len(Horizons))
+	for _, v := range keyForecasts(path) {
+		rows = append(rows, &model.StockPredict{
+			TsCode:       last.TsCode,
+			TradeDate:    last.TradeDate,
+			Model:        name,
+			Horizon:      v.Horizon,
+			ModelVersion: predictor.Version(name),
+			LastClose:    last.Close,
+			Predict:      v.Val,
+			Lower:        v.Lower,
+			Upper:        v.Upper,
+		})
+	}
+	if err := dao.UpsertStockPredict(ctx, rows); err != nil {
+		zap.S().Errorf("[forecast] [UpsertStockPredict] [err] = %s", err.Error())
+	}
+
+	return path, nil
+}
+
+// StockUncached 预测但不写缓存也不记录，用于非默认复权方式
+func StockUncached(ctx context.Context, stockData []*model.StockData) ([]*predictor.Forecast, error) {
+	if err := loadChains(); err != nil {
+		return nil, err
+	}
+
+	path, _, err := stockChain.Forecast(ctx, stockFeatures(ctx, stockData), predictor.Path(predictor.MaxHorizon))
+	return path, err
+}
+
+// Fund 预测基金未来 predictor.MaxHorizon 个交易日的收盘价路径，fundData 按日期升序
+func Fund(ctx context.Context, id int, fundData []*model.FundData) ([]*predictor.Forecast, error) {
+	if err := loadChains(); err != nil {
+		return nil, err
+	}
+
+	path, name, err := fundChain.Forecast(ctx, fundFeatures(ctx, fundData), predictor.Path(predictor.MaxHorizon))
+	if err != nil {
+		return nil, err
+	}
+
+	setCache(ctx, fmt.Sprintf(public.RedisKeyFundPredict, id), fmt.Sprintf(public.RedisKeyFundForecast, id), path, name == fundChain.Primary())
+
+	last := fundData[len(fundData)-1]
+	rows := make([]*model.FundPredict, 0, len(Horizons))
+	for _, v := range keyForecasts(path) {
+		rows = append(rows, &model.FundPredict{
+			TsCode:       last.TsCode,
+			TradeDate:    last.TradeDate,
+			Model:        name,
+			Horizon:      v.Horizon,
+			ModelVersion: predictor.Version(name),
+			LastClose:    last.Close,
+			Predict:      v.Val,
+			Lower:        v.Lower,
+			Upper:        v.Upper,
+		})
+	}
+	if err := dao.UpsertFundPredict(ctx, rows); err != nil {
+		zap.S().Errorf("[forecast] [UpsertFundPredict] [err] = %s", err.Error())
+	}
+
+	return path, nil
+}
+
+// CachedStock 当日缓存的股票预测路径，未命中时返回 nil
+func CachedStock(ctx context.Context, id int) ([]*predictor.Forecast, error) {
+	return getCache(ctx, fmt.Sprintf(public.RedisKeyStockForecast, id))
+}
+
+// CachedFund 当日缓存的基金预测路径，未命中时返回 nil
+func CachedFund(ctx context.Context, id int) ([]*predictor.Forecast, error) {
+	return getCache(ctx, fmt.Sprintf(public.RedisKeyFundForecast, id))
+}
+
+// StockPoints 股票日线转为预测数据点
+func StockPoints(stockData []*model.StockData) []*predictor.Point {
+	points := make([]*predictor.Point, 0, len(stockData))
+	for _, v := range stockData {
+		points = append(points, &predictor.Point{
+			Date:  v.TradeDate,
+			Open:  v.Open,
+			High:  v.High,
+			Low:   v.Low,
+			Close: v.Close,
+			Vol:   float64(v.Vol),
+		})
+	}
+	return points
+}
+
+// FundPoints 基金日线转为预测数据点，基金模型训练时未使用成交量
+func FundPoints(fundData []*model.FundData) []*predictor.Point {
+	points := make([]*predictor.Point, 0, len(fundData))
+	for _, v := range fundData {
+		points = append(points, &predictor.Point{
+			Date:  v.TradeDate,
+			Open:  v.Open,
+			High:  v.High,
+			Low:   v.Low,
+			Close: v.Close,
+		})
+	}
+	return points
+}
+
+// stockFeatures 按配置的特征集为每个数据点附加特征，返回最近 Window 个数据点
+func stockFeatures(ctx context.Context, stockData []*model.StockData) []*predictor.Point {
+	set := feature.Configured()
+	in := &feature.Input{Bars: model.StockBars(stockData)}
+	if set.Has("is_hs") {
+		if info, err := dao.GetStockInfoByTsCode(ctx, stockData[0].TsCode); err == nil {
+			in.IsHs = info.IsHs
+		} else {
+			zap.S().Errorf("[forecast] [GetStockInfoByTsCode] [err] = %s", err.Error())
+		}
+	}
+	if set.Has("turnover") {
+		in.Turnover = turnoverRates(ctx, stockData[0].TsCode, stockData[0].TradeDate)
+	}
+	if set.Has("in_hs300") {
+		in.HS300 = hs300Members(ctx, stockData[0].TsCode, stockData[0].TradeDate)
+	}
+	if set.Has("shibor_on") {
+		in.Shibor = shiborRates(ctx)
+	}
+	return window(StockPoints(stockData), feature.Build(set, in))
+}
+
+// fundFeatures 同 stockFeatures，基金没有沪深港通标的、换手率与指数成分特征
+func fundFeatures(ctx context.Context, fundData []*model.FundData) []*predictor.Point {
+	set := feature.Configured()
+	in := &feature.Input{Bars: model.FundBars(fundData)}
+	if set.Has("shibor_on") {
+		in.Shibor = shiborRates(ctx)
+	}
+	return window(FundPoints(fundData), feature.Build(set, in))
+}
+
+func window(points []*predictor.Point, rows []map[string]float64) []*predictor.Point {
+	for i, v := range points {
+		v.Features = rows[i]
+	}
+	return points[max(0, len(points)-Window):]
+}
+
+// shiborRates 隔夜 SHIBOR，缓存到当天结束，获取失败时返回 nil
+func shiborRates(ctx context.Context) map[string]float64 {
+	list, err := dao.CachedList(ctx, public.RedisKeyShiborDaily, tushare.EconomicsShibor)
+	if err != nil {
+		zap.S().Errorf("[forecast] [shiborRates] [err] = %s", err.Error())
+		return nil
+	}
+
+	rates := make(map[string]float64, len(list))
+	for _, v := range list {
+		rates[v.Date] = v.On
+	}
+	return rates
+}
+
+// turnoverRates start 以来股票每日的换手率
+func turnoverRates(ctx context.Context, tsCode string, start time.Time) map[string]float64 {
+	startStr := start.Format(util.TimeDateOnlyWithOutSep)
+	list, err := dao.CachedList(ctx, fmt.Sprintf(public.RedisKeyTurnover, tsCode, startStr), func(ctx context.Context) ([]*tushare.DailyBasicResp, error) {
+		return tushare.DailyBasic(ctx, tsCode, startStr, time.Now().Format(util.TimeDateOnlyWithOutSep))
+	})
+	if err != nil {
+		zap.S().Errorf("[forecast] [turnoverRates] [err] = %s", err.Error())
+		return nil
+	}
+
+	rates := make(map[string]float64, len(list))
+	for _, v := range list {
+		rates[v.TradeDate] = v.TurnoverRate
+	}
+	return rates
+}
+
+// hs300Members start 之前最近一期起，沪深 300 每期成分是否包含该股票
+// 成分每月公布一次，向前多取一个月保证第一根 K 线有可用的成分
+func hs300Members(ctx context.Context, tsCode string, start time.Time) map[string]bool {
+	startStr := start.AddDate(0, -1, 0).Format(util.TimeDateOnlyWithOutSep)
+	list, err := dao.CachedList(ctx, fmt.Sprintf(public.RedisKeyIndexWeight, hs300Code, startStr), func(ctx context.Context) ([]*tushare.IndexWeightResp, error) {
+		return tushare.IndexWeight(ctx, hs300Code, startStr, time.Now().Format(util.TimeDateOnlyWithOutSep))
+	})
+	if err != nil {
+		zap.S().Errorf("[forecast] [hs300Members] [err] = %s", err.Error())
+		return nil
+	}
+
+	members := make(map[string]bool)
+	for _, v := range list {
+		members[v.TradeDate] = members[v.TradeDate] || v.ConCode == tsCode
+	}
+	return members
+}
+
+// keyForecasts 路径中需要持久化的步数
+func keyForecasts(path []*predictor.Forecast) []*predictor.Forecast {
+	list := make([]*predictor.Forecast, 0, len(Horizons))
+	for _, v := range path {
+		if slices.Contains(Horizons, v.Horizon) {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+// setCache 下一交易日的预测值写入 valKey，完整路径写入 pathKey
+// 主模型的结果缓存到当天结束，回退模型的结果只缓存 fallbackExpire
+func setCache(ctx context.Context, valKey, pathKey string, path []*predictor.Forecast, primary bool) {
+	exp := time.Duration(util.SecondsUntilMidnight()) * time.Second
+	if !primary {
+		exp = min(exp, fallbackExpire)
+	}
+
+	data, err := json.Marshal(path)
+	if err != nil {
+		zap.S().Error("[forecast] [json.Marshal] [err] = ", err.Error())
+		return
+	}
+
+	rdb := connector.GetRedis().WithContext(ctx)
+	pipe := rdb.Pipeline()
+	pipe.Set(ctx, valKey, path[0].Val, exp)
+	pipe.Set(ctx, pathKey, data, exp)
+	if _, err := pipe.Exec(ctx); err != nil {
+		zap.S().Error("[forecast] [pipe.Exec] [err] = ", err.Error())
+	}
+}
+
+func getCache(ctx context.Context, pathKey string) ([]*predictor.Forecast, error) {
+	rdb := connector.GetRedis().WithContext(ctx)
+	result, err := rdb.Get(ctx, pathKey).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var path []*predictor.Forecast
+	if err := json.Unmarshal([]byte(result), &path); err != nil {
+		return nil, err
+	}
+	return path, nil
+}
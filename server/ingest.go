@@ -0,0 +1,218 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"financia/config"
+	"financia/public"
+	"financia/public/db/connector"
+	"financia/public/db/dao"
+	"financia/public/db/model"
+	"financia/server/tushare"
+	"financia/util"
+	"fmt"
+	"go.uber.org/zap"
+	"time"
+)
+
+// defaultIngestDays 首次运行且未配置起始日期时回补的天数
+const defaultIngestDays = 30
+
+// ingestTask 按交易日拉取一张日线表
+type ingestTask struct {
+	table   string
+	doToday string // 当日已更新标记，避免接口里再按代码单独拉取
+	// fetch 拉取并写入某个交易日的数据，返回写入的代码
+	fetch func(ctx context.Context, tradeDate time.Time) ([]string, error)
+}
+
+// DailyIngest 收盘后按交易日拉取全市场股票与基金日线，以及股票复权因子
+// 从上次的进度开始补齐缺失的交易日，重复运行不会写入重复数据
+func DailyIngest() {
+	ctx := context.Background()
+	tasks := []*ingestTask{
+		{table: model.StockData{}.TableName(), doToday: public.RedisKeyStockDataDoToday, fetch: ingestStockDaily},
+		{table: model.FundData{}.TableName(), doToday: public.RedisKeyFundDataDoToday, fetch: ingestFundDaily},
+		{table: model.StockAdjFactor{}.TableName(), fetch: ingestAdjFactor},
+	}
+
+	for _, task := range tasks {
+		if err := runIngest(ctx, task); err != nil {
+			zap.S().Errorf("[DailyIngest] [%s] [err] = %s", task.table, err.Error())
+		}
+	}
+}
+
+func runIngest(ctx context.Context, task *ingestTask) error {
+	last, err := dao.GetIngestCheckpoint(ctx, task.table)
+	if err != nil {
+		return fmt.Errorf("get checkpoint: %w", err)
+	}
+	if last.IsZero() {
+		last = ingestStartDate().AddDate(0, 0, -1)
+	}
+
+	today := time.Now()
+	start := last.AddDate(0, 0, 1)
+	if start.After(today) {
+		return nil
+	}
+
+	days, err := tushare.TradeCal(ctx, "SSE", start.Format(util.TimeDateOnlyWithOutSep), today.Format(util.TimeDateOnlyWithOutSep))
+	if err != nil {
+		if errors.Is(err, tushare.ErrEmptyResult) {
+			return nil
+		}
+		return fmt.Errorf("trade cal: %w", err)
+	}
+
+	for _, date := range openDays(days) {
+		codes, err := task.fetch(ctx, date)
+		if err != nil {
+			// 当天数据尚未发布，等下次运行；过去的交易日没有数据则保留检查点重试
+			if errors.Is(err, tushare.ErrEmptyResult) && sameDay(date, today) {
+				return nil
+			}
+			return fmt.Errorf("fetch %s: %w", date.Format(time.DateOnly), err)
+		}
+
+		if err := dao.SetIngestCheckpoint(ctx, task.table, date); err != nil {
+			return fmt.Errorf("set checkpoint: %w", err)
+		}
+		zap.S().Infof("[DailyIngest] [%s] %s done, %d codes", task.table, date.Format(time.DateOnly), len(codes))
+
+		if sameDay(date, today) && task.doToday != "" {
+			markDoToday(ctx, task.doToday, codes)
+		}
+	}
+
+	return nil
+}
+
+func ingestStockDaily(ctx context.Context, tradeDate time.Time) ([]string, error) {
+	data, err := tushare.DailyStockByDate(ctx, tradeDate.Format(util.TimeDateOnlyWithOutSep))
+	if err != nil {
+		return nil, err
+	}
+
+	listed, err := dao.GetListedStockCodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	exists, err := dao.GetStockDataCodesByDate(ctx, tradeDate)
+	if err != nil {
+		return nil, err
+	}
+
+	insert := make([]*model.StockData, 0, len(data))
+	codes := make([]string, 0, len(data))
+	for _, v := range data {
+		if _, ok := listed[v.TsCode]; !ok {
+			continue
+		}
+		codes = append(codes, v.TsCode)
+		if _, ok := exists[v.TsCode]; ok {
+			continue
+		}
+		insert = append(insert, v)
+	}
+
+	return codes, dao.UpsertStockData(ctx, insert)
+}
+
+func ingestFundDaily(ctx context.Context, tradeDate time.Time) ([]string, error) {
+	data, err := tushare.DailyFundByDate(ctx, tradeDate.Format(util.TimeDateOnlyWithOutSep))
+	if err != nil {
+		return nil, err
+	}
+
+	funds, err := dao.GetFundCodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	exists, err := dao.GetFundDataCodesByDate(ctx, tradeDate)
+	if err != nil {
+		return nil, err
+	}
+
+	insert := make([]*model.FundData, 0, len(data))
+	codes := make([]string, 0, len(data))
+	for _, v := range data {
+		if _, ok := funds[v.TsCode]; !ok {
+			continue
+		}
+		codes = append(codes, v.TsCode)
+		if _, ok := exists[v.TsCode]; ok {
+			continue
+		}
+		insert = append(insert, v)
+	}
+
+	return codes, dao.UpsertFundData(ctx, insert)
+}
+
+func ingestAdjFactor(ctx context.Context, tradeDate time.Time) ([]string, error) {
+	data, err := tushare.AdjFactorByDate(ctx, tradeDate.Format(util.TimeDateOnlyWithOutSep))
+	if err != nil {
+		return nil, err
+	}
+
+	listed, err := dao.GetListedStockCodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	upsert := make([]*model.StockAdjFactor, 0, len(data))
+	codes := make([]string, 0, len(data))
+	for _, v := range data {
+		if _, ok := listed[v.TsCode]; !ok {
+			continue
+		}
+		codes = append(codes, v.TsCode)
+		upsert = append(upsert, v)
+	}
+
+	return codes, dao.UpsertStockAdjFactor(ctx, upsert)
+}
+
+// markDoToday 标记代码当日数据已更新，接口不再单独请求 tushare
+func markDoToday(ctx context.Context, keyFormat string, codes []string) {
+	rdb := connector.GetRedis().WithContext(ctx)
+	exp := time.Duration(util.SecondsUntilMidnight()) * time.Second
+
+	pipe := rdb.Pipeline()
+	for _, code := range codes {
+		pipe.Set(ctx, fmt.Sprintf(keyFormat, code), "1", exp)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		zap.S().Errorf("[markDoToday] [pipe.Exec] [err] = %s", err.Error())
+	}
+}
+
+// openDays 交易日历中开市的日期，按时间升序
+func openDays(days []*tushare.FutTradeCalResp) []time.Time {
+	list := make([]time.Time, 0, len(days))
+	for _, v := range days {
+		if v.IsOpen != public.MarketStatusOpen {
+			continue
+		}
+		list = append(list, util.ConvertDateStrToTime(v.CalDate, util.TimeDateOnlyWithOutSep))
+	}
+
+	// 交易日历按日期倒序返回
+	for i, j := 0, len(list)-1; i < j; i, j = i+1, j-1 {
+		list[i], list[j] = list[j], list[i]
+	}
+	return list
+}
+
+func ingestStartDate() time.Time {
+	if t := util.ConvertDateStrToTime(config.Configs.Ingest.StartDate, time.DateOnly); !t.IsZero() {
+		return t
+	}
+	return time.Now().AddDate(0, 0, -defaultIngestDays)
+}
+
+func sameDay(a, b time.Time) bool {
+	return a.Format(time.DateOnly) == b.Format(time.DateOnly)
+}
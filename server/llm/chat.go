@@ -0,0 +1,30 @@
+package llm
+
+import "financia/config"
+
+// defaultHistoryTokens 未配置时对话历史的 token 预算
+const defaultHistoryTokens = 2000
+
+// HistoryTokens 对话历史的 token 预算
+func HistoryTokens() int {
+	if n := config.Configs.LLM.HistoryTokens; n > 0 {
+		return n
+	}
+	return defaultHistoryTokens
+}
+
+// TrimHistory 从最近的消息向前保留直到用完预算，保留的部分以用户消息开头
+func TrimHistory(history []Message, budget int) []Message {
+	start := len(history)
+	for i := len(history) - 1; i >= 0; i-- {
+		budget -= EstimateTokens(history[i].Content)
+		if budget < 0 {
+			break
+		}
+		start = i
+	}
+	for start < len(history) && history[start].Role != "user" {
+		start++
+	}
+	return history[start:]
+}
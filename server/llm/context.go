@@ -0,0 +1,75 @@
+package llm
+
+import (
+	"encoding/json"
+	"financia/config"
+	"unicode/utf8"
+)
+
+// defaultContextTokens 未配置时参考资料的 token 预算
+const defaultContextTokens = 1500
+
+// 参考资料的名称，同时是请求中的开关参数
+const (
+	SectionIncome   = "income"   // 利润表
+	SectionForecast = "forecast" // 业绩预告
+	SectionHolder   = "holder"   // 前十大股东及变动
+	SectionPeer     = "peer"     // 同行业公司
+	SectionMacro    = "macro"    // SHIBOR 与 CPI
+)
+
+// Section 一段参考资料，Rows 按重要程度排列，超出预算时从末尾截断
+type Section struct {
+	Name  string `json:"-"`
+	Title string `json:"title"`
+	Rows  []any  `json:"rows"`
+}
+
+// ContextTokens 参考资料的 token 预算
+func ContextTokens() int {
+	if n := config.Configs.LLM.ContextTokens; n > 0 {
+		return n
+	}
+	return defaultContextTokens
+}
+
+// EstimateTokens 粗略估计文本的 token 数：非 ASCII 字符每字计一个，ASCII 字符每 4 个计一个
+func EstimateTokens(s string) int {
+	var ascii, other int
+	for _, r := range s {
+		if r < utf8.RuneSelf {
+			ascii++
+		} else {
+			other++
+		}
+	}
+	return other + (ascii+3)/4
+}
+
+// Fit 按顺序放入参考资料直到用完预算，放不下的行丢弃，一行都放不下的资料整段丢弃
+func Fit(sections []*Section, budget int) []*Section {
+	list := make([]*Section, 0, len(sections))
+	for _, s := range sections {
+		// 标题与 JSON 结构的开销
+		used := EstimateTokens(s.Title) + 8
+		fitted := &Section{Name: s.Name, Title: s.Title}
+		for _, row := range s.Rows {
+			data, err := json.Marshal(row)
+			if err != nil {
+				continue
+			}
+			n := EstimateTokens(string(data)) + 1
+			if used+n > budget {
+				break
+			}
+			used += n
+			fitted.Rows = append(fitted.Rows, row)
+		}
+		if len(fitted.Rows) == 0 {
+			continue
+		}
+		budget -= used
+		list = append(list, fitted)
+	}
+	return list
+}
@@ -0,0 +1,84 @@
+// Package llm 大模型分析
+//
+// Provider 屏蔽具体的模型服务，OpenAI 兼容接口（讯飞星火等）与本地 Mock 均实现该接口。
+// 提示词按资产类型使用模板渲染，Stream 将模型输出以 SSE 事件推送给前端。
+package llm
+
+import (
+	"context"
+	"errors"
+	"financia/config"
+	"sync"
+	"time"
+)
+
+// 默认使用讯飞星火的 OpenAI 兼容接口
+const (
+	defaultBaseURL = "https://spark-api-open.xf-yun.com/v1"
+	defaultModel   = "4.0Ultra"
+	defaultTimeout = 2 * time.Minute
+)
+
+// ErrNotConfigured 未配置模型服务的密钥
+var ErrNotConfigured = errors.New("llm: provider not configured")
+
+// Message 对话消息
+type Message struct {
+	Role    string `json:"role"` // system、user 或 assistant
+	Content string `json:"content"`
+}
+
+// Request 一次对话请求
+type Request struct {
+	User     string // 终端用户标识，用于服务方的滥用监控
+	Messages []Message
+}
+
+// Provider 大模型服务，以流式方式返回回复
+type Provider interface {
+	Name() string
+	// Model 实际使用的模型，随分析一起保存
+	Model() string
+	// Stream 发送请求并依次回调回复的增量文本，onDelta 返回错误时中止
+	Stream(ctx context.Context, req *Request, onDelta func(delta string) error) error
+}
+
+var (
+	once     sync.Once
+	provider Provider
+)
+
+// Default 按配置创建的模型服务
+func Default() Provider {
+	once.Do(func() {
+		provider = newProvider(&config.Configs.LLM)
+	})
+	return provider
+}
+
+func newProvider(cfg *config.LLMConfig) Provider {
+	if cfg.Provider == "mock" {
+		return &Mock{Reply: "这是一条用于本地调试的模拟分析。"}
+	}
+
+	baseURL, model, apiKey := cfg.BaseURL, cfg.Model, cfg.APIKey
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+	if model == "" {
+		model = defaultModel
+	}
+	// 兼容旧配置
+	if apiKey == "" {
+		apiKey = config.Configs.Spark.Password
+	}
+	return NewOpenAI(baseURL, apiKey, model)
+}
+
+// Timeout 单次分析的最长时间
+func Timeout() time.Duration {
+	if d := config.Configs.LLM.Timeout; d > 0 {
+		return d
+	}
+	return defaultTimeout
+}
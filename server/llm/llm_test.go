@@ -0,0 +1,284 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"financia/config"
+	"financia/public"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestOpenAIStream(t *testing.T) {
+	var got chatRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" || r.Header.Get("Authorization") != "Bearer key" {
+			t.Errorf("path = %s, auth = %s", r.URL.Path, r.Header.Get("Authorization"))
+		}
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		for _, s := range []string{"你好", "", "，世界"} {
+			fmt.Fprintf(w, "data: {\"choices\":[{\"delta\":{\"content\":%q}}]}\n\n", s)
+		}
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer srv.Close()
+
+	p := NewOpenAI(srv.URL+"/v1/", "key", "m1")
+	var reply strings.Builder
+	err := p.Stream(context.Background(), &Request{User: "7", Messages: []Message{{Role: "user", Content: "hi"}}}, func(s string) error {
+		reply.WriteString(s)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reply.String() != "你好，世界" {
+		t.Errorf("reply = %q", reply.String())
+	}
+	if !got.Stream || got.Model != "m1" || got.User != "7" || len(got.Messages) != 1 {
+		t.Errorf("request = %+v", got)
+	}
+}
+
+func TestOpenAIErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/limited") {
+			http.Error(w, "rate limited", http.StatusTooManyRequests)
+			return
+		}
+		if strings.HasPrefix(r.URL.Path, "/truncated") {
+			// 连接在 [DONE] 之前关闭
+			fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"你好\"}}]}\n\n")
+			return
+		}
+		// 星火在流中返回错误码
+		fmt.Fprint(w, "data: {\"code\":10013,\"message\":\"content blocked\"}\n\n")
+	}))
+	defer srv.Close()
+
+	for _, url := range []string{srv.URL + "/limited", srv.URL} {
+		p := NewOpenAI(url, "key", "m1")
+		err := p.Stream(context.Background(), &Request{}, func(string) error { return nil })
+		if err == nil {
+			t.Errorf("%s: expected error", url)
+		}
+	}
+
+	var reply string
+	err := NewOpenAI(srv.URL+"/truncated", "key", "m1").Stream(context.Background(), &Request{}, func(s string) error {
+		reply += s
+		return nil
+	})
+	if !errors.Is(err, io.ErrUnexpectedEOF) || reply != "你好" {
+		t.Errorf("truncated: err = %v, reply = %q, want io.ErrUnexpectedEOF", err, reply)
+	}
+
+	err = NewOpenAI(srv.URL, "", "m1").Stream(context.Background(), &Request{}, func(string) error { return nil })
+	if !errors.Is(err, ErrNotConfigured) {
+		t.Errorf("err = %v, want ErrNotConfigured", err)
+	}
+}
+
+// sseEvents 解析响应中的事件名与数据
+func sseEvents(body string) [][2]string {
+	var events [][2]string
+	for _, block := range strings.Split(strings.TrimSpace(body), "\n\n") {
+		var ev [2]string
+		for _, line := range strings.Split(block, "\n") {
+			if v, ok := strings.CutPrefix(line, "event:"); ok {
+				ev[0] = v
+			} else if v, ok := strings.CutPrefix(line, "data:"); ok {
+				ev[1] = v
+			}
+		}
+		events = append(events, ev)
+	}
+	return events
+}
+
+func TestStream(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/stock/ai", nil)
+
+	m := &Mock{Reply: "上涨趋势明显，\n建议继续持有观察"}
+	content, err := Stream(c, m, &Request{User: "1"})
+	if err != nil || content != m.Reply {
+		t.Fatalf("content = %q, err = %v", content, err)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("content type = %s", ct)
+	}
+
+	events := sseEvents(w.Body.String())
+	if len(events) != 3 || events[0][0] != EventDelta || events[2][0] != EventDone {
+		t.Fatalf("events = %v", events)
+	}
+	var done Delta
+	if err := json.Unmarshal([]byte(events[2][1]), &done); err != nil || done.Content != m.Reply {
+		t.Errorf("done = %q, err = %v", events[2][1], err)
+	}
+	if len(m.Requests()) != 1 {
+		t.Errorf("requests = %d", len(m.Requests()))
+	}
+
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/stock/ai", nil)
+	content, err = Stream(c, &Mock{Reply: "部分", Err: io.ErrUnexpectedEOF}, &Request{})
+	events = sseEvents(w.Body.String())
+	if !errors.Is(err, io.ErrUnexpectedEOF) || content != "部分" || events[len(events)-1][0] != EventError {
+		t.Errorf("content = %q, err = %v, events = %v", content, err, events)
+	}
+}
+
+func TestReplay(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	content := strings.Repeat("缓存的分析", 10)
+	Replay(c, content)
+
+	events := sseEvents(w.Body.String())
+	if len(events) != 3 || events[2][0] != EventDone {
+		t.Fatalf("events = %v", events)
+	}
+	var got strings.Builder
+	for _, ev := range events[:2] {
+		var d Delta
+		if err := json.Unmarshal([]byte(ev[1]), &d); err != nil || !d.Cached {
+			t.Fatalf("delta = %s, err = %v", ev[1], err)
+		}
+		got.WriteString(d.Content)
+	}
+	if got.String() != content {
+		t.Errorf("replayed = %q", got.String())
+	}
+}
+
+func TestPromptVersion(t *testing.T) {
+	stock, err := PromptVersion(public.AssetStock, nil)
+	if err != nil || len(stock) != 12 {
+		t.Fatalf("version = %q, err = %v", stock, err)
+	}
+	if fund, _ := PromptVersion(public.AssetFund, nil); fund != stock {
+		t.Errorf("same templates, versions %s != %s", fund, stock)
+	}
+	if v, _ := PromptVersion(public.AssetStock, []string{SectionMacro}); v == stock {
+		t.Error("version unchanged with sections")
+	}
+
+	config.Configs.LLM.Prompts = map[string]config.PromptConfig{public.AssetStock: {System: "简要分析"}}
+	defer func() { config.Configs.LLM.Prompts = nil }()
+	if v, _ := PromptVersion(public.AssetStock, nil); v == stock {
+		t.Error("version unchanged after override")
+	}
+	if _, err := PromptVersion("bond", nil); err == nil {
+		t.Error("expected error for unknown asset")
+	}
+}
+
+func TestRender(t *testing.T) {
+	predict := 10.5
+	data := NewPromptData(public.AssetFund, "沪深300ETF", "510300.SH", []float64{1, 2, 3, 4, 5, 6}, &predict)
+	messages, err := Render(public.AssetFund, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(messages[0].Content, "基金「沪深300ETF」") || !strings.Contains(messages[0].Content, "predict") {
+		t.Errorf("system = %s", messages[0].Content)
+	}
+	var user PromptData
+	if err := json.Unmarshal([]byte(messages[1].Content), &user); err != nil || *user.Predict != 10.5 || len(user.SMA) != 2 {
+		t.Errorf("user = %s, err = %v", messages[1].Content, err)
+	}
+
+	config.Configs.LLM.Prompts = map[string]config.PromptConfig{public.AssetStock: {User: "{{.Name}} 最新收盘 {{index .Data 0}}"}}
+	defer func() { config.Configs.LLM.Prompts = nil }()
+	messages, err = Render(public.AssetStock, NewPromptData(public.AssetStock, "平安银行", "000001.SZ", []float64{12}, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if messages[1].Content != "平安银行 最新收盘 12" || strings.Contains(messages[0].Content, "predict") {
+		t.Errorf("messages = %+v", messages)
+	}
+
+	if _, err := Render("bond", data); err == nil {
+		t.Error("expected error for unknown asset")
+	}
+}
+
+func TestEstimateTokens(t *testing.T) {
+	for s, want := range map[string]int{"": 0, "abcd": 1, "abcde": 2, "净利润": 3, "净利润 12.5": 5} {
+		if got := EstimateTokens(s); got != want {
+			t.Errorf("EstimateTokens(%q) = %d, want %d", s, got, want)
+		}
+	}
+}
+
+func TestFit(t *testing.T) {
+	type row struct {
+		V string `json:"v"`
+	}
+	long := strings.Repeat("长", 50)
+	sections := []*Section{
+		{Name: SectionMacro, Title: "宏观", Rows: []any{&row{"a"}, &row{"b"}}},
+		{Name: SectionIncome, Title: "利润表", Rows: []any{&row{long}, &row{long}, &row{long}}},
+		{Name: SectionPeer, Title: "同行业", Rows: []any{&row{long}}},
+	}
+
+	got := Fit(sections, 150)
+	if len(got) != 2 || len(got[0].Rows) != 2 || len(got[1].Rows) != 2 {
+		t.Fatalf("fit = %+v", got)
+	}
+	if len(sections[1].Rows) != 3 {
+		t.Error("input modified")
+	}
+	if got := Fit(sections, 5); len(got) != 0 {
+		t.Errorf("tiny budget = %+v", got)
+	}
+}
+
+func TestRenderContext(t *testing.T) {
+	data := NewPromptData(public.AssetStock, "平安银行", "000001.SZ", []float64{12, 13}, nil)
+	data.Context = []*Section{{Name: SectionMacro, Title: "宏观数据", Rows: []any{map[string]float64{"on": 1.5}}}}
+	messages, err := Render(public.AssetStock, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(messages[0].Content, "宏观数据等参考资料") {
+		t.Errorf("system = %s", messages[0].Content)
+	}
+	if !strings.Contains(messages[1].Content, `"context":[{"title":"宏观数据","rows":[{"on":1.5}]}]`) {
+		t.Errorf("user = %s", messages[1].Content)
+	}
+}
+
+func TestTrimHistory(t *testing.T) {
+	history := []Message{
+		{Role: "user", Content: "为什么下跌"},
+		{Role: "assistant", Content: "主要是业绩预告不及预期"},
+		{Role: "user", Content: "和上季度比呢"},
+		{Role: "assistant", Content: "净利润同比下降"},
+	}
+
+	if got := TrimHistory(history, 100); len(got) != 4 {
+		t.Errorf("all fit, got %d", len(got))
+	}
+	// 预算只够最后两条
+	if got := TrimHistory(history, 14); len(got) != 2 || got[0].Content != "和上季度比呢" {
+		t.Errorf("got %+v", got)
+	}
+	// 截断点落在回复上时，丢弃没有问题的回复
+	if got := TrimHistory(history, 7); len(got) != 0 {
+		t.Errorf("got %+v", got)
+	}
+}
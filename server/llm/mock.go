@@ -0,0 +1,50 @@
+package llm
+
+import (
+	"context"
+	"sync"
+)
+
+// mockChunk Mock 每次回调的字符数
+const mockChunk = 8
+
+// Mock 本地模拟的模型服务，按固定回复分段返回，用于测试与无密钥的本地调试
+type Mock struct {
+	Reply string
+	Err   error // 回复发送完后返回的错误
+
+	mu       sync.Mutex
+	requests []*Request
+}
+
+func (*Mock) Name() string {
+	return "mock"
+}
+
+func (*Mock) Model() string {
+	return "mock"
+}
+
+func (m *Mock) Stream(ctx context.Context, req *Request, onDelta func(string) error) error {
+	m.mu.Lock()
+	m.requests = append(m.requests, req)
+	m.mu.Unlock()
+
+	runes := []rune(m.Reply)
+	for i := 0; i < len(runes); i += mockChunk {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := onDelta(string(runes[i:min(i+mockChunk, len(runes))])); err != nil {
+			return err
+		}
+	}
+	return m.Err
+}
+
+// Requests 收到的全部请求
+func (m *Mock) Requests() []*Request {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]*Request(nil), m.requests...)
+}
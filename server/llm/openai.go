@@ -0,0 +1,125 @@
+package llm
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/go-resty/resty/v2"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// OpenAI OpenAI 兼容的 /chat/completions 流式接口
+type OpenAI struct {
+	baseURL string
+	apiKey  string
+	model   string
+	client  *resty.Client
+}
+
+func NewOpenAI(baseURL, apiKey, model string) *OpenAI {
+	return &OpenAI{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		apiKey:  apiKey,
+		model:   model,
+		client:  resty.New(),
+	}
+}
+
+type chatRequest struct {
+	Model    string    `json:"model"`
+	User     string    `json:"user,omitempty"`
+	Messages []Message `json:"messages"`
+	Stream   bool      `json:"stream"`
+}
+
+// chatChunk 流式响应的一行，星火出错时在 code、message 中返回
+type chatChunk struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Error   *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+	Choices []struct {
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+	} `json:"choices"`
+}
+
+func (*OpenAI) Name() string {
+	return "openai"
+}
+
+func (p *OpenAI) Model() string {
+	return p.model
+}
+
+func (p *OpenAI) Stream(ctx context.Context, req *Request, onDelta func(string) error) error {
+	if p.apiKey == "" {
+		return ErrNotConfigured
+	}
+
+	resp, err := p.client.R().
+		SetContext(ctx).
+		SetHeader("Content-Type", "application/json").
+		SetHeader("Accept", "text/event-stream").
+		SetAuthToken(p.apiKey).
+		SetBody(&chatRequest{
+			Model:    p.model,
+			User:     req.User,
+			Messages: req.Messages,
+			Stream:   true,
+		}).
+		SetDoNotParseResponse(true).
+		Post(p.baseURL + "/chat/completions")
+	if err != nil {
+		return err
+	}
+	body := resp.RawBody()
+	defer body.Close()
+
+	if resp.StatusCode() != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(body, 1024))
+		return fmt.Errorf("llm: status %d: %s", resp.StatusCode(), strings.TrimSpace(string(msg)))
+	}
+
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+		data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		if data == "[DONE]" {
+			return nil
+		}
+
+		var chunk chatChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return fmt.Errorf("llm: decode chunk: %w", err)
+		}
+		if chunk.Error != nil {
+			return fmt.Errorf("llm: %s", chunk.Error.Message)
+		}
+		if chunk.Code != 0 {
+			return fmt.Errorf("llm: code %d: %s", chunk.Code, chunk.Message)
+		}
+		for _, choice := range chunk.Choices {
+			if choice.Delta.Content == "" {
+				continue
+			}
+			if err := onDelta(choice.Delta.Content); err != nil {
+				return err
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	// 连接在 [DONE] 之前关闭，回复不完整
+	return io.ErrUnexpectedEOF
+}
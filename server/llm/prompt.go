@@ -0,0 +1,126 @@
+package llm
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"financia/config"
+	"financia/public"
+	"financia/util/indicator"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// PromptData 渲染提示词模板的数据，序列化后即默认的用户消息
+type PromptData struct {
+	Asset   string     `json:"-"`
+	Name    string     `json:"name"`
+	TsCode  string     `json:"tsCode"`
+	Data    []float64  `json:"data"` // 收盘价，按日期升序
+	SMA     []float64  `json:"sma"`
+	EMA     []float64  `json:"ema"`
+	WMA     []float64  `json:"wma"`
+	MACD    []float64  `json:"macd"`
+	RSI     []float64  `json:"rsi"`
+	Predict *float64   `json:"predict"`           // 下一交易日预测值，尚未预测时为空
+	Context []*Section `json:"context,omitempty"` // 参考资料，已按预算截断
+}
+
+// NewPromptData 由收盘价计算提示词中的技术指标
+func NewPromptData(asset, name, tsCode string, closes []float64, predict *float64) *PromptData {
+	_, _, macd := indicator.MACD(closes, 5, 10, 5)
+	return &PromptData{
+		Asset:   asset,
+		Name:    name,
+		TsCode:  tsCode,
+		Data:    closes,
+		SMA:     indicator.Trim(indicator.SMA(closes, 5)),
+		EMA:     indicator.Trim(indicator.EMA(closes, 5)),
+		WMA:     indicator.Trim(indicator.WMA(closes, 5)),
+		MACD:    indicator.Trim(macd),
+		RSI:     indicator.Trim(indicator.RSI(closes, 5)),
+		Predict: predict,
+	}
+}
+
+const defaultSystem = "你是一个专业的金融量化分析师。我会给你{{if eq .Asset \"fund\"}}基金{{else}}股票{{end}}「{{.Name}}」一定时间内的收盘价、简单移动平均线、指数移动平均线、加权移动平均线、" +
+	"指数平滑异同平均线、相对强弱指标，请你分析这些数据并用中文回答我。回答的时候先给出结论（只需要一句话标明之后是什么情况），" +
+	"{{if .Predict}}我会给到你我的预测值predict（在结论中展示这个数据），然后结合我的预测值以及其他数据再进行分析并解释。{{else}}然后结合其他数据再进行分析并解释。{{end}}" +
+	"{{if .Context}}context中附有{{range $i, $s := .Context}}{{if $i}}、{{end}}{{$s.Title}}{{end}}等参考资料，请结合这些资料分析，不要引用资料中没有的数据。{{end}}"
+
+const defaultUser = "{{json .}}"
+
+// defaultPrompts 各资产类型的默认模板，可通过 LLM.Prompts 逐项覆盖
+var defaultPrompts = map[string]config.PromptConfig{
+	public.AssetStock: {System: defaultSystem, User: defaultUser},
+	public.AssetFund:  {System: defaultSystem, User: defaultUser},
+}
+
+var funcs = template.FuncMap{
+	"json": func(v any) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+}
+
+// prompt 资产类型实际使用的模板
+func prompt(asset string) (config.PromptConfig, error) {
+	p, ok := defaultPrompts[asset]
+	if !ok {
+		return p, fmt.Errorf("llm: no prompt for asset %q", asset)
+	}
+	if override, ok := config.Configs.LLM.Prompts[asset]; ok {
+		if override.System != "" {
+			p.System = override.System
+		}
+		if override.User != "" {
+			p.User = override.User
+		}
+	}
+	return p, nil
+}
+
+// PromptVersion 模板内容与所选参考资料的摘要，模板修改后缓存的分析随之失效
+func PromptVersion(asset string, sections []string) (string, error) {
+	p, err := prompt(asset)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(p.System + "\x00" + p.User + "\x00" + strings.Join(sections, ",")))
+	return hex.EncodeToString(sum[:])[:12], nil
+}
+
+// Render 按资产类型的模板生成系统消息与用户消息
+func Render(asset string, data any) ([]Message, error) {
+	p, err := prompt(asset)
+	if err != nil {
+		return nil, err
+	}
+
+	system, err := execute(asset+".system", p.System, data)
+	if err != nil {
+		return nil, err
+	}
+	user, err := execute(asset+".user", p.User, data)
+	if err != nil {
+		return nil, err
+	}
+	return []Message{
+		{Role: "system", Content: system},
+		{Role: "user", Content: user},
+	}, nil
+}
+
+func execute(name, text string, data any) (string, error) {
+	t, err := template.New(name).Funcs(funcs).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("llm: parse prompt %s: %w", name, err)
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("llm: render prompt %s: %w", name, err)
+	}
+	return buf.String(), nil
+}
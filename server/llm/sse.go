@@ -0,0 +1,73 @@
+package llm
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"strings"
+)
+
+// SSE 事件名称
+const (
+	EventDelta = "delta" // data: {"content": "增量文本"}
+	EventDone  = "done"  // data: {"content": "完整回复"}
+	EventError = "error" // data: {"message": "错误信息"}
+)
+
+// Delta delta 与 done 事件的数据
+type Delta struct {
+	Content string `json:"content"`
+	Cached  bool   `json:"cached,omitempty"` // 回放的缓存分析
+}
+
+// Error error 事件的数据
+type Error struct {
+	Message string `json:"message"`
+}
+
+// Stream 调用模型并以 SSE 推送回复，返回完整回复
+// 出错时推送 error 事件并返回已收到的部分，不再推送 done 事件
+func Stream(c *gin.Context, p Provider, req *Request) (string, error) {
+	setHeader(c)
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), Timeout())
+	defer cancel()
+
+	var content strings.Builder
+	err := p.Stream(ctx, req, func(delta string) error {
+		content.WriteString(delta)
+		c.SSEvent(EventDelta, &Delta{Content: delta})
+		c.Writer.Flush()
+		return nil
+	})
+	if err != nil {
+		c.SSEvent(EventError, &Error{Message: "分析失败，请稍后再试"})
+		c.Writer.Flush()
+		return content.String(), err
+	}
+
+	c.SSEvent(EventDone, &Delta{Content: content.String()})
+	c.Writer.Flush()
+	return content.String(), nil
+}
+
+// replayChunk 回放时每个 delta 事件的字数
+const replayChunk = 32
+
+// Replay 以与 Stream 相同的事件格式推送已保存的回复
+func Replay(c *gin.Context, content string) {
+	setHeader(c)
+
+	runes := []rune(content)
+	for i := 0; i < len(runes); i += replayChunk {
+		c.SSEvent(EventDelta, &Delta{Content: string(runes[i:min(i+replayChunk, len(runes))]), Cached: true})
+	}
+	c.SSEvent(EventDone, &Delta{Content: content, Cached: true})
+	c.Writer.Flush()
+}
+
+func setHeader(c *gin.Context) {
+	c.Writer.Header().Set("Content-Type", "text/event-stream")
+	c.Writer.Header().Set("Cache-Control", "no-cache")
+	c.Writer.Header().Set("Connection", "keep-alive")
+	c.Writer.Header().Set("X-Accel-Buffering", "no")
+}
@@ -0,0 +1,221 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"financia/config"
+	"financia/public"
+	"financia/public/db/dao"
+	"financia/public/db/model"
+	"financia/server/notify"
+	"go.uber.org/zap"
+	"time"
+)
+
+const (
+	// notifyInterval 定时检查待重试消息的间隔，新消息入队时立即发送
+	notifyInterval = 30 * time.Second
+	// notifyBatch 每次取出的待发送记录数
+	notifyBatch = 100
+	// notifySendTimeout 单条消息的发送超时
+	notifySendTimeout = 30 * time.Second
+	// webhookTimeout 未配置时 webhook 请求的超时
+	webhookTimeout = 10 * time.Second
+)
+
+// defaultChannels 未配置时提醒、摘要等消息的发送渠道
+var defaultChannels = []string{notify.ChannelEmail, notify.ChannelInbox}
+
+var notifyWake = make(chan struct{}, 1)
+
+func init() {
+	cfg := &config.Configs
+	if cfg.Email.Provider == "memory" {
+		notify.Register(&notify.Memory{})
+	} else {
+		notify.Register(notify.NewSMTP(cfg.Email.Server, cfg.Email.Port, cfg.Email.User, cfg.Email.Password))
+	}
+	if cfg.Notify.WebhookURL != "" {
+		timeout := cfg.Notify.WebhookTimeout
+		if timeout <= 0 {
+			timeout = webhookTimeout
+		}
+		notify.Register(notify.NewWebhook(cfg.Notify.WebhookURL, cfg.Notify.WebhookSecret, timeout))
+	}
+	notify.Register(notify.NewInbox(saveInbox))
+}
+
+func saveInbox(ctx context.Context, msg *notify.Message) error {
+	return dao.CreateInboxMessage(ctx, &model.InboxMessage{
+		UserId:  msg.UserId,
+		Kind:    msg.Kind,
+		Subject: msg.Subject,
+		Text:    msg.Text,
+		HTML:    msg.HTML,
+	})
+}
+
+// Notify 按模板渲染消息，为每个渠道保存一条待发送记录，由 NotifyWorker 异步发送
+// channels 为空时使用配置的默认渠道；未配置的渠道、没有收件地址的邮件与没有用户的站内信跳过
+func Notify(ctx context.Context, userId int64, to, kind string, data any, channels ...string) error {
+	msg, err := notify.Render(kind, data)
+	if err != nil {
+		return err
+	}
+	if len(channels) == 0 {
+		channels = config.Configs.Notify.Channels
+	}
+	if len(channels) == 0 {
+		channels = defaultChannels
+	}
+
+	now := time.Now()
+	list := make([]*model.Notification, 0, len(channels))
+	for _, channel := range channels {
+		if _, err := notify.Lookup(channel); err != nil {
+			zap.S().Errorf("[Notify] [Lookup] [err] = %s", err.Error())
+			continue
+		}
+		if (channel == notify.ChannelEmail && to == "") || (channel == notify.ChannelInbox && userId == 0) {
+			continue
+		}
+		list = append(list, &model.Notification{
+			UserId:        userId,
+			Channel:       channel,
+			Kind:          kind,
+			Target:        to,
+			Subject:       msg.Subject,
+			Text:          msg.Text,
+			HTML:          msg.HTML,
+			Unsubscribe:   msg.Unsubscribe,
+			Status:        public.NotifyStatusPending,
+			NextAttemptAt: now,
+		})
+	}
+	if len(list) == 0 {
+		return nil
+	}
+	if err := dao.CreateNotifications(ctx, list); err != nil {
+		return err
+	}
+
+	select {
+	case notifyWake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// SendCode 立即发送验证码邮件，不经过队列也不重试，超时不超过验证码的有效期
+// 发送记录只保存标题与结果，不保存含验证码的正文
+func SendCode(ctx context.Context, to, code string, ttl time.Duration) error {
+	msg, err := notify.Render(notify.KindCode, &notify.CodeData{Code: code, Minutes: max(1, int(ttl/time.Minute))})
+	if err != nil {
+		return err
+	}
+	msg.To = to
+	notifier, err := notify.Lookup(notify.ChannelEmail)
+	if err != nil {
+		return err
+	}
+
+	sendCtx, cancel := context.WithTimeout(ctx, min(notifySendTimeout, ttl))
+	err = notifier.Send(sendCtx, msg)
+	cancel()
+
+	now := time.Now()
+	record := &model.Notification{
+		Channel:       notify.ChannelEmail,
+		Kind:          notify.KindCode,
+		Target:        to,
+		Subject:       msg.Subject,
+		Status:        public.NotifyStatusSent,
+		Attempts:      1,
+		NextAttemptAt: now,
+		SentAt:        &now,
+	}
+	if err != nil {
+		record.Status, record.LastError, record.SentAt = public.NotifyStatusFailed, truncate(err.Error(), 500), nil
+	}
+	if err := dao.CreateNotifications(ctx, []*model.Notification{record}); err != nil {
+		zap.S().Errorf("[SendCode] [CreateNotifications] [err] = %s", err.Error())
+	}
+	return err
+}
+
+// NotifyWorker 发送待发送的消息，失败的按退避时间重试，超过次数后标记为失败
+// 记录保存在数据库中，服务重启后未发送的消息继续发送
+func NotifyWorker() {
+	ticker := time.NewTicker(notifyInterval)
+	defer ticker.Stop()
+	for {
+		deliverNotifications()
+		select {
+		case <-notifyWake:
+		case <-ticker.C:
+		}
+	}
+}
+
+func deliverNotifications() {
+	ctx := context.Background()
+	for {
+		list, err := dao.GetDueNotifications(ctx, time.Now(), notifyBatch)
+		if err != nil {
+			zap.S().Errorf("[NotifyWorker] [GetDueNotifications] [err] = %s", err.Error())
+			return
+		}
+		for _, v := range list {
+			deliver(ctx, v)
+		}
+		if len(list) < notifyBatch {
+			return
+		}
+	}
+}
+
+func deliver(ctx context.Context, n *model.Notification) {
+	err := send(ctx, n)
+	n.Attempts++
+	if err == nil {
+		now := time.Now()
+		n.Status, n.LastError, n.SentAt = public.NotifyStatusSent, "", &now
+	} else {
+		zap.S().Errorf("[NotifyWorker] [send] [%d %s] [err] = %s", n.Id, n.Channel, err.Error())
+		n.LastError = truncate(err.Error(), 500)
+		if n.Attempts >= notify.MaxAttempts || errors.Is(err, notify.ErrNotConfigured) {
+			n.Status = public.NotifyStatusFailed
+		} else {
+			n.NextAttemptAt = time.Now().Add(notify.Backoff(n.Attempts))
+		}
+	}
+	if err := dao.UpdateNotification(ctx, n); err != nil {
+		zap.S().Errorf("[NotifyWorker] [UpdateNotification] [err] = %s", err.Error())
+	}
+}
+
+func send(ctx context.Context, n *model.Notification) error {
+	notifier, err := notify.Lookup(n.Channel)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, notifySendTimeout)
+	defer cancel()
+	return notifier.Send(ctx, &notify.Message{
+		UserId:      n.UserId,
+		To:          n.Target,
+		Kind:        n.Kind,
+		Subject:     n.Subject,
+		Text:        n.Text,
+		HTML:        n.HTML,
+		Unsubscribe: n.Unsubscribe,
+	})
+}
+
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
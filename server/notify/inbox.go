@@ -0,0 +1,26 @@
+package notify
+
+import (
+	"context"
+	"errors"
+)
+
+// Inbox 站内信，由 save 保存到用户的收件箱
+type Inbox struct {
+	save func(ctx context.Context, msg *Message) error
+}
+
+func NewInbox(save func(ctx context.Context, msg *Message) error) *Inbox {
+	return &Inbox{save: save}
+}
+
+func (*Inbox) Name() string {
+	return ChannelInbox
+}
+
+func (i *Inbox) Send(ctx context.Context, msg *Message) error {
+	if msg.UserId == 0 {
+		return errors.New("notify: inbox message without user")
+	}
+	return i.save(ctx, msg)
+}
@@ -0,0 +1,77 @@
+// Package notify 消息通知
+//
+// Notifier 屏蔽具体的发送渠道，邮件（SMTP）、通用 Webhook 与站内信均实现该接口，
+// 本地与测试可用 Memory 代替 SMTP。消息的标题与正文由内置模板渲染为纯文本与 HTML 两种格式，
+// 发送由 server 中的队列异步完成，每条消息保存发送状态，失败后按 Backoff 重试。
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// 发送渠道
+const (
+	ChannelEmail   = "email"
+	ChannelWebhook = "webhook"
+	ChannelInbox   = "inbox"
+)
+
+// MaxAttempts 一条消息最多的发送次数，之后不再重试
+const MaxAttempts = 5
+
+// ErrNotConfigured 渠道未配置
+var ErrNotConfigured = errors.New("notify: channel not configured")
+
+// Message 一条待发送的消息
+type Message struct {
+	UserId  int64  // 站内信与 Webhook 使用，未注册的用户为 0
+	To      string // 邮件的收件地址
+	Kind    string // 模板名称
+	Subject string
+	Text    string
+	HTML    string
+	// Unsubscribe 一键退订地址，邮件按 RFC 8058 带 List-Unsubscribe 与 List-Unsubscribe-Post 头
+	Unsubscribe string
+}
+
+// Notifier 发送渠道，Name 为渠道名称
+type Notifier interface {
+	Name() string
+	Send(ctx context.Context, msg *Message) error
+}
+
+var (
+	mu       sync.RWMutex
+	registry = make(map[string]Notifier)
+)
+
+// Register 注册渠道，同名渠道后注册的覆盖先注册的
+func Register(n Notifier) {
+	mu.Lock()
+	registry[n.Name()] = n
+	mu.Unlock()
+}
+
+// Lookup 按名称获取渠道
+func Lookup(name string) (Notifier, error) {
+	mu.RLock()
+	n, ok := registry[name]
+	mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrNotConfigured, name)
+	}
+	return n, nil
+}
+
+// Backoff 第 attempt 次发送失败后到下次重试的间隔，从 30 秒开始翻倍，最长 1 小时
+func Backoff(attempt int) time.Duration {
+	d := 30 * time.Second
+	for i := 1; i < attempt && d < time.Hour; i++ {
+		d *= 2
+	}
+	return min(d, time.Hour)
+}
@@ -0,0 +1,142 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRender(t *testing.T) {
+	predict := 10.5
+	cases := []struct {
+		kind string
+		data any
+		want []string // 纯文本与 HTML 中都应出现的内容
+	}{
+		{KindCode, &CodeData{Code: "123456", Minutes: 1}, []string{"123456"}},
+		{KindAlert, &AlertData{Items: []*AlertItem{{Date: "2024-03-01", Message: "平安银行 <突破>"}}}, []string{"2024-03-01"}},
+		{KindDigest, &DigestData{
+			Date:           "2024-03-01",
+			Stocks:         []*DigestItem{{Name: "平安银行", TsCode: "000001.SZ", Close: 10, PctChg: 1.5, Predict: &predict, Direction: "看涨"}},
+			Funds:          []*DigestItem{{Name: "沪深300ETF", TsCode: "510300.SH", Close: 3.9, PctChg: -0.2}},
+			UnsubscribeURL: "https://example.com/unsubscribe?token=abc",
+		}, []string{"000001.SZ", "1.50%", "10.50", "-0.20%", "token=abc"}},
+	}
+	for _, c := range cases {
+		msg, err := Render(c.kind, c.data)
+		if err != nil {
+			t.Fatalf("%s: %v", c.kind, err)
+		}
+		if msg.Subject == "" || strings.Contains(msg.Subject, "\n") {
+			t.Errorf("%s: bad subject %q", c.kind, msg.Subject)
+		}
+		for _, w := range c.want {
+			if !strings.Contains(msg.Text, w) || !strings.Contains(msg.HTML, w) {
+				t.Errorf("%s: %q missing in text %q or html", c.kind, w, msg.Text)
+			}
+		}
+	}
+
+	// 只有摘要带一键退订地址
+	msg, _ := Render(KindDigest, cases[2].data)
+	if msg.Unsubscribe != "https://example.com/unsubscribe?token=abc" {
+		t.Errorf("unsubscribe = %q", msg.Unsubscribe)
+	}
+	if msg, _ := Render(KindCode, cases[0].data); msg.Unsubscribe != "" {
+		t.Errorf("code unsubscribe = %q", msg.Unsubscribe)
+	}
+
+	// HTML 正文转义消息内容，纯文本保持原样
+	msg, _ = Render(KindAlert, &AlertData{Items: []*AlertItem{{Date: "2024-03-01", Message: "<b>"}}})
+	if strings.Contains(msg.HTML, "<b>") || !strings.Contains(msg.Text, "<b>") {
+		t.Errorf("escaping: text %q html %q", msg.Text, msg.HTML)
+	}
+
+	if _, err := Render("missing", nil); err == nil {
+		t.Error("unknown kind should fail")
+	}
+}
+
+func TestWebhook(t *testing.T) {
+	var (
+		body []byte
+		sig  string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		sig = r.Header.Get(SignatureHeader)
+		if strings.Contains(string(body), "fail") {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer srv.Close()
+
+	w := NewWebhook(srv.URL, "secret", time.Second)
+	if err := w.Send(context.Background(), &Message{UserId: 1, Kind: KindAlert, Subject: "s", Text: "t"}); err != nil {
+		t.Fatal(err)
+	}
+	var got webhookBody
+	if err := json.Unmarshal(body, &got); err != nil || got.UserId != 1 || got.Subject != "s" {
+		t.Errorf("body = %s, err = %v", body, err)
+	}
+	if sig != "sha256="+Sign("secret", body) {
+		t.Errorf("signature = %q", sig)
+	}
+
+	if err := w.Send(context.Background(), &Message{Text: "fail"}); err == nil {
+		t.Error("non-2xx status should fail")
+	}
+}
+
+func TestMemoryAndInbox(t *testing.T) {
+	m := &Memory{}
+	Register(m)
+	n, err := Lookup(ChannelEmail)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := n.Send(context.Background(), &Message{To: "a@example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	if sent := m.Sent(); len(sent) != 1 || sent[0].To != "a@example.com" {
+		t.Errorf("sent = %+v", sent)
+	}
+	m.Err = errors.New("down")
+	if err := n.Send(context.Background(), &Message{To: "b@example.com"}); err == nil || len(m.Sent()) != 1 {
+		t.Errorf("failing memory: err = %v, sent = %d", err, len(m.Sent()))
+	}
+
+	var saved []*Message
+	inbox := NewInbox(func(ctx context.Context, msg *Message) error {
+		saved = append(saved, msg)
+		return nil
+	})
+	if err := inbox.Send(context.Background(), &Message{}); err == nil {
+		t.Error("inbox without user should fail")
+	}
+	if err := inbox.Send(context.Background(), &Message{UserId: 2}); err != nil || len(saved) != 1 {
+		t.Errorf("inbox: err = %v, saved = %d", err, len(saved))
+	}
+
+	if _, err := Lookup("sms"); !errors.Is(err, ErrNotConfigured) {
+		t.Errorf("unknown channel: got %v", err)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	want := []time.Duration{30 * time.Second, time.Minute, 2 * time.Minute, 4 * time.Minute}
+	for i, w := range want {
+		if got := Backoff(i + 1); got != w {
+			t.Errorf("Backoff(%d) = %v, want %v", i+1, got, w)
+		}
+	}
+	if got := Backoff(20); got != time.Hour {
+		t.Errorf("Backoff(20) = %v, want 1h", got)
+	}
+}
@@ -0,0 +1,79 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"gopkg.in/gomail.v2"
+	"sync"
+)
+
+// SMTP 通过 SMTP 服务器发送邮件，465 端口使用 SSL，其余端口支持时使用 STARTTLS，均校验服务器证书
+type SMTP struct {
+	dialer *gomail.Dialer
+	from   string
+}
+
+func NewSMTP(host string, port int, user, password string) *SMTP {
+	return &SMTP{dialer: gomail.NewDialer(host, port, user, password), from: user}
+}
+
+func (*SMTP) Name() string {
+	return ChannelEmail
+}
+
+func (s *SMTP) Send(ctx context.Context, msg *Message) error {
+	if msg.To == "" {
+		return errors.New("notify: empty recipient")
+	}
+	m := gomail.NewMessage()
+	m.SetHeader("From", s.from)
+	m.SetHeader("To", msg.To)
+	m.SetHeader("Subject", msg.Subject)
+	if msg.Unsubscribe != "" {
+		m.SetHeader("List-Unsubscribe", "<"+msg.Unsubscribe+">")
+		m.SetHeader("List-Unsubscribe-Post", "List-Unsubscribe=One-Click")
+	}
+	m.SetBody("text/plain", msg.Text)
+	if msg.HTML != "" {
+		m.AddAlternative("text/html", msg.HTML)
+	}
+
+	// gomail 不支持 context，超时由连接的超时控制
+	done := make(chan error, 1)
+	go func() { done <- s.dialer.DialAndSend(m) }()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Memory 保存在内存中的邮件渠道，用于测试与本地调试，代替 SMTP
+type Memory struct {
+	Err error // 不为空时发送失败并返回该错误
+
+	mu   sync.Mutex
+	sent []*Message
+}
+
+func (*Memory) Name() string {
+	return ChannelEmail
+}
+
+func (m *Memory) Send(ctx context.Context, msg *Message) error {
+	if m.Err != nil {
+		return m.Err
+	}
+	m.mu.Lock()
+	m.sent = append(m.sent, msg)
+	m.mu.Unlock()
+	return nil
+}
+
+// Sent 已发送的邮件
+func (m *Memory) Sent() []*Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]*Message(nil), m.sent...)
+}
@@ -0,0 +1,90 @@
+package notify
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+)
+
+// 消息模板
+const (
+	KindCode   = "code"   // 注册验证码，数据为 CodeData
+	KindAlert  = "alert"  // 提醒触发，数据为 AlertData
+	KindDigest = "digest" // 每日摘要，数据为 DigestData
+)
+
+// CodeData 验证码
+type CodeData struct {
+	Code    string
+	Minutes int // 有效期，单位分钟
+}
+
+// AlertData 同一用户本次触发的提醒
+type AlertData struct {
+	Items []*AlertItem
+}
+
+type AlertItem struct {
+	Date    string
+	Message string
+}
+
+// DigestData 关注的股票、基金的每日摘要
+type DigestData struct {
+	Date           string
+	Stocks         []*DigestItem
+	Funds          []*DigestItem
+	Signals        []*AlertItem // 当日触发的提醒
+	UnsubscribeURL string
+}
+
+type DigestItem struct {
+	Name      string
+	TsCode    string
+	Close     float64
+	PctChg    float64  // 涨跌幅（%）
+	Predict   *float64 // 下一交易日的预测收盘价，未缓存时为空
+	Direction string   // 看涨、看跌或持平
+}
+
+//go:embed templates
+var templateFS embed.FS
+
+var funcs = map[string]any{
+	"price": func(v float64) string { return fmt.Sprintf("%.2f", v) },
+	"pct":   func(v float64) string { return fmt.Sprintf("%+.2f%%", v) },
+	"deref": func(v *float64) float64 { return *v },
+}
+
+// 纯文本模板中的 <kind>.subject 与 <kind>.text 分别为标题与纯文本正文，HTML 模板中的 <kind>.html 为 HTML 正文
+var (
+	textTemplates = texttemplate.Must(texttemplate.New("").Funcs(funcs).ParseFS(templateFS, "templates/*.txt"))
+	htmlTemplates = htmltemplate.Must(htmltemplate.New("").Funcs(funcs).ParseFS(templateFS, "templates/*.html"))
+)
+
+// Render 按模板渲染消息的标题与正文
+func Render(kind string, data any) (*Message, error) {
+	var subject, text, html bytes.Buffer
+	if err := textTemplates.ExecuteTemplate(&subject, kind+".subject", data); err != nil {
+		return nil, fmt.Errorf("notify: render %s subject: %w", kind, err)
+	}
+	if err := textTemplates.ExecuteTemplate(&text, kind+".text", data); err != nil {
+		return nil, fmt.Errorf("notify: render %s text: %w", kind, err)
+	}
+	if err := htmlTemplates.ExecuteTemplate(&html, kind+".html", data); err != nil {
+		return nil, fmt.Errorf("notify: render %s html: %w", kind, err)
+	}
+	msg := &Message{
+		Kind:    kind,
+		Subject: strings.TrimSpace(subject.String()),
+		Text:    strings.TrimSpace(text.String()) + "\n",
+		HTML:    html.String(),
+	}
+	if d, ok := data.(*DigestData); ok {
+		msg.Unsubscribe = d.UnsubscribeURL
+	}
+	return msg, nil
+}
@@ -0,0 +1,73 @@
+package notify
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/go-resty/resty/v2"
+	"time"
+)
+
+// SignatureHeader 配置了密钥时请求体的 HMAC-SHA256 签名
+const SignatureHeader = "X-Financia-Signature"
+
+// Webhook 以 JSON POST 到固定地址，2xx 视为成功
+type Webhook struct {
+	url    string
+	secret string
+	client *resty.Client
+}
+
+func NewWebhook(url, secret string, timeout time.Duration) *Webhook {
+	return &Webhook{url: url, secret: secret, client: resty.New().SetTimeout(timeout)}
+}
+
+func (*Webhook) Name() string {
+	return ChannelWebhook
+}
+
+type webhookBody struct {
+	UserId  int64  `json:"userId"`
+	Kind    string `json:"kind"`
+	Subject string `json:"subject"`
+	Text    string `json:"text"`
+	HTML    string `json:"html"`
+}
+
+func (w *Webhook) Send(ctx context.Context, msg *Message) error {
+	body, err := json.Marshal(&webhookBody{
+		UserId:  msg.UserId,
+		Kind:    msg.Kind,
+		Subject: msg.Subject,
+		Text:    msg.Text,
+		HTML:    msg.HTML,
+	})
+	if err != nil {
+		return err
+	}
+
+	req := w.client.R().SetContext(ctx).
+		SetHeader("Content-Type", "application/json").
+		SetBody(body)
+	if w.secret != "" {
+		req.SetHeader(SignatureHeader, "sha256="+Sign(w.secret, body))
+	}
+	resp, err := req.Post(w.url)
+	if err != nil {
+		return err
+	}
+	if !resp.IsSuccess() {
+		return fmt.Errorf("notify: webhook status %d", resp.StatusCode())
+	}
+	return nil
+}
+
+// Sign 请求体的 HMAC-SHA256 签名，十六进制
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
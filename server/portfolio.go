@@ -0,0 +1,118 @@
+package server
+
+import (
+	"context"
+	"financia/public"
+	"financia/public/db/dao"
+	"financia/public/db/model"
+	"financia/server/portfolio"
+	"financia/server/tushare"
+	"financia/util"
+	"fmt"
+	"go.uber.org/zap"
+	"sort"
+	"time"
+)
+
+func portfolioTransactions(list []*model.PortfolioTransaction) []*portfolio.Transaction {
+	txs := make([]*portfolio.Transaction, 0, len(list))
+	for _, v := range list {
+		txs = append(txs, &portfolio.Transaction{
+			Asset:    v.Asset,
+			TsCode:   v.TsCode,
+			Name:     v.Name,
+			Side:     v.Side,
+			Quantity: v.Quantity,
+			Price:    v.Price,
+			Fee:      v.Fee,
+			Date:     v.TradeDate,
+		})
+	}
+	return txs
+}
+
+// CheckPortfolio 检查交易记录中的卖出数量不超过当时的持仓
+// 新录入的交易与数据库读出的时区可能不同，只按年月日比较
+func CheckPortfolio(p *model.Portfolio, list []*model.PortfolioTransaction) error {
+	txs := portfolioTransactions(list)
+	for _, v := range txs {
+		y, m, d := v.Date.Date()
+		v.Date = time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	}
+	return portfolio.Check(txs, p.Method)
+}
+
+// PortfolioReport 按不复权收盘价逐日估值组合，基准指数获取失败时只记录日志
+// 不复权价格与实际成交价一致，分红送转不计入收益
+func PortfolioReport(ctx context.Context, p *model.Portfolio) (*portfolio.Report, error) {
+	list, err := dao.GetPortfolioTransactions(ctx, p.Id)
+	if err != nil {
+		return nil, err
+	}
+	txs := portfolioTransactions(list)
+	if len(txs) == 0 {
+		return portfolio.Evaluate(nil, p.Method, nil, p.Benchmark, nil)
+	}
+
+	start := txs[0].Date
+	for _, v := range txs {
+		if v.Date.Before(start) {
+			start = v.Date
+		}
+	}
+	from, end := start.Format(time.DateOnly), time.Now().Format(time.DateOnly)
+
+	prices := make(map[string][]*util.Bar)
+	for _, v := range txs {
+		if _, ok := prices[v.TsCode]; ok {
+			continue
+		}
+		var bars []*util.Bar
+		switch v.Asset {
+		case public.AssetStock:
+			data, err := dao.GetStockData(ctx, v.TsCode, from, end)
+			if err != nil {
+				return nil, err
+			}
+			bars = model.StockBars(data)
+		case public.AssetFund:
+			data, err := dao.GetFundData(ctx, v.TsCode, from, end)
+			if err != nil {
+				return nil, err
+			}
+			bars = model.FundBars(data)
+		default:
+			return nil, fmt.Errorf("unknown asset %q", v.Asset)
+		}
+		sort.Slice(bars, func(i, j int) bool {
+			return bars[i].Date.Before(bars[j].Date)
+		})
+		prices[v.TsCode] = bars
+	}
+
+	bench, err := indexBars(ctx, p.Benchmark, start)
+	if err != nil {
+		zap.S().Errorf("[PortfolioReport] [indexBars] [err] = %s", err.Error())
+	}
+	return portfolio.Evaluate(txs, p.Method, prices, p.Benchmark, bench)
+}
+
+// indexBars start 以来的指数收盘价，按日期升序，缓存到当天结束
+func indexBars(ctx context.Context, tsCode string, start time.Time) ([]*util.Bar, error) {
+	key := fmt.Sprintf(public.RedisKeyIndexDaily, tsCode, start.Format(util.TimeDateOnlyWithOutSep))
+	list, err := dao.CachedList(ctx, key, func(ctx context.Context) ([]*tushare.IndexDailyResp, error) {
+		return tushare.IndexDaily(ctx, tsCode, start.Format(util.TimeDateOnlyWithOutSep), time.Now().Format(util.TimeDateOnlyWithOutSep))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	bars := make([]*util.Bar, 0, len(list))
+	for _, v := range list {
+		bars = append(bars, &util.Bar{Date: v.TradeDate, Close: v.Close})
+	}
+	sort.Slice(bars, func(i, j int) bool {
+		return bars[i].Date.Before(bars[j].Date)
+	})
+	return bars, nil
+}
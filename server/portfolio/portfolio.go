@@ -0,0 +1,317 @@
+// Package portfolio 按交易记录推算持仓、成本与盈亏，并逐日按收盘价估值
+//
+// 成本可按先进先出（FIFO）或移动平均计算，买入手续费计入成本，卖出手续费从卖出所得中扣除。
+// 时间加权收益按成交金额扣除当日的资金进出：当日收益为 (收盘市值 - 当日净买入) / 前一日市值 - 1，
+// 空仓后首次买入的当日为 收盘市值 / 买入金额 - 1，再逐日连乘，不受买卖金额大小的影响。
+package portfolio
+
+import (
+	"errors"
+	"financia/util"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// 成本计算方法
+const (
+	MethodFIFO    = "fifo"
+	MethodAverage = "average"
+)
+
+// 交易方向
+const (
+	SideBuy  = "buy"
+	SideSell = "sell"
+)
+
+// DefaultBenchmark 未指定时的基准指数，沪深300
+const DefaultBenchmark = "000300.SH"
+
+// ErrOversell 卖出数量超过当时的持仓
+var ErrOversell = errors.New("portfolio: sell quantity exceeds position")
+
+// Transaction 一笔买卖
+type Transaction struct {
+	Asset    string
+	TsCode   string
+	Name     string
+	Side     string
+	Quantity float64
+	Price    float64
+	Fee      float64
+	Date     time.Time
+}
+
+// amount 成交金额
+func (t *Transaction) amount() float64 {
+	return t.Quantity * t.Price
+}
+
+// Position 单个代码的持仓与盈亏，已清仓的代码数量为 0，只有已实现盈亏
+type Position struct {
+	Asset         string  `json:"asset"`
+	TsCode        string  `json:"tsCode"`
+	Name          string  `json:"name"`
+	Quantity      float64 `json:"quantity"`
+	CostBasis     float64 `json:"costBasis"` // 剩余持仓的成本，含买入手续费
+	AvgCost       float64 `json:"avgCost"`
+	Close         float64 `json:"close"` // 最新收盘价，没有行情时为最近一笔交易的价格
+	MarketValue   float64 `json:"marketValue"`
+	UnrealizedPnL float64 `json:"unrealizedPnl"`
+	RealizedPnL   float64 `json:"realizedPnl"`
+	Fees          float64 `json:"fees"`
+}
+
+// Point 每个交易日收盘后的估值
+type Point struct {
+	Date        string  `json:"date"`
+	MarketValue float64 `json:"marketValue"`
+	Return      float64 `json:"return"`    // 截至当日的时间加权收益
+	Benchmark   float64 `json:"benchmark"` // 基准同期收益
+}
+
+// Report 组合的盈亏与收益
+type Report struct {
+	Method          string      `json:"method"`
+	Start           string      `json:"start"`
+	End             string      `json:"end"`
+	MarketValue     float64     `json:"marketValue"`
+	CostBasis       float64     `json:"costBasis"`
+	RealizedPnL     float64     `json:"realizedPnl"`
+	UnrealizedPnL   float64     `json:"unrealizedPnl"`
+	Fees            float64     `json:"fees"`
+	TWR             float64     `json:"twr"` // 时间加权收益
+	Benchmark       string      `json:"benchmark"`
+	BenchmarkReturn float64     `json:"benchmarkReturn"`
+	Excess          float64     `json:"excess"` // TWR 减基准收益
+	Positions       []*Position `json:"positions"`
+	Equity          []*Point    `json:"equity"`
+}
+
+// lot 一批持仓，cost 为这一批的总成本
+type lot struct {
+	quantity float64
+	cost     float64
+}
+
+// holding 单个代码的持仓，移动平均法只有一批
+type holding struct {
+	position *Position
+	lots     []*lot
+	price    float64   // 最近的收盘价或成交价
+	traded   time.Time // 最近一笔交易的日期，之前的收盘价不再使用
+}
+
+func (h *holding) apply(t *Transaction, method string) error {
+	h.position.Fees += t.Fee
+	h.price, h.traded = t.Price, t.Date
+	if t.Side == SideBuy {
+		cost := t.amount() + t.Fee
+		if method == MethodAverage && len(h.lots) > 0 {
+			h.lots[0].quantity += t.Quantity
+			h.lots[0].cost += cost
+		} else {
+			h.lots = append(h.lots, &lot{quantity: t.Quantity, cost: cost})
+		}
+		h.position.Quantity += t.Quantity
+		return nil
+	}
+
+	// 浮点误差以内视为全部卖出
+	const epsilon = 1e-9
+	if t.Quantity > h.position.Quantity+epsilon {
+		return fmt.Errorf("%w: %s %s sell %v, hold %v", ErrOversell, t.Date.Format(time.DateOnly), t.TsCode, t.Quantity, h.position.Quantity)
+	}
+	var cost float64
+	remain := t.Quantity
+	for remain > epsilon && len(h.lots) > 0 {
+		l := h.lots[0]
+		if l.quantity <= remain+epsilon {
+			cost += l.cost
+			remain -= l.quantity
+			h.lots = h.lots[1:]
+			continue
+		}
+		part := l.cost * remain / l.quantity
+		cost += part
+		l.cost -= part
+		l.quantity -= remain
+		remain = 0
+	}
+	h.position.Quantity = max(0, h.position.Quantity-t.Quantity)
+	h.position.RealizedPnL += t.amount() - t.Fee - cost
+	return nil
+}
+
+func (h *holding) costBasis() float64 {
+	var cost float64
+	for _, l := range h.lots {
+		cost += l.cost
+	}
+	return cost
+}
+
+// sortTransactions 按日期升序，同一天保持录入顺序
+func sortTransactions(txs []*Transaction) []*Transaction {
+	list := append([]*Transaction(nil), txs...)
+	sort.SliceStable(list, func(i, j int) bool {
+		return dateOf(list[i].Date).Before(dateOf(list[j].Date))
+	})
+	return list
+}
+
+// Check 按日期回放交易，检查卖出数量不超过当时的持仓
+func Check(txs []*Transaction, method string) error {
+	holdings := make(map[string]*holding)
+	for _, t := range sortTransactions(txs) {
+		h, ok := holdings[t.TsCode]
+		if !ok {
+			h = &holding{position: &Position{TsCode: t.TsCode}}
+			holdings[t.TsCode] = h
+		}
+		if err := h.apply(t, method); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Evaluate 回放交易并按收盘价逐日估值
+// prices 为各代码按日期升序的 K 线，bench 为基准指数按日期升序的 K 线，均可缺失
+func Evaluate(txs []*Transaction, method string, prices map[string][]*util.Bar, benchmark string, bench []*util.Bar) (*Report, error) {
+	report := &Report{
+		Method:    method,
+		Benchmark: benchmark,
+		Positions: make([]*Position, 0),
+		Equity:    make([]*Point, 0),
+	}
+	txs = sortTransactions(txs)
+	if len(txs) == 0 {
+		return report, nil
+	}
+
+	dates := tradeDates(txs, prices, bench)
+	var (
+		holdings              = make(map[string]*holding)
+		order                 []*holding
+		cursor                = make(map[string]int)
+		next                  int // 下一笔未处理的交易
+		prevMV                float64
+		growth                = 1.0
+		benchIdx              int
+		benchBase, benchClose float64
+	)
+	for _, d := range dates {
+		var flow float64 // 当日净买入，含手续费
+		for ; next < len(txs) && !dateOf(txs[next].Date).After(d); next++ {
+			t := txs[next]
+			h, ok := holdings[t.TsCode]
+			if !ok {
+				h = &holding{position: &Position{Asset: t.Asset, TsCode: t.TsCode, Name: t.Name}}
+				holdings[t.TsCode] = h
+				order = append(order, h)
+			}
+			if err := h.apply(t, method); err != nil {
+				return nil, err
+			}
+			if t.Side == SideBuy {
+				flow += t.amount() + t.Fee
+			} else {
+				flow -= t.amount() - t.Fee
+			}
+		}
+
+		var mv float64
+		for _, h := range order {
+			bars := prices[h.position.TsCode]
+			i := cursor[h.position.TsCode]
+			for ; i < len(bars) && !dateOf(bars[i].Date).After(d); i++ {
+				if !dateOf(bars[i].Date).Before(dateOf(h.traded)) {
+					h.price = bars[i].Close
+				}
+			}
+			cursor[h.position.TsCode] = i
+			mv += h.position.Quantity * h.price
+		}
+
+		if prevMV > 0 {
+			growth *= (mv - flow) / prevMV
+		} else if flow > 0 {
+			growth *= mv / flow
+		}
+		prevMV = mv
+
+		for ; benchIdx < len(bench) && !dateOf(bench[benchIdx].Date).After(d); benchIdx++ {
+			benchClose = bench[benchIdx].Close
+		}
+		if benchBase == 0 {
+			benchBase = benchClose
+		}
+		point := &Point{Date: d.Format(time.DateOnly), MarketValue: mv, Return: growth - 1}
+		if benchBase > 0 {
+			point.Benchmark = benchClose/benchBase - 1
+		}
+		report.Equity = append(report.Equity, point)
+	}
+
+	for _, h := range order {
+		p := h.position
+		p.CostBasis = h.costBasis()
+		p.Close = h.price
+		p.MarketValue = p.Quantity * h.price
+		if p.Quantity > 0 {
+			p.AvgCost = p.CostBasis / p.Quantity
+			p.UnrealizedPnL = p.MarketValue - p.CostBasis
+		}
+		report.MarketValue += p.MarketValue
+		report.CostBasis += p.CostBasis
+		report.RealizedPnL += p.RealizedPnL
+		report.UnrealizedPnL += p.UnrealizedPnL
+		report.Fees += p.Fees
+		report.Positions = append(report.Positions, p)
+	}
+
+	last := report.Equity[len(report.Equity)-1]
+	report.Start = report.Equity[0].Date
+	report.End = last.Date
+	report.TWR = last.Return
+	report.BenchmarkReturn = last.Benchmark
+	report.Excess = report.TWR - report.BenchmarkReturn
+	return report, nil
+}
+
+// dateOf 日期部分，按各自时区的年月日归一为 UTC 零点，行情与交易的时区可能不同
+func dateOf(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// tradeDates 第一笔交易之后所有有行情或交易的日期
+func tradeDates(txs []*Transaction, prices map[string][]*util.Bar, bench []*util.Bar) []time.Time {
+	start := dateOf(txs[0].Date)
+	set := make(map[time.Time]struct{})
+	for _, t := range txs {
+		set[dateOf(t.Date)] = struct{}{}
+	}
+	add := func(bars []*util.Bar) {
+		for _, v := range bars {
+			if d := dateOf(v.Date); !d.Before(start) {
+				set[d] = struct{}{}
+			}
+		}
+	}
+	for _, bars := range prices {
+		add(bars)
+	}
+	add(bench)
+
+	dates := make([]time.Time, 0, len(set))
+	for d := range set {
+		dates = append(dates, d)
+	}
+	sort.Slice(dates, func(i, j int) bool {
+		return dates[i].Before(dates[j])
+	})
+	return dates
+}
@@ -0,0 +1,134 @@
+package portfolio
+
+import (
+	"errors"
+	"financia/util"
+	"math"
+	"testing"
+	"time"
+)
+
+var day0 = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func day(i int) time.Time {
+	return day0.AddDate(0, 0, i)
+}
+
+func closes(list ...float64) []*util.Bar {
+	bars := make([]*util.Bar, len(list))
+	for i, v := range list {
+		bars[i] = &util.Bar{Date: day(i), Close: v}
+	}
+	return bars
+}
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestCostMethods(t *testing.T) {
+	txs := []*Transaction{
+		{TsCode: "A", Side: SideBuy, Quantity: 100, Price: 10, Fee: 5, Date: day(0)},
+		{TsCode: "A", Side: SideBuy, Quantity: 100, Price: 12, Fee: 5, Date: day(1)},
+		{TsCode: "A", Side: SideSell, Quantity: 150, Price: 13, Fee: 10, Date: day(2)},
+	}
+	prices := map[string][]*util.Bar{"A": closes(10, 12, 13, 14)}
+
+	fifo, err := Evaluate(txs, MethodFIFO, prices, "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := fifo.Positions[0]
+	// 卖出 150 = 第一批 100（成本 1005）+ 第二批一半（成本 602.5）
+	if !near(p.RealizedPnL, 1950-10-1005-602.5) || !near(p.CostBasis, 602.5) || p.Quantity != 50 {
+		t.Errorf("fifo = %+v", p)
+	}
+	if !near(p.UnrealizedPnL, 50*14-602.5) || !near(p.Fees, 20) {
+		t.Errorf("fifo unrealized = %v, fees = %v", p.UnrealizedPnL, p.Fees)
+	}
+
+	avg, err := Evaluate(txs, MethodAverage, prices, "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p = avg.Positions[0]
+	// 平均成本 2210 / 200 = 11.05
+	if !near(p.AvgCost, 11.05) || !near(p.RealizedPnL, 1950-10-150*11.05) {
+		t.Errorf("average = %+v", p)
+	}
+	// 两种方法的总盈亏相同
+	if !near(fifo.RealizedPnL+fifo.UnrealizedPnL, avg.RealizedPnL+avg.UnrealizedPnL) {
+		t.Errorf("total pnl differs: %v, %v", fifo.RealizedPnL+fifo.UnrealizedPnL, avg.RealizedPnL+avg.UnrealizedPnL)
+	}
+}
+
+func TestOversell(t *testing.T) {
+	txs := []*Transaction{
+		{TsCode: "A", Side: SideSell, Quantity: 10, Price: 10, Date: day(1)},
+		{TsCode: "A", Side: SideBuy, Quantity: 10, Price: 10, Date: day(0)},
+	}
+	if err := Check(txs, MethodFIFO); err != nil {
+		t.Errorf("sorted by date, err = %v", err)
+	}
+	txs = append(txs, &Transaction{TsCode: "A", Side: SideSell, Quantity: 1, Price: 10, Date: day(2)})
+	if err := Check(txs, MethodFIFO); !errors.Is(err, ErrOversell) {
+		t.Errorf("err = %v, want ErrOversell", err)
+	}
+}
+
+func TestTimeWeightedReturn(t *testing.T) {
+	// 第 0 天买入 1000 元，第 1 天上涨 10% 并以收盘价追加 10 倍资金，第 2 天下跌 10%
+	txs := []*Transaction{
+		{TsCode: "A", Side: SideBuy, Quantity: 100, Price: 10, Date: day(0)},
+		{TsCode: "A", Side: SideBuy, Quantity: 1000, Price: 11, Date: day(1)},
+	}
+	prices := map[string][]*util.Bar{"A": closes(10, 11, 9.9)}
+	bench := closes(100, 101, 102)
+
+	r, err := Evaluate(txs, MethodFIFO, prices, DefaultBenchmark, bench)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 时间加权收益 1.1 * 0.9 - 1，与追加金额无关
+	if !near(r.TWR, 1.1*0.9-1) {
+		t.Errorf("twr = %v", r.TWR)
+	}
+	if !near(r.BenchmarkReturn, 0.02) || !near(r.Excess, r.TWR-0.02) {
+		t.Errorf("benchmark = %v, excess = %v", r.BenchmarkReturn, r.Excess)
+	}
+	if len(r.Equity) != 3 || !near(r.Equity[2].MarketValue, 1100*9.9) || r.Start != "2024-01-01" || r.End != "2024-01-03" {
+		t.Errorf("equity = %+v", r.Equity)
+	}
+}
+
+func TestMissingPrices(t *testing.T) {
+	// 没有行情的代码按成交价估值
+	txs := []*Transaction{{TsCode: "B", Side: SideBuy, Quantity: 10, Price: 2, Date: day(0)}}
+	r, err := Evaluate(txs, MethodFIFO, nil, "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.MarketValue != 20 || r.TWR != 0 || len(r.Equity) != 1 {
+		t.Errorf("report = %+v", r)
+	}
+}
+
+func TestMixedLocations(t *testing.T) {
+	// 交易日期按本地时区解析，行情为 UTC，指数又是本地时区，同一天不能拆成多个估值点
+	cst := time.FixedZone("CST", 8*3600)
+	local := func(i int) time.Time {
+		d := day(i)
+		return time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, cst)
+	}
+	txs := []*Transaction{{TsCode: "A", Side: SideBuy, Quantity: 100, Price: 10, Date: local(0)}}
+	prices := map[string][]*util.Bar{"A": closes(10, 11)}
+	bench := []*util.Bar{{Date: local(0), Close: 100}, {Date: local(1), Close: 101}}
+
+	r, err := Evaluate(txs, MethodFIFO, prices, "", bench)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Equity) != 2 || r.Start != "2024-01-01" || r.End != "2024-01-02" || !near(r.TWR, 0.1) || !near(r.BenchmarkReturn, 0.01) {
+		t.Errorf("equity = %+v, twr = %v, bench = %v", r.Equity, r.TWR, r.BenchmarkReturn)
+	}
+}
@@ -0,0 +1,173 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"financia/config"
+	"financia/public"
+	"financia/public/db/connector"
+	"financia/public/db/dao"
+	"financia/server/forecast"
+	"fmt"
+	"github.com/go-redis/redis/v8"
+	"go.uber.org/zap"
+	"sort"
+	"time"
+)
+
+const (
+	// predictWorkers 未配置时的预测工作协程数
+	predictWorkers = 4
+	// predictJobExpire 去重标记的有效期，工作协程异常退出时标记到期后可重新入队
+	predictJobExpire = 10 * time.Minute
+	// predictFailedExpire 失败状态保留的时间，到期后可重新入队
+	predictFailedExpire = time.Minute
+	// predictPopTimeout 队列为空时单次阻塞等待的时间
+	predictPopTimeout = 5 * time.Second
+	// predictTimeout 单个预测任务的最长运行时间
+	predictTimeout = time.Minute
+)
+
+// predictJob 队列中的预测任务
+type predictJob struct {
+	Asset     string `json:"asset"`
+	Id        int    `json:"id"`
+	TsCode    string `json:"tsCode"`
+	WindowEnd string `json:"windowEnd"` // 输入窗口的最后交易日
+}
+
+func (j *predictJob) key() string {
+	return fmt.Sprintf(public.RedisKeyPredictJob, j.Asset, j.TsCode, j.WindowEnd)
+}
+
+// EnqueuePredict 预测任务入队，返回任务状态
+// 同一代码、同一输入窗口的任务已在队列中或运行中时不重复入队，直接返回其当前状态
+func EnqueuePredict(ctx context.Context, asset string, id int, tsCode string) (string, error) {
+	var (
+		end time.Time
+		err error
+	)
+	switch asset {
+	case public.AssetStock:
+		end, err = dao.GetStockLastTradeDate(ctx, tsCode)
+	case public.AssetFund:
+		end, err = dao.GetFundLastTradeDate(ctx, tsCode)
+	default:
+		return "", fmt.Errorf("unknown asset %q", asset)
+	}
+	if err != nil {
+		return "", err
+	}
+	if end.IsZero() {
+		return "", fmt.Errorf("%s %s: no data", asset, tsCode)
+	}
+
+	job := &predictJob{Asset: asset, Id: id, TsCode: tsCode, WindowEnd: end.Format(time.DateOnly)}
+	data, err := json.Marshal(job)
+	if err != nil {
+		return "", err
+	}
+
+	rdb := connector.GetRedis().WithContext(ctx)
+	ok, err := rdb.SetNX(ctx, job.key(), public.JobStatusPending, predictJobExpire).Result()
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		status, err := rdb.Get(ctx, job.key()).Result()
+		if errors.Is(err, redis.Nil) {
+			// 标记恰好被工作协程删除，任务已完成
+			return public.JobStatusDone, nil
+		}
+		return status, err
+	}
+
+	if err := rdb.LPush(ctx, public.RedisKeyPredictQueue, data).Err(); err != nil {
+		rdb.Del(ctx, job.key())
+		return "", err
+	}
+	return public.JobStatusPending, nil
+}
+
+// PredictWorker 启动预测工作协程，从 Redis 队列中取任务运行
+func PredictWorker() {
+	n := config.Configs.Predictor.Workers
+	if n <= 0 {
+		n = predictWorkers
+	}
+	for i := 0; i < n; i++ {
+		go func() {
+			for {
+				popPredictJob()
+			}
+		}()
+	}
+}
+
+func popPredictJob() {
+	ctx := context.Background()
+	rdb := connector.GetRedis().WithContext(ctx)
+	result, err := rdb.BRPop(ctx, predictPopTimeout, public.RedisKeyPredictQueue).Result()
+	if errors.Is(err, redis.Nil) {
+		return
+	}
+	if err != nil {
+		zap.S().Errorf("[PredictWorker] [BRPop] [err] = %s", err.Error())
+		time.Sleep(predictPopTimeout)
+		return
+	}
+
+	var job predictJob
+	if err := json.Unmarshal([]byte(result[1]), &job); err != nil {
+		zap.S().Errorf("[PredictWorker] [json.Unmarshal] [err] = %s", err.Error())
+		return
+	}
+	rdb.SetXX(ctx, job.key(), public.JobStatusRunning, redis.KeepTTL)
+
+	if err := runPredictJob(&job); err != nil {
+		zap.S().Errorf("[PredictWorker] [%s %s] [err] = %s", job.Asset, job.TsCode, err.Error())
+		rdb.Set(ctx, job.key(), public.JobStatusFailed, predictFailedExpire)
+		return
+	}
+	rdb.Del(ctx, job.key())
+}
+
+func runPredictJob(job *predictJob) error {
+	ctx, cancel := context.WithTimeout(context.Background(), predictTimeout)
+	defer cancel()
+
+	switch job.Asset {
+	case public.AssetStock:
+		stockData, err := dao.GetStockDataLimit(ctx, job.TsCode, forecast.History())
+		if err != nil {
+			return err
+		}
+		if len(stockData) == 0 {
+			return errors.New("stockData is nil")
+		}
+		sort.Slice(stockData, func(i, j int) bool {
+			return stockData[i].TradeDate.Before(stockData[j].TradeDate)
+		})
+		// 与缓存的预测结果一致，使用前复权数据
+		if err := AdjustStockData(ctx, job.TsCode, stockData, public.AdjQfq); err != nil {
+			return err
+		}
+		_, err = forecast.Stock(ctx, job.Id, stockData)
+		return err
+	case public.AssetFund:
+		fundData, err := dao.GetFundDataLimit(ctx, job.TsCode, forecast.History())
+		if err != nil {
+			return err
+		}
+		if len(fundData) == 0 {
+			return errors.New("fundData is nil")
+		}
+		sort.Slice(fundData, func(i, j int) bool {
+			return fundData[i].TradeDate.Before(fundData[j].TradeDate)
+		})
+		_, err = forecast.Fund(ctx, job.Id, fundData)
+		return err
+	}
+	return fmt.Errorf("unknown asset %q", job.Asset)
+}
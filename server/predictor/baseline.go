@@ -0,0 +1,192 @@
+package predictor
+
+import (
+	"context"
+	"fmt"
+)
+
+// Naive 以最后一个收盘价作为预测值，区间按日涨跌的波动随步数扩大
+type Naive struct{}
+
+func (Naive) Name() string {
+	return "naive"
+}
+
+func (Naive) Version() string {
+	return "1"
+}
+
+func (n Naive) Predict(ctx context.Context, points []*Point) (float64, error) {
+	list, err := n.Forecast(ctx, points, []int{1})
+	if err != nil {
+		return 0, err
+	}
+	return list[0].Val, nil
+}
+
+func (Naive) Forecast(_ context.Context, points []*Point, horizons []int) ([]*Forecast, error) {
+	y := closes(points)
+	if len(y) == 0 {
+		return nil, ErrNotEnoughData
+	}
+
+	diffs := make([]float64, 0, len(y)-1)
+	for i := 1; i < len(y); i++ {
+		diffs = append(diffs, y[i]-y[i-1])
+	}
+	sigma := residualStd(diffs, 0)
+
+	list := make([]*Forecast, 0, len(horizons))
+	for _, h := range horizons {
+		list = append(list, interval(h, y[len(y)-1], sigma))
+	}
+	return list, nil
+}
+
+// Linear 对最近 Window 个收盘价做最小二乘线性回归并外推
+type Linear struct {
+	Window int
+}
+
+func (Linear) Name() string {
+	return "linear"
+}
+
+func (l Linear) Version() string {
+	return fmt.Sprintf("window=%d", l.Window)
+}
+
+func (l Linear) Predict(ctx context.Context, points []*Point) (float64, error) {
+	list, err := l.Forecast(ctx, points, []int{1})
+	if err != nil {
+		return 0, err
+	}
+	return list[0].Val, nil
+}
+
+func (l Linear) Forecast(_ context.Context, points []*Point, horizons []int) ([]*Forecast, error) {
+	y := closes(points)
+	if l.Window > 0 && len(y) > l.Window {
+		y = y[len(y)-l.Window:]
+	}
+	if len(y) < 2 {
+		return nil, ErrNotEnoughData
+	}
+
+	n := float64(len(y))
+	var sumX, sumY, sumXY, sumXX float64
+	for i, v := range y {
+		x := float64(i)
+		sumX += x
+		sumY += v
+		sumXY += x * v
+		sumXX += x * x
+	}
+
+	slope := (n*sumXY - sumX*sumY) / (n*sumXX - sumX*sumX)
+	intercept := (sumY - slope*sumX) / n
+
+	residuals := make([]float64, len(y))
+	for i, v := range y {
+		residuals[i] = v - (intercept + slope*float64(i))
+	}
+	sigma := residualStd(residuals, 2)
+
+	list := make([]*Forecast, 0, len(horizons))
+	for _, h := range horizons {
+		list = append(list, interval(h, intercept+slope*(n-1+float64(h)), sigma))
+	}
+	return list, nil
+}
+
+// HoltWinters 加法 Holt-Winters 三次指数平滑，Season 为季节长度（交易日）
+// 数据不足两个季节时退化为 Holt 双指数平滑
+type HoltWinters struct {
+	Alpha  float64 // 水平
+	Beta   float64 // 趋势
+	Gamma  float64 // 季节
+	Season int
+}
+
+func (HoltWinters) Name() string {
+	return "holtwinters"
+}
+
+func (h HoltWinters) Version() string {
+	return fmt.Sprintf("alpha=%g,beta=%g,gamma=%g,season=%d", h.Alpha, h.Beta, h.Gamma, h.Season)
+}
+
+func (h HoltWinters) Predict(ctx context.Context, points []*Point) (float64, error) {
+	list, err := h.Forecast(ctx, points, []int{1})
+	if err != nil {
+		return 0, err
+	}
+	return list[0].Val, nil
+}
+
+// Forecast 区间宽度取样本内一步预测误差的标准差
+func (h HoltWinters) Forecast(_ context.Context, points []*Point, horizons []int) ([]*Forecast, error) {
+	y := closes(points)
+	if len(y) < 2 {
+		return nil, ErrNotEnoughData
+	}
+
+	m := h.Season
+	if m < 2 || len(y) < 2*m {
+		return h.holt(y, horizons), nil
+	}
+
+	// 趋势取前两个季节均值之差
+	var first, second float64
+	for i := 0; i < m; i++ {
+		first += y[i]
+		second += y[m+i]
+	}
+	first /= float64(m)
+	second /= float64(m)
+
+	// 季节项取第一个季节去掉趋势后的偏差，水平对齐到第一个季节的最后一天
+	trend := (second - first) / float64(m)
+	center := float64(m-1) / 2
+	level := first + trend*center
+	season := make([]float64, m)
+	for i := 0; i < m; i++ {
+		season[i] = y[i] - (first + trend*(float64(i)-center))
+	}
+
+	errs := make([]float64, 0, len(y)-m)
+	for t := m; t < len(y); t++ {
+		s := season[t%m]
+		errs = append(errs, y[t]-(level+trend+s))
+		prevLevel := level
+		level = h.Alpha*(y[t]-s) + (1-h.Alpha)*(level+trend)
+		trend = h.Beta*(level-prevLevel) + (1-h.Beta)*trend
+		season[t%m] = h.Gamma*(y[t]-level) + (1-h.Gamma)*s
+	}
+	sigma := residualStd(errs, 0)
+
+	list := make([]*Forecast, 0, len(horizons))
+	for _, step := range horizons {
+		val := level + trend*float64(step) + season[(len(y)+step-1)%m]
+		list = append(list, interval(step, val, sigma))
+	}
+	return list, nil
+}
+
+func (h HoltWinters) holt(y []float64, horizons []int) []*Forecast {
+	level, trend := y[0], y[1]-y[0]
+	errs := make([]float64, 0, len(y)-1)
+	for _, v := range y[1:] {
+		errs = append(errs, v-(level+trend))
+		prevLevel := level
+		level = h.Alpha*v + (1-h.Alpha)*(level+trend)
+		trend = h.Beta*(level-prevLevel) + (1-h.Beta)*trend
+	}
+	sigma := residualStd(errs, 0)
+
+	list := make([]*Forecast, 0, len(horizons))
+	for _, step := range horizons {
+		list = append(list, interval(step, level+trend*float64(step), sigma))
+	}
+	return list
+}
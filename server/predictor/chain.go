@@ -0,0 +1,75 @@
+package predictor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.uber.org/zap"
+)
+
+// Chain 按顺序尝试多个预测器，返回第一个成功的结果
+type Chain struct {
+	list []Predictor
+}
+
+// NewChain 按名称构建回退链
+func NewChain(names ...string) (*Chain, error) {
+	if len(names) == 0 {
+		return nil, errors.New("predictor: empty chain")
+	}
+
+	c := &Chain{list: make([]Predictor, 0, len(names))}
+	for _, name := range names {
+		p, err := Lookup(name)
+		if err != nil {
+			return nil, err
+		}
+		c.list = append(c.list, p)
+	}
+	return c, nil
+}
+
+// Primary 链上第一个预测器的名称
+func (c *Chain) Primary() string {
+	return c.list[0].Name()
+}
+
+// Predict 返回预测值与实际使用的预测器名称
+func (c *Chain) Predict(ctx context.Context, points []*Point) (float64, string, error) {
+	var errs []error
+	for _, p := range c.list {
+		val, err := p.Predict(ctx, points)
+		if err == nil {
+			return val, p.Name(), nil
+		}
+		zap.S().Warnf("[Chain] [%s] [err] = %s", p.Name(), err.Error())
+		errs = append(errs, fmt.Errorf("%s: %w", p.Name(), err))
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return 0, "", errors.Join(errs...)
+}
+
+// Forecast 返回多步预测与实际使用的预测器名称，不支持多步预测的预测器直接跳过
+func (c *Chain) Forecast(ctx context.Context, points []*Point, horizons []int) ([]*Forecast, string, error) {
+	var errs []error
+	for _, p := range c.list {
+		f, ok := p.(Forecaster)
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s: %w", p.Name(), ErrNoHorizon))
+			continue
+		}
+
+		list, err := f.Forecast(ctx, points, horizons)
+		if err == nil {
+			return list, p.Name(), nil
+		}
+		zap.S().Warnf("[Chain] [%s] [err] = %s", p.Name(), err.Error())
+		errs = append(errs, fmt.Errorf("%s: %w", p.Name(), err))
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return nil, "", errors.Join(errs...)
+}
@@ -0,0 +1,61 @@
+package predictor
+
+import (
+	"context"
+	"math"
+)
+
+// MaxHorizon 多步预测的最远交易日数
+const MaxHorizon = 20
+
+// z95 正态分布 95% 置信区间的分位数
+const z95 = 1.96
+
+// Forecast 向后第 Horizon 个交易日的预测值与 95% 置信区间
+type Forecast struct {
+	Horizon int     `json:"horizon"`
+	Val     float64 `json:"val"`
+	Lower   float64 `json:"lower"`
+	Upper   float64 `json:"upper"`
+}
+
+// Forecaster 支持多步预测的预测器
+type Forecaster interface {
+	Predictor
+	// Forecast 按 horizons 的顺序返回各步的预测，horizons 均为正数
+	Forecast(ctx context.Context, points []*Point, horizons []int) ([]*Forecast, error)
+}
+
+// Path 1 到 n 步的完整预测路径
+func Path(n int) []int {
+	horizons := make([]int, n)
+	for i := range horizons {
+		horizons[i] = i + 1
+	}
+	return horizons
+}
+
+// interval 一步误差标准差为 sigma 时第 h 步的预测区间，误差按随机游走累积
+func interval(h int, val, sigma float64) *Forecast {
+	width := z95 * sigma * math.Sqrt(float64(h))
+	return &Forecast{
+		Horizon: h,
+		Val:     val,
+		Lower:   val - width,
+		Upper:   val + width,
+	}
+}
+
+// residualStd 残差的标准差，残差均值视为 0，dof 为模型参数占用的自由度
+func residualStd(list []float64, dof int) float64 {
+	n := len(list) - dof
+	if n <= 0 {
+		return 0
+	}
+
+	var sum float64
+	for _, v := range list {
+		sum += v * v
+	}
+	return math.Sqrt(sum / float64(n))
+}
@@ -0,0 +1,58 @@
+package predictor
+
+import "math"
+
+// Outcome 一次已实现的预测，Last 为预测时输入窗口最后一天的收盘价
+type Outcome struct {
+	Last    float64
+	Predict float64
+	Actual  float64
+}
+
+// Metrics 样本外准确率
+type Metrics struct {
+	Samples int
+	HitRate float64 // 方向命中率，0~1
+	MAE     float64 // 平均绝对误差
+	MAPE    float64 // 平均绝对百分比误差，0~1，跳过实际值为 0 的样本
+}
+
+// Evaluate 计算一组已实现预测的方向命中率、MAE 与 MAPE
+// 方向以相对 Last 的涨跌判断，预测与实际都持平也算命中
+func Evaluate(list []*Outcome) *Metrics {
+	m := &Metrics{Samples: len(list)}
+	if len(list) == 0 {
+		return m
+	}
+
+	var hits, pctCount int
+	var absSum, pctSum float64
+	for _, v := range list {
+		if sign(v.Predict-v.Last) == sign(v.Actual-v.Last) {
+			hits++
+		}
+		diff := math.Abs(v.Predict - v.Actual)
+		absSum += diff
+		if v.Actual != 0 {
+			pctSum += diff / math.Abs(v.Actual)
+			pctCount++
+		}
+	}
+
+	m.HitRate = float64(hits) / float64(len(list))
+	m.MAE = absSum / float64(len(list))
+	if pctCount > 0 {
+		m.MAPE = pctSum / float64(pctCount)
+	}
+	return m
+}
+
+func sign(v float64) int {
+	switch {
+	case v > 0:
+		return 1
+	case v < 0:
+		return -1
+	}
+	return 0
+}
@@ -0,0 +1,95 @@
+// Package predictor 收盘价预测
+//
+// Predictor 预测下一交易日收盘价，Forecaster 额外给出多步预测与置信区间。
+// 实现包括 python gRPC 服务与纯 Go 的基线模型，
+// 通过名称注册，按资产类型配置回退顺序（见 Chain）
+package predictor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrNotEnoughData 数据点不足以预测
+	ErrNotEnoughData = errors.New("predictor: not enough data")
+	// ErrUnavailable 预测服务不可用
+	ErrUnavailable = errors.New("predictor: unavailable")
+	// ErrNoHorizon 预测器不支持多步预测
+	ErrNoHorizon = errors.New("predictor: multi-horizon forecast not supported")
+)
+
+// Point 一根用于预测的日线，按日期升序传入
+type Point struct {
+	Date  time.Time
+	Open  float64
+	High  float64
+	Low   float64
+	Close float64
+	Vol   float64
+	// Features 命名特征，由 server/feature 构建，只有 python 等需要的预测器使用
+	Features map[string]float64
+}
+
+// Predictor 根据历史日线预测下一交易日收盘价
+type Predictor interface {
+	Name() string
+	Predict(ctx context.Context, points []*Point) (float64, error)
+}
+
+// Versioned 带版本号的预测器，版本随参数或模型权重变化，随预测结果一起记录
+type Versioned interface {
+	Version() string
+}
+
+var (
+	mu       sync.RWMutex
+	registry = map[string]Predictor{}
+)
+
+func init() {
+	Register(Naive{})
+	Register(Linear{Window: 30})
+	Register(HoltWinters{Alpha: 0.5, Beta: 0.1, Gamma: 0.1, Season: 5})
+}
+
+// Register 按名称注册预测器，同名覆盖
+func Register(p Predictor) {
+	mu.Lock()
+	registry[p.Name()] = p
+	mu.Unlock()
+}
+
+// Lookup 按名称获取预测器
+func Lookup(name string) (Predictor, error) {
+	mu.RLock()
+	p, ok := registry[name]
+	mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("predictor: unknown predictor %q", name)
+	}
+	return p, nil
+}
+
+// Version 已注册预测器的版本，未注册或未实现 Versioned 时为空
+func Version(name string) string {
+	p, err := Lookup(name)
+	if err != nil {
+		return ""
+	}
+	if v, ok := p.(Versioned); ok {
+		return v.Version()
+	}
+	return ""
+}
+
+func closes(points []*Point) []float64 {
+	list := make([]float64, len(points))
+	for i, v := range points {
+		list[i] = v.Close
+	}
+	return list
+}
@@ -0,0 +1,197 @@
+package predictor
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func series(closes ...float64) []*Point {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	points := make([]*Point, len(closes))
+	for i, v := range closes {
+		points[i] = &Point{Date: start.AddDate(0, 0, i), Close: v}
+	}
+	return points
+}
+
+func TestBaselines(t *testing.T) {
+	ctx := context.Background()
+	line := series(1, 2, 3, 4, 5, 6, 7, 8, 9, 10)
+
+	tests := []struct {
+		p    Predictor
+		want float64
+	}{
+		{Naive{}, 10},
+		{Linear{Window: 30}, 11},
+		{Linear{Window: 3}, 11},
+		// 完全线性的序列，季节项为常数偏移，预测仍沿趋势外推
+		{HoltWinters{Alpha: 0.5, Beta: 0.1, Gamma: 0.1, Season: 5}, 11},
+		{HoltWinters{Alpha: 0.5, Beta: 0.1, Season: 0}, 11},
+	}
+	for _, tt := range tests {
+		got, err := tt.p.Predict(ctx, line)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.p.Name(), err)
+		}
+		if math.Abs(got-tt.want) > 1e-6 {
+			t.Errorf("%s = %v, want %v", tt.p.Name(), got, tt.want)
+		}
+	}
+}
+
+func TestHoltWintersSeason(t *testing.T) {
+	// 周期为 5 的锯齿序列，下一个点应接近周期中对应位置的值
+	var closes []float64
+	for i := 0; i < 40; i++ {
+		closes = append(closes, 10+float64(i%5))
+	}
+	got, err := HoltWinters{Alpha: 0.5, Beta: 0.1, Gamma: 0.1, Season: 5}.Predict(context.Background(), series(closes...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(got-10) > 0.5 {
+		t.Errorf("holtwinters = %v, want about 10", got)
+	}
+}
+
+func TestForecastPath(t *testing.T) {
+	ctx := context.Background()
+	// 线性序列叠加交替扰动，残差不为 0，区间应随步数变宽
+	var closes []float64
+	for i := 0; i < 40; i++ {
+		closes = append(closes, 10+0.5*float64(i)+float64(i%2))
+	}
+	points := series(closes...)
+
+	for _, f := range []Forecaster{Naive{}, Linear{Window: 30}, HoltWinters{Alpha: 0.5, Beta: 0.1, Gamma: 0.1, Season: 5}} {
+		list, err := f.Forecast(ctx, points, Path(MaxHorizon))
+		if err != nil {
+			t.Fatalf("%s: %v", f.Name(), err)
+		}
+		if len(list) != MaxHorizon {
+			t.Fatalf("%s: len = %d, want %d", f.Name(), len(list), MaxHorizon)
+		}
+
+		val, _ := f.Predict(ctx, points)
+		if math.Abs(list[0].Val-val) > 1e-9 {
+			t.Errorf("%s: step 1 = %v, want Predict %v", f.Name(), list[0].Val, val)
+		}
+
+		prevWidth := 0.0
+		for i, v := range list {
+			if v.Horizon != i+1 {
+				t.Errorf("%s: horizon = %d, want %d", f.Name(), v.Horizon, i+1)
+			}
+			if !(v.Lower < v.Val && v.Val < v.Upper) {
+				t.Errorf("%s: step %d = %v not in [%v, %v]", f.Name(), v.Horizon, v.Val, v.Lower, v.Upper)
+			}
+			if width := v.Upper - v.Lower; width <= prevWidth {
+				t.Errorf("%s: step %d width %v not wider than %v", f.Name(), v.Horizon, width, prevWidth)
+			} else {
+				prevWidth = width
+			}
+		}
+	}
+}
+
+func TestForecastLinearExact(t *testing.T) {
+	// 完全线性的序列残差为 0，区间退化为点
+	list, err := Linear{}.Forecast(context.Background(), series(1, 2, 3, 4, 5), []int{1, 5, 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []float64{6, 10, 25} {
+		if math.Abs(list[i].Val-want) > 1e-9 || math.Abs(list[i].Upper-list[i].Lower) > 1e-9 {
+			t.Errorf("step %d = %+v, want %v", list[i].Horizon, list[i], want)
+		}
+	}
+}
+
+func TestNotEnoughData(t *testing.T) {
+	for _, p := range []Predictor{Naive{}, Linear{}, HoltWinters{}} {
+		if _, err := p.Predict(context.Background(), nil); !errors.Is(err, ErrNotEnoughData) {
+			t.Errorf("%s: err = %v, want ErrNotEnoughData", p.Name(), err)
+		}
+	}
+}
+
+type failing struct{}
+
+func (failing) Name() string { return "failing" }
+
+func (failing) Predict(context.Context, []*Point) (float64, error) {
+	return 0, ErrUnavailable
+}
+
+func TestChainFallback(t *testing.T) {
+	Register(failing{})
+
+	chain, err := NewChain("failing", "naive")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if chain.Primary() != "failing" {
+		t.Errorf("primary = %s", chain.Primary())
+	}
+
+	val, name, err := chain.Predict(context.Background(), series(1, 2, 3))
+	if err != nil || name != "naive" || val != 3 {
+		t.Errorf("chain = %v %s %v, want 3 naive", val, name, err)
+	}
+
+	list, name, err := chain.Forecast(context.Background(), series(1, 2, 3), []int{1, 5})
+	if err != nil || name != "naive" || len(list) != 2 || list[1].Val != 3 {
+		t.Errorf("chain forecast = %v %s %v, want naive", list, name, err)
+	}
+
+	chain, _ = NewChain("failing")
+	if _, _, err := chain.Forecast(context.Background(), series(1), []int{1}); !errors.Is(err, ErrNoHorizon) {
+		t.Errorf("err = %v, want ErrNoHorizon", err)
+	}
+	if _, _, err := chain.Predict(context.Background(), series(1)); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("err = %v, want ErrUnavailable", err)
+	}
+
+	if _, err := NewChain("missing"); err == nil {
+		t.Error("expected error for unknown predictor")
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	m := Evaluate([]*Outcome{
+		{Last: 10, Predict: 11, Actual: 12}, // 命中，误差 1
+		{Last: 10, Predict: 9, Actual: 11},  // 方向错误，误差 2
+		{Last: 10, Predict: 10, Actual: 10}, // 持平，命中
+		{Last: 10, Predict: 12, Actual: 8},  // 方向错误，误差 4
+	})
+	if m.Samples != 4 {
+		t.Fatalf("samples = %d", m.Samples)
+	}
+	if m.HitRate != 0.5 {
+		t.Errorf("hit rate = %v, want 0.5", m.HitRate)
+	}
+	if math.Abs(m.MAE-7.0/4) > 1e-9 {
+		t.Errorf("mae = %v, want 1.75", m.MAE)
+	}
+	wantMAPE := (1.0/12 + 2.0/11 + 0 + 4.0/8) / 4
+	if math.Abs(m.MAPE-wantMAPE) > 1e-9 {
+		t.Errorf("mape = %v, want %v", m.MAPE, wantMAPE)
+	}
+
+	if m := Evaluate(nil); m.Samples != 0 || m.HitRate != 0 {
+		t.Errorf("empty = %+v", m)
+	}
+}
+
+func TestVersion(t *testing.T) {
+	if v := Version("linear"); v != "window=30" {
+		t.Errorf("linear version = %q", v)
+	}
+	if v := Version("missing"); v != "" {
+		t.Errorf("missing version = %q", v)
+	}
+}
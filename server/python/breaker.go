@@ -0,0 +1,90 @@
+package python
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrBreakerOpen 熔断器打开，请求未发出
+var ErrBreakerOpen = errors.New("python: circuit breaker open")
+
+// 熔断器状态
+const (
+	BreakerClosed   = "closed"    // 正常放行
+	BreakerOpen     = "open"      // 拒绝请求，等待冷却
+	BreakerHalfOpen = "half-open" // 冷却结束，放行一个探测请求
+)
+
+// Breaker 熔断器，连续失败 failures 次后打开，拒绝请求 cooldown 时长，
+// 之后放行一个探测请求：成功则关闭，失败则重新打开
+type Breaker struct {
+	failures int
+	cooldown time.Duration
+	now      func() time.Time
+
+	mu       sync.Mutex
+	state    string
+	count    int       // 连续失败次数
+	openedAt time.Time // 最近一次打开的时间
+	probing  bool      // 半开状态下已有探测请求在途
+}
+
+func NewBreaker(failures int, cooldown time.Duration) *Breaker {
+	return &Breaker{
+		failures: failures,
+		cooldown: cooldown,
+		now:      time.Now,
+		state:    BreakerClosed,
+	}
+}
+
+// Allow 请求是否放行，放行后须调用 Done 报告结果
+func (b *Breaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case BreakerOpen:
+		if b.now().Sub(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.state = BreakerHalfOpen
+		b.probing = true
+		return true
+	case BreakerHalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+		return true
+	}
+	return true
+}
+
+// Done 报告放行请求的结果，failed 为 true 表示服务侧失败
+func (b *Breaker) Done(failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !failed {
+		b.state = BreakerClosed
+		b.count = 0
+		b.probing = false
+		return
+	}
+
+	b.count++
+	if b.state == BreakerHalfOpen || b.count >= b.failures {
+		b.state = BreakerOpen
+		b.openedAt = b.now()
+		b.probing = false
+	}
+}
+
+// State 当前状态，冷却结束但尚未放行探测请求时仍为 open
+func (b *Breaker) State() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
@@ -0,0 +1,49 @@
+package python
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBreaker(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	b := NewBreaker(3, 10*time.Second)
+	b.now = func() time.Time { return now }
+
+	// 成功会清零连续失败次数
+	for _, failed := range []bool{true, true, false, true, true} {
+		if !b.Allow() {
+			t.Fatal("closed breaker rejected request")
+		}
+		b.Done(failed)
+	}
+	if b.State() != BreakerClosed {
+		t.Fatalf("state = %s, want closed", b.State())
+	}
+
+	b.Allow()
+	b.Done(true)
+	if b.State() != BreakerOpen || b.Allow() {
+		t.Fatalf("state = %s, want open and rejecting", b.State())
+	}
+
+	// 冷却结束只放行一个探测请求，探测失败重新打开
+	now = now.Add(10 * time.Second)
+	if !b.Allow() || b.Allow() {
+		t.Fatal("half-open breaker should allow exactly one probe")
+	}
+	b.Done(true)
+	if b.State() != BreakerOpen || b.Allow() {
+		t.Fatalf("state = %s, want reopened", b.State())
+	}
+
+	// 探测成功后关闭
+	now = now.Add(10 * time.Second)
+	if !b.Allow() {
+		t.Fatal("probe rejected after cooldown")
+	}
+	b.Done(false)
+	if b.State() != BreakerClosed || !b.Allow() {
+		t.Fatalf("state = %s, want closed", b.State())
+	}
+}
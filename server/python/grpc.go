@@ -2,49 +2,166 @@ package python
 
 import (
 	"context"
+	"errors"
 	"financia/config"
+	"financia/server/feature"
+	"financia/server/predictor"
 	pb "financia/server/python/grpc"
+	"fmt"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	_ "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"math"
+	"sync/atomic"
 	"time"
 )
 
+const (
+	defaultTimeout         = 5 * time.Second
+	defaultBatchTimeout    = 8 * time.Second
+	defaultBreakerFailures = 5
+	defaultBreakerCooldown = 30 * time.Second
+
+	// readyRetryMax 就绪检查失败后重试的最长间隔
+	readyRetryMax = 30 * time.Second
+)
+
 var (
 	rpcCli    pb.PredictorClient
-	semaphore = make(chan struct{}, 2)
+	healthCli healthpb.HealthClient
+	breaker   = NewBreaker(defaultBreakerFailures, defaultBreakerCooldown)
+	// metadata 服务就绪后获取的模型信息，为 nil 时视为服务不可用
+	metadata atomic.Pointer[pb.MetadataResponse]
 )
 
 // NewGRPCClient
-// 初始化 gRPC 负载均衡连接
+// 初始化 gRPC 负载均衡连接，并在后台等待服务就绪
 func NewGRPCClient() {
-	// 负载均衡：round_robin
+	// 负载均衡：round_robin，通过标准健康检查协议剔除不健康的实例
 	servers := config.Configs.Python.Url
 	conn, err := grpc.NewClient(
 		servers,
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
-		grpc.WithDefaultServiceConfig(`{"loadBalancingPolicy": "round_robin"}`),
+		grpc.WithDefaultServiceConfig(`{"loadBalancingPolicy": "round_robin", "healthCheckConfig": {"serviceName": ""}}`),
 	)
 	if err != nil {
 		zap.S().Error("[NewGRPCClient] [err] = ", err.Error())
 		return
 	}
 
-	client := pb.NewPredictorClient(conn)
-	rpcCli = client
-	return
+	rpcCli = pb.NewPredictorClient(conn)
+	healthCli = healthpb.NewHealthClient(conn)
+	if n := config.Configs.Python.BreakerFailures; n > 0 {
+		breaker.failures = n
+	}
+	if d := config.Configs.Python.BreakerCooldown; d > 0 {
+		breaker.cooldown = d
+	}
+
+	go waitReady()
 }
 
-func SendPredictRequest(req *pb.PredictRequest) (float64, error) {
-	if len(req.Data) != 31 {
-		return 0, nil
+// waitReady 重试健康检查直到服务为 SERVING，然后获取模型信息
+func waitReady() {
+	wait := time.Second
+	for {
+		err := checkReady()
+		if err == nil {
+			m := metadata.Load()
+			zap.S().Infof("[NewGRPCClient] ready, model = %s, version = %s, window = %d, features = %s", m.Name, m.Version, m.Window, m.FeatureSchema)
+			return
+		}
+
+		zap.S().Warnf("[NewGRPCClient] [checkReady] [err] = %s, retry in %s", err.Error(), wait)
+		time.Sleep(wait)
+		wait = min(wait*2, readyRetryMax)
+	}
+}
+
+func checkReady() error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout())
+	defer cancel()
+
+	health, err := healthCli.Check(ctx, &healthpb.HealthCheckRequest{})
+	if err != nil {
+		return err
+	}
+	if health.Status != healthpb.HealthCheckResponse_SERVING {
+		return fmt.Errorf("health status %s", health.Status)
+	}
+
+	m, err := rpcCli.Metadata(ctx, &pb.MetadataRequest{})
+	if err != nil {
+		return err
+	}
+	if m.Window <= 0 {
+		return fmt.Errorf("invalid window %d", m.Window)
+	}
+	metadata.Store(m)
+	return nil
+}
+
+// Ready 服务是否已通过就绪检查
+func Ready() bool {
+	return metadata.Load() != nil
+}
+
+// BreakerState 熔断器当前状态
+func BreakerState() string {
+	return breaker.State()
+}
+
+func timeout() time.Duration {
+	if d := config.Configs.Python.Timeout; d > 0 {
+		return d
+	}
+	return defaultTimeout
+}
+
+func batchTimeout() time.Duration {
+	if d := config.Configs.Python.BatchTimeout; d > 0 {
+		return d
+	}
+	return defaultBatchTimeout
+}
+
+// invoke 经熔断器调用 rpc，只有服务侧的错误计入失败
+func invoke[T any](d time.Duration, call func(ctx context.Context) (T, error)) (T, error) {
+	var zero T
+	if rpcCli == nil {
+		return zero, errors.New("python: client not initialized")
+	}
+	if !breaker.Allow() {
+		return zero, ErrBreakerOpen
 	}
 
-	semaphore <- struct{}{}        // 获取信号量
-	defer func() { <-semaphore }() // 释放信号量
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), d)
 	defer cancel()
-	resp, err := rpcCli.Predict(ctx, req)
+	resp, err := call(ctx)
+	breaker.Done(serverFailure(err))
+	return resp, err
+}
+
+// serverFailure 服务不可用、超时或内部错误，参数错误等不计入熔断
+func serverFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Internal, codes.Unknown:
+		return true
+	}
+	return false
+}
+
+func SendPredictRequest(req *pb.PredictRequest) (float64, error) {
+	resp, err := invoke(timeout(), func(ctx context.Context) (*pb.PredictResponse, error) {
+		return rpcCli.Predict(ctx, req)
+	})
 	if err != nil {
 		zap.S().Error("[SendPredictRequest] [err] = ", err.Error())
 		return 0, err
@@ -53,14 +170,130 @@ func SendPredictRequest(req *pb.PredictRequest) (float64, error) {
 }
 
 func SendPredictAllRequest(req *pb.PredictAllRequest) ([]float64, error) {
-	semaphore <- struct{}{}        // 获取信号量
-	defer func() { <-semaphore }() // 释放信号量
-	ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
-	defer cancel()
-	resp, err := rpcCli.PredictAll(ctx, req)
+	resp, err := invoke(batchTimeout(), func(ctx context.Context) (*pb.PredictAllResponse, error) {
+		return rpcCli.PredictAll(ctx, req)
+	})
 	if err != nil {
-		zap.S().Error("[SendPredictRequest] [err] = ", err.Error())
+		zap.S().Error("[SendPredictAllRequest] [err] = ", err.Error())
 		return nil, err
 	}
 	return resp.Val, nil
 }
+
+func SendPredictHorizonsRequest(req *pb.PredictHorizonsRequest) ([]*pb.Forecast, error) {
+	resp, err := invoke(timeout(), func(ctx context.Context) (*pb.PredictHorizonsResponse, error) {
+		return rpcCli.PredictHorizons(ctx, req)
+	})
+	if err != nil {
+		zap.S().Error("[SendPredictHorizonsRequest] [err] = ", err.Error())
+		return nil, err
+	}
+	return resp.Forecasts, nil
+}
+
+// Predictor python gRPC 预测服务，作为 predictor.Predictor 的一种实现
+type Predictor struct{}
+
+func NewPredictor() *Predictor {
+	return &Predictor{}
+}
+
+func (*Predictor) Name() string {
+	return "python"
+}
+
+// Version 服务返回的模型名称与版本，未就绪时为空
+func (*Predictor) Version() string {
+	m := metadata.Load()
+	if m == nil {
+		return ""
+	}
+	return m.Name + ":" + m.Version
+}
+
+func (*Predictor) Predict(_ context.Context, points []*predictor.Point) (float64, error) {
+	points, err := window(points)
+	if err != nil {
+		return 0, err
+	}
+
+	val, err := SendPredictRequest(&pb.PredictRequest{
+		Data:   dataPoints(points),
+		Schema: feature.Configured().Schema(),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", predictor.ErrUnavailable, err)
+	}
+	return round3(val), nil
+}
+
+func (*Predictor) Forecast(_ context.Context, points []*predictor.Point, horizons []int) ([]*predictor.Forecast, error) {
+	points, err := window(points)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &pb.PredictHorizonsRequest{
+		Data:     dataPoints(points),
+		Horizons: make([]int32, 0, len(horizons)),
+		Schema:   feature.Configured().Schema(),
+	}
+	for _, h := range horizons {
+		req.Horizons = append(req.Horizons, int32(h))
+	}
+
+	forecasts, err := SendPredictHorizonsRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", predictor.ErrUnavailable, err)
+	}
+	if len(forecasts) != len(horizons) {
+		return nil, fmt.Errorf("%w: got %d forecasts for %d horizons", predictor.ErrUnavailable, len(forecasts), len(horizons))
+	}
+
+	list := make([]*predictor.Forecast, 0, len(forecasts))
+	for _, v := range forecasts {
+		list = append(list, &predictor.Forecast{
+			Horizon: int(v.Horizon),
+			Val:     round3(v.Val),
+			Lower:   round3(v.Lower),
+			Upper:   round3(v.Upper),
+		})
+	}
+	return list, nil
+}
+
+// window 按服务声明的窗口长度截取最近的数据点，并校验特征集与模型训练时一致
+func window(points []*predictor.Point) ([]*predictor.Point, error) {
+	m := metadata.Load()
+	if m == nil {
+		return nil, predictor.ErrUnavailable
+	}
+	if m.FeatureSchema != "" && m.FeatureSchema != feature.Configured().Schema() {
+		return nil, fmt.Errorf("%w: model expects features %s, configured %s", predictor.ErrUnavailable, m.FeatureSchema, feature.Configured().Schema())
+	}
+	n := int(m.Window)
+	if len(points) < n {
+		return nil, predictor.ErrNotEnoughData
+	}
+	return points[len(points)-n:], nil
+}
+
+func dataPoints(points []*predictor.Point) []*pb.DataPoint {
+	list := make([]*pb.DataPoint, 0, len(points))
+	for _, v := range points {
+		list = append(list, &pb.DataPoint{
+			Date:     v.Date.Format(time.DateOnly),
+			CoImf1:   v.Open,
+			CoImf2:   v.High,
+			CoImf3:   v.Low,
+			CoImf4:   v.Vol,
+			Target:   v.Close,
+			Features: v.Features,
+		})
+	}
+	return list
+}
+
+func round3(val float64) float64 {
+	return math.Floor(val*1000) / 1000
+}
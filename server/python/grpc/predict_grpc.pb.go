@@ -19,8 +19,10 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	Predictor_Predict_FullMethodName    = "/predict.Predictor/Predict"
-	Predictor_PredictAll_FullMethodName = "/predict.Predictor/PredictAll"
+	Predictor_Predict_FullMethodName         = "/predict.Predictor/Predict"
+	Predictor_PredictAll_FullMethodName      = "/predict.Predictor/PredictAll"
+	Predictor_PredictHorizons_FullMethodName = "/predict.Predictor/PredictHorizons"
+	Predictor_Metadata_FullMethodName        = "/predict.Predictor/Metadata"
 )
 
 // PredictorClient is the client API for Predictor service.
@@ -29,6 +31,9 @@ const (
 type PredictorClient interface {
 	Predict(ctx context.Context, in *PredictRequest, opts ...grpc.CallOption) (*PredictResponse, error)
 	PredictAll(ctx context.Context, in *PredictAllRequest, opts ...grpc.CallOption) (*PredictAllResponse, error)
+	PredictHorizons(ctx context.Context, in *PredictHorizonsRequest, opts ...grpc.CallOption) (*PredictHorizonsResponse, error)
+	// 模型名称、版本与输入窗口长度，客户端启动时获取，预测请求的数据点数量须等于 window
+	Metadata(ctx context.Context, in *MetadataRequest, opts ...grpc.CallOption) (*MetadataResponse, error)
 }
 
 type predictorClient struct {
@@ -59,12 +64,35 @@ func (c *predictorClient) PredictAll(ctx context.Context, in *PredictAllRequest,
 	return out, nil
 }
 
+func (c *predictorClient) PredictHorizons(ctx context.Context, in *PredictHorizonsRequest, opts ...grpc.CallOption) (*PredictHorizonsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PredictHorizonsResponse)
+	err := c.cc.Invoke(ctx, Predictor_PredictHorizons_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *predictorClient) Metadata(ctx context.Context, in *MetadataRequest, opts ...grpc.CallOption) (*MetadataResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MetadataResponse)
+	err := c.cc.Invoke(ctx, Predictor_Metadata_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // PredictorServer is the server API for Predictor service.
 // All implementations must embed UnimplementedPredictorServer
 // for forward compatibility.
 type PredictorServer interface {
 	Predict(context.Context, *PredictRequest) (*PredictResponse, error)
 	PredictAll(context.Context, *PredictAllRequest) (*PredictAllResponse, error)
+	PredictHorizons(context.Context, *PredictHorizonsRequest) (*PredictHorizonsResponse, error)
+	// 模型名称、版本与输入窗口长度，客户端启动时获取，预测请求的数据点数量须等于 window
+	Metadata(context.Context, *MetadataRequest) (*MetadataResponse, error)
 	mustEmbedUnimplementedPredictorServer()
 }
 
@@ -81,6 +109,12 @@ func (UnimplementedPredictorServer) Predict(context.Context, *PredictRequest) (*
 func (UnimplementedPredictorServer) PredictAll(context.Context, *PredictAllRequest) (*PredictAllResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method PredictAll not implemented")
 }
+func (UnimplementedPredictorServer) PredictHorizons(context.Context, *PredictHorizonsRequest) (*PredictHorizonsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PredictHorizons not implemented")
+}
+func (UnimplementedPredictorServer) Metadata(context.Context, *MetadataRequest) (*MetadataResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Metadata not implemented")
+}
 func (UnimplementedPredictorServer) mustEmbedUnimplementedPredictorServer() {}
 func (UnimplementedPredictorServer) testEmbeddedByValue()                   {}
 
@@ -138,6 +172,42 @@ func _Predictor_PredictAll_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Predictor_PredictHorizons_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PredictHorizonsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PredictorServer).PredictHorizons(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Predictor_PredictHorizons_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PredictorServer).PredictHorizons(ctx, req.(*PredictHorizonsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Predictor_Metadata_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MetadataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PredictorServer).Metadata(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Predictor_Metadata_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PredictorServer).Metadata(ctx, req.(*MetadataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Predictor_ServiceDesc is the grpc.ServiceDesc for Predictor service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -153,6 +223,14 @@ var Predictor_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "PredictAll",
 			Handler:    _Predictor_PredictAll_Handler,
 		},
+		{
+			MethodName: "PredictHorizons",
+			Handler:    _Predictor_PredictHorizons_Handler,
+		},
+		{
+			MethodName: "Metadata",
+			Handler:    _Predictor_Metadata_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "predict.proto",
@@ -0,0 +1,88 @@
+package python
+
+import (
+	"context"
+	"errors"
+	"financia/server/predictor"
+	pb "financia/server/python/grpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+	"time"
+)
+
+// fakeClient 记录请求的数据点数量，err 不为空时返回错误
+type fakeClient struct {
+	pb.PredictorClient
+	got int
+	err error
+}
+
+func (f *fakeClient) Predict(_ context.Context, in *pb.PredictRequest, _ ...grpc.CallOption) (*pb.PredictResponse, error) {
+	f.got = len(in.Data)
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &pb.PredictResponse{Val: 1.23456}, nil
+}
+
+func points(n int) []*predictor.Point {
+	list := make([]*predictor.Point, n)
+	for i := range list {
+		list[i] = &predictor.Point{Date: time.Date(2024, 1, 1+i, 0, 0, 0, 0, time.UTC), Close: float64(i)}
+	}
+	return list
+}
+
+func TestPredictorWindow(t *testing.T) {
+	fake := &fakeClient{}
+	rpcCli, breaker = fake, NewBreaker(2, time.Minute)
+	defer func() { rpcCli, breaker = nil, NewBreaker(defaultBreakerFailures, defaultBreakerCooldown) }()
+	defer metadata.Store(nil)
+
+	p := NewPredictor()
+	if _, err := p.Predict(context.Background(), points(40)); !errors.Is(err, predictor.ErrUnavailable) {
+		t.Fatalf("err before ready = %v, want ErrUnavailable", err)
+	}
+
+	metadata.Store(&pb.MetadataResponse{Name: "lstm", Version: "2", Window: 31})
+	if v := p.Version(); v != "lstm:2" {
+		t.Errorf("version = %q", v)
+	}
+	if _, err := p.Predict(context.Background(), points(30)); !errors.Is(err, predictor.ErrNotEnoughData) {
+		t.Errorf("err = %v, want ErrNotEnoughData", err)
+	}
+	// 多余的数据点只发送最近的 window 个
+	val, err := p.Predict(context.Background(), points(40))
+	if err != nil || val != 1.234 || fake.got != 31 {
+		t.Errorf("val = %v, err = %v, sent %d points", val, err, fake.got)
+	}
+}
+
+func TestPredictorBreaker(t *testing.T) {
+	fake := &fakeClient{err: status.Error(codes.InvalidArgument, "bad request")}
+	rpcCli, breaker = fake, NewBreaker(2, time.Minute)
+	defer func() { rpcCli, breaker = nil, NewBreaker(defaultBreakerFailures, defaultBreakerCooldown) }()
+	metadata.Store(&pb.MetadataResponse{Window: 3})
+	defer metadata.Store(nil)
+
+	p := NewPredictor()
+	// 参数错误不计入熔断
+	for i := 0; i < 3; i++ {
+		_, _ = p.Predict(context.Background(), points(3))
+	}
+	if BreakerState() != BreakerClosed {
+		t.Fatalf("state = %s after client errors, want closed", BreakerState())
+	}
+
+	fake.err = status.Error(codes.Unavailable, "connection refused")
+	for i := 0; i < 2; i++ {
+		_, _ = p.Predict(context.Background(), points(3))
+	}
+	fake.got = 0
+	_, err := p.Predict(context.Background(), points(3))
+	if !errors.Is(err, predictor.ErrUnavailable) || fake.got != 0 {
+		t.Errorf("err = %v, sent %d points, want rejected by open breaker", err, fake.got)
+	}
+}
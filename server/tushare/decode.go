@@ -0,0 +1,205 @@
+package tushare
+
+import (
+	"context"
+	"errors"
+	"financia/util"
+	"fmt"
+	"github.com/spf13/cast"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// tagName 结构体字段映射 tushare 列名的标签
+// 例如 `tushare:"trade_date"`，可选项 date 将 YYYYMMDD 转为 YYYY-MM-DD（仅 string 字段）
+const tagName = "tushare"
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// binding 结构体字段与列的对应关系
+type binding struct {
+	index  []int
+	column string
+	date   bool
+}
+
+// bindingsOf 解析结构体（含匿名嵌入结构体）上的 tushare 标签
+func bindingsOf(t reflect.Type) []binding {
+	var list []binding
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, ok := f.Tag.Lookup(tagName)
+		if !ok {
+			if f.Anonymous && f.Type.Kind() == reflect.Struct && f.Type != timeType {
+				for _, b := range bindingsOf(f.Type) {
+					b.index = append([]int{i}, b.index...)
+					list = append(list, b)
+				}
+			}
+			continue
+		}
+		if tag == "-" {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(tag, ",")
+		list = append(list, binding{
+			index:  []int{i},
+			column: name,
+			date:   opts == "date",
+		})
+	}
+	return list
+}
+
+// Fields 返回结构体标签声明的列名，用于请求的 fields 参数
+func Fields(v interface{}) string {
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+
+	bindings := bindingsOf(t)
+	names := make([]string, 0, len(bindings))
+	for _, b := range bindings {
+		names = append(names, b.column)
+	}
+	return strings.Join(names, ",")
+}
+
+// Decode 按响应中的 fields 表头将 items 填充到 out
+// out 必须是结构体切片（或结构体指针切片）的指针，缺失的列保持零值
+func Decode(resp *DailyResp, out interface{}) error {
+	rv := reflect.ValueOf(out)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("tushare: decode target must be a pointer to slice, got %T", out)
+	}
+
+	slice := rv.Elem()
+	elemType := slice.Type().Elem()
+	structType := elemType
+	if elemType.Kind() == reflect.Ptr {
+		structType = elemType.Elem()
+	}
+	if structType.Kind() != reflect.Struct {
+		return fmt.Errorf("tushare: decode target element must be a struct, got %s", structType)
+	}
+
+	columns := make(map[string]int, len(resp.Fields))
+	for i, f := range resp.Fields {
+		columns[f] = i
+	}
+
+	result := reflect.MakeSlice(slice.Type(), 0, len(resp.Items))
+	bindings := bindingsOf(structType)
+	for _, item := range resp.Items {
+		v := reflect.New(structType)
+		for _, b := range bindings {
+			col, ok := columns[b.column]
+			if !ok || col >= len(item) {
+				continue
+			}
+			if err := setField(v.Elem().FieldByIndex(b.index), item[col], b.date); err != nil {
+				return fmt.Errorf("tushare: decode column %s: %w", b.column, err)
+			}
+		}
+
+		if elemType.Kind() == reflect.Ptr {
+			result = reflect.Append(result, v)
+		} else {
+			result = reflect.Append(result, v.Elem())
+		}
+	}
+
+	slice.Set(result)
+	return nil
+}
+
+func setField(field reflect.Value, raw interface{}, date bool) error {
+	if raw == nil {
+		return nil
+	}
+	if s, ok := raw.(string); ok && s == "" {
+		return nil
+	}
+
+	if field.Type() == timeType {
+		str := cast.ToString(raw)
+		t := util.ConvertDateStrToTime(str, util.TimeDateOnlyWithOutSep)
+		if t.IsZero() {
+			return fmt.Errorf("invalid date %q", str)
+		}
+		field.Set(reflect.ValueOf(t))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		str := cast.ToString(raw)
+		if date {
+			if t := util.ConvertDateStrToTime(str, util.TimeDateOnlyWithOutSep); !t.IsZero() {
+				str = t.Format(time.DateOnly)
+			}
+		}
+		field.SetString(str)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := cast.ToInt64E(raw)
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := cast.ToFloat64E(raw)
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+	case reflect.Bool:
+		b, err := cast.ToBoolE(raw)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	default:
+		return fmt.Errorf("unsupported field type %s", field.Type())
+	}
+	return nil
+}
+
+// query 调用接口并按 T 的标签解码，fields 取自 T 的标签
+func query[T any](ctx context.Context, apiName string, params interface{}) ([]*T, error) {
+	resp, err := defaultClient.Post(ctx, apiName, params, Fields(new(T)))
+	if err != nil {
+		return nil, err
+	}
+
+	var list []*T
+	if err := Decode(resp, &list); err != nil {
+		return nil, fmt.Errorf("tushare: %s: %w", apiName, err)
+	}
+	return list, nil
+}
+
+// queryPages 按 offset/limit 分页查询，直到返回不足一页，limit 不能超过接口的单次上限
+// 任一页失败时返回错误，不返回部分数据
+func queryPages[T any](ctx context.Context, apiName string, req *DailyReq, limit int) ([]*T, error) {
+	list := make([]*T, 0)
+	for offset := 0; ; offset += limit {
+		page := *req
+		page.Offset, page.Limit = offset, limit
+
+		rows, err := query[T](ctx, apiName, &page)
+		if errors.Is(err, ErrEmptyResult) && offset > 0 {
+			// 总数恰好是整页
+			return list, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, rows...)
+		if len(rows) < limit {
+			return list, nil
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package tushare
+
+import (
+	"financia/public/db/model"
+	"testing"
+	"time"
+)
+
+func Test_Decode(t *testing.T) {
+	// 列顺序与结构体声明不同，且包含多余的列
+	resp := &DailyResp{
+		Fields: []string{"trade_date", "extra", "close", "ts_code", "vol"},
+		Items: [][]interface{}{
+			{"20240105", "x", 10.5, "000001.SZ", 123456.0},
+			{"20240104", nil, 10.1, "000001.SZ", nil},
+		},
+	}
+
+	var list []*model.StockData
+	if err := Decode(resp, &list); err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 {
+		t.Fatalf("len = %d", len(list))
+	}
+	if list[0].TsCode != "000001.SZ" || list[0].Close != 10.5 || list[0].Vol != 123456 ||
+		!list[0].TradeDate.Equal(time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("row 0 = %+v", list[0])
+	}
+	if list[1].Vol != 0 {
+		t.Errorf("row 1 vol = %d", list[1].Vol)
+	}
+}
+
+func Test_DecodeEmbedded(t *testing.T) {
+	resp := &DailyResp{
+		Fields: []string{"update_flag", "ann_date", "type"},
+		Items:  [][]interface{}{{"0", "20240131", "预增"}},
+	}
+
+	var list []forecastRow
+	if err := Decode(resp, &list); err != nil {
+		t.Fatal(err)
+	}
+	if list[0].AnnDate != "2024-01-31" || list[0].Type != "预增" || list[0].UpdateFlag != "0" {
+		t.Errorf("row = %+v", list[0])
+	}
+}
+
+func Test_Fields(t *testing.T) {
+	if got := Fields(new(hsgtTop10Row)); got != "name,close,change,rank,amount,market_type" {
+		t.Errorf("fields = %s", got)
+	}
+}
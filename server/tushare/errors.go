@@ -0,0 +1,55 @@
+package tushare
+
+import (
+	"errors"
+	"financia/util"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrQuotaExceeded 积分不足或访问频次超限
+	ErrQuotaExceeded = errors.New("tushare: quota exceeded")
+	// ErrInvalidToken token 无效
+	ErrInvalidToken = errors.New("tushare: invalid token")
+	// ErrEmptyResult 接口正常返回但没有数据
+	ErrEmptyResult = errors.New("tushare: empty result")
+)
+
+const (
+	codeInvalidToken = 40101
+	codeNoPermission = 40203
+)
+
+// APIError tushare 返回的业务错误
+type APIError struct {
+	ApiName string
+	Code    int
+	Msg     string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("tushare: %s code = %d, msg = %s", e.ApiName, e.Code, e.Msg)
+}
+
+// Unwrap 将错误码映射到可判断的哨兵错误
+func (e *APIError) Unwrap() error {
+	switch {
+	case e.Code == codeInvalidToken || strings.Contains(e.Msg, "token不对"):
+		return ErrInvalidToken
+	case e.Code == codeNoPermission || strings.Contains(e.Msg, "最多访问"):
+		return ErrQuotaExceeded
+	}
+	return nil
+}
+
+// RespCode 将 tushare 错误转换为接口返回码
+func RespCode(err error) int {
+	switch {
+	case errors.Is(err, ErrQuotaExceeded):
+		return util.TuShareLimitError
+	case errors.Is(err, ErrEmptyResult):
+		return util.DataEmptyError
+	}
+	return util.InternalServerError
+}
@@ -1,18 +1,26 @@
 package tushare
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"financia/config"
+	"fmt"
 	"github.com/go-resty/resty/v2"
 	"go.uber.org/zap"
+	"net/http"
+	"time"
 )
 
 const (
-	url = "http://api.tushare.pro"
+	defaultUrl        = "http://api.tushare.pro"
+	defaultRate       = 200 // 每分钟请求数，对应 2000 积分档
+	defaultBurst      = 10
+	defaultMaxRetries = 3
+	defaultTimeout    = 30 * time.Second
+	defaultBackoff    = 500 * time.Millisecond
 )
 
-var token = config.Configs.TuShare.Token
-
 type TuShareReq struct {
 	ApiName string      `json:"api_name"`
 	Token   string      `json:"token"`
@@ -21,46 +29,154 @@ type TuShareReq struct {
 }
 
 type TuShareResp struct {
-	Code int         `json:"code"`
-	Msg  string      `json:"msg"`
-	Data interface{} `json:"data"`
+	Code int             `json:"code"`
+	Msg  string          `json:"msg"`
+	Data json.RawMessage `json:"data"`
 }
 
-func tuSharePost(apiName string, data interface{}, fields string) any {
-	client := resty.New()
-	tuShareResp := &TuShareResp{}
+// Client tushare 接口客户端
+// 所有请求共享同一个 http 连接池与令牌桶限流
+type Client struct {
+	baseUrl    string
+	token      string
+	http       *resty.Client
+	limiter    *limiter
+	maxRetries int
+	backoff    time.Duration
+	recordDir  string // 非空时将请求与响应录制到该目录，供 ReplayServer 回放
+}
+
+// NewClient 根据配置创建客户端，未配置的项使用默认值
+func NewClient(conf config.TuShareConfig) *Client {
+	baseUrl := conf.Url
+	if baseUrl == "" {
+		baseUrl = defaultUrl
+	}
+	rate := conf.Rate
+	if rate <= 0 {
+		rate = defaultRate
+	}
+	burst := conf.Burst
+	if burst <= 0 {
+		burst = defaultBurst
+	}
+	maxRetries := conf.MaxRetries
+	if maxRetries <= 0 {
+		maxRetries = defaultMaxRetries
+	}
+	timeout := time.Duration(conf.Timeout) * time.Second
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
 
-	zap.S().Debugf("[tuSharePost] [apiName] = %s", apiName)
-	zap.S().Debugf("[tuSharePost] [data] = %#v", data)
+	return &Client{
+		baseUrl:    baseUrl,
+		token:      conf.Token,
+		http:       resty.New().SetTimeout(timeout),
+		limiter:    newLimiter(float64(rate)/60, burst),
+		maxRetries: maxRetries,
+		backoff:    defaultBackoff,
+		recordDir:  conf.RecordDir,
+	}
+}
+
+var defaultClient = NewClient(config.Configs.TuShare)
+
+// SetDefault 替换包级默认客户端
+func SetDefault(c *Client) {
+	defaultClient = c
+}
+
+// Post 调用 tushare 接口
+// 网络错误与 5xx 按指数退避重试，业务错误转换为 *APIError 返回
+func (c *Client) Post(ctx context.Context, apiName string, params interface{}, fields string) (*DailyResp, error) {
+	zap.S().Debugf("[Post] [apiName] = %s", apiName)
+	zap.S().Debugf("[Post] [params] = %#v", params)
+
+	var lastErr error
+	for attempt := 0; attempt <= c.maxRetries; attempt++ {
+		if attempt > 0 {
+			wait := c.backoff << (attempt - 1)
+			zap.S().Warnf("[Post] [%s] retry %d after %s, [err] = %s", apiName, attempt, wait, lastErr.Error())
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(wait):
+			}
+		}
+
+		if err := c.limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+
+		resp, err := c.do(ctx, apiName, params, fields)
+		if err == nil {
+			return resp, nil
+		}
+		if !isTransient(err) {
+			return nil, err
+		}
+		lastErr = err
+	}
 
-	_, err := client.R().
+	return nil, fmt.Errorf("tushare: %s failed after %d retries: %w", apiName, c.maxRetries, lastErr)
+}
+
+func (c *Client) do(ctx context.Context, apiName string, params interface{}, fields string) (*DailyResp, error) {
+	resp, err := c.http.R().
+		SetContext(ctx).
 		SetHeader("Content-Type", "application/json").
 		SetBody(&TuShareReq{
 			ApiName: apiName,
-			Token:   token,
-			Params:  data,
+			Token:   c.token,
+			Params:  params,
 			Fields:  fields,
 		}).
-		SetResult(&tuShareResp).
-		Post(url)
+		Post(c.baseUrl)
 	if err != nil {
-		zap.S().Errorf("[tuSharePost] [err] = %s", err.Error())
-		return nil
+		return nil, &transientError{err: err}
+	}
+	if resp.StatusCode() >= http.StatusInternalServerError || resp.StatusCode() == http.StatusTooManyRequests {
+		return nil, &transientError{err: fmt.Errorf("http status %d", resp.StatusCode())}
+	}
+	if resp.StatusCode() != http.StatusOK {
+		return nil, fmt.Errorf("tushare: %s http status %d", apiName, resp.StatusCode())
 	}
 
-	return tuShareResp.Data
-}
-
-func marshalResp(r any, resp *DailyResp) error {
-	marshal, err := json.Marshal(r.(map[string]interface{}))
-	if err != nil {
-		zap.S().Errorf("[FutWeeklyDetail] [json.Marshal] [err] = %s", err.Error())
-		return err
+	tuShareResp := &TuShareResp{}
+	if err := json.Unmarshal(resp.Body(), tuShareResp); err != nil {
+		return nil, fmt.Errorf("tushare: %s decode resp: %w", apiName, err)
+	}
+	if c.recordDir != "" {
+		c.record(apiName, params, fields, resp.Body())
+	}
+	if tuShareResp.Code != 0 {
+		return nil, &APIError{ApiName: apiName, Code: tuShareResp.Code, Msg: tuShareResp.Msg}
 	}
 
-	if err := json.Unmarshal(marshal, &resp); err != nil {
-		zap.S().Errorf("[FutWeeklyDetail] [json.Unmarshal] [err] = %s", err.Error())
-		return err
+	var data DailyResp
+	if len(tuShareResp.Data) > 0 && string(tuShareResp.Data) != "null" {
+		if err := json.Unmarshal(tuShareResp.Data, &data); err != nil {
+			return nil, fmt.Errorf("tushare: %s decode data: %w", apiName, err)
+		}
+	}
+	if len(data.Items) == 0 {
+		return nil, fmt.Errorf("tushare: %s: %w", apiName, ErrEmptyResult)
 	}
-	return nil
+
+	return &data, nil
+}
+
+// transientError 可重试的错误
+type transientError struct {
+	err error
+}
+
+func (e *transientError) Error() string { return e.err.Error() }
+
+func (e *transientError) Unwrap() error { return e.err }
+
+func isTransient(err error) bool {
+	var t *transientError
+	return errors.As(err, &t)
 }
@@ -1 +1,105 @@
 package tushare
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"financia/config"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func newTestClient(url string) *Client {
+	c := NewClient(config.TuShareConfig{Url: url, Token: "test", Rate: 6000, Burst: 100})
+	c.backoff = 0
+	return c
+}
+
+func Test_ClientRetry(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`{"code":0,"msg":"","data":{"fields":["ts_code"],"items":[["000001.SZ"]]}}`))
+	}))
+	defer srv.Close()
+
+	resp, err := newTestClient(srv.URL).Post(context.Background(), "daily", nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 3 || len(resp.Items) != 1 {
+		t.Fatalf("calls = %d, items = %d", calls, len(resp.Items))
+	}
+}
+
+func Test_ClientErrors(t *testing.T) {
+	cases := []struct {
+		body string
+		want error
+	}{
+		{`{"code":40203,"msg":"抱歉，您每分钟最多访问该接口500次"}`, ErrQuotaExceeded},
+		{`{"code":40101,"msg":"您的token不对，请确认。"}`, ErrInvalidToken},
+		{`{"code":0,"msg":"","data":{"fields":["ts_code"],"items":[]}}`, ErrEmptyResult},
+	}
+
+	for _, tc := range cases {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(tc.body))
+		}))
+		_, err := newTestClient(srv.URL).Post(context.Background(), "daily", nil, "")
+		srv.Close()
+		if !errors.Is(err, tc.want) {
+			t.Errorf("body = %s, err = %v, want %v", tc.body, err, tc.want)
+		}
+	}
+}
+
+type pageRow struct {
+	TsCode string `tushare:"ts_code"`
+}
+
+func Test_QueryPages(t *testing.T) {
+	// 共 5 行，每页 2 行；fail 为出错的偏移
+	pages := func(fail int) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var body struct {
+				Params DailyReq `json:"params"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			if body.Params.Offset == fail {
+				w.Write([]byte(`{"code":40203,"msg":"抱歉，您每分钟最多访问该接口500次"}`))
+				return
+			}
+			items := make([]string, 0)
+			for i := body.Params.Offset; i < min(body.Params.Offset+body.Params.Limit, 5); i++ {
+				items = append(items, fmt.Sprintf(`["%06d.SZ"]`, i))
+			}
+			fmt.Fprintf(w, `{"code":0,"msg":"","data":{"fields":["ts_code"],"items":[%s]}}`, strings.Join(items, ","))
+		}))
+	}
+	defer SetDefault(defaultClient)
+
+	srv := pages(-1)
+	SetDefault(newTestClient(srv.URL))
+	list, err := queryPages[pageRow](context.Background(), "daily", &DailyReq{TradeDate: "20240301"}, 2)
+	srv.Close()
+	if err != nil || len(list) != 5 || list[4].TsCode != "000004.SZ" {
+		t.Fatalf("len = %d, err = %v", len(list), err)
+	}
+
+	// 中间一页失败时不返回部分数据
+	srv = pages(2)
+	SetDefault(newTestClient(srv.URL))
+	list, err = queryPages[pageRow](context.Background(), "daily", &DailyReq{TradeDate: "20240301"}, 2)
+	srv.Close()
+	if !errors.Is(err, ErrQuotaExceeded) || list != nil {
+		t.Fatalf("list = %v, err = %v", list, err)
+	}
+}
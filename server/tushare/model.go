@@ -1,5 +1,7 @@
 package tushare
 
+import "time"
+
 type DailyReq struct {
 	TsCode     string `json:"ts_code,omitempty"`
 	TradeDate  string `json:"trade_date,omitempty"`
@@ -10,6 +12,9 @@ type DailyReq struct {
 	ReportType int    `json:"report_type,omitempty"` // 1
 	Q          string `json:"q,omitempty"`
 	StartM     string `json:"start_m,omitempty"`
+	IndexCode  string `json:"index_code,omitempty"`
+	Offset     int    `json:"offset,omitempty"` // 分页偏移
+	Limit      int    `json:"limit,omitempty"`  // 单页行数
 }
 
 type DailyResp struct {
@@ -18,117 +23,132 @@ type DailyResp struct {
 }
 
 type FundSalesRatioResp struct {
-	Year      string  `json:"year"`
-	Bank      float64 `json:"bank"`
-	SecComp   float64 `json:"secComp"`
-	FundComp  float64 `json:"fundComp"`
-	IndepComp float64 `json:"indepComp"`
-	Rests     float64 `json:"rests"`
+	Year      string  `json:"year" tushare:"year"`
+	Bank      float64 `json:"bank" tushare:"bank"`
+	SecComp   float64 `json:"secComp" tushare:"sec_comp"`
+	FundComp  float64 `json:"fundComp" tushare:"fund_comp"`
+	IndepComp float64 `json:"indepComp" tushare:"indep_comp"`
+	Rests     float64 `json:"rests" tushare:"rests"`
 }
 
 type FundSalesVolResp struct {
-	Year      string  `json:"year"`
-	Quarter   string  `json:"quarter"`
-	InstName  string  `json:"instName"`
-	FundScale float64 `json:"fundScale"`
-	Scale     float64 `json:"scale"`
-	Rank      int     `json:"rank"`
+	Year      string  `json:"year" tushare:"year"`
+	Quarter   string  `json:"quarter" tushare:"quarter"`
+	InstName  string  `json:"instName" tushare:"inst_name"`
+	FundScale float64 `json:"fundScale" tushare:"fund_scale"`
+	Scale     float64 `json:"scale" tushare:"scale"`
+	Rank      int     `json:"rank" tushare:"rank"`
 }
 
 type FutTradeCalResp struct {
-	CalDate string `json:"calDate"`
-	IsOpen  int    `json:"isOpen"` // 0: 休市 1: 开市
+	CalDate string `json:"calDate" tushare:"cal_date"`
+	IsOpen  int    `json:"isOpen" tushare:"is_open"` // 0: 休市 1: 开市
 }
 
 type FutWeeklyDetailResp struct {
-	Vol          int     `json:"vol"`
-	VolYoy       float64 `json:"volYoy"`
-	Amount       float64 `json:"amount"`
-	AmountYoy    float64 `json:"amountYoy"`
-	CumVol       int     `json:"cumVol"`
-	CumVolYoy    float64 `json:"cumVolYoy"`
-	Cumamt       float64 `json:"cumamt"`
-	CumamtYoy    float64 `json:"cumamtYoy"`
-	OpenInterest int     `json:"openInterest"`
-	InterestWow  float64 `json:"interestWow"`
-	McClose      float64 `json:"mcClose"`
-	CloseWow     float64 `json:"closeWow"`
-	WeekDate     string  `json:"weekDate"`
+	Vol          int     `json:"vol" tushare:"vol"`
+	VolYoy       float64 `json:"volYoy" tushare:"vol_yoy"`
+	Amount       float64 `json:"amount" tushare:"amount"`
+	AmountYoy    float64 `json:"amountYoy" tushare:"amout_yoy"`
+	CumVol       int     `json:"cumVol" tushare:"cumvol"`
+	CumVolYoy    float64 `json:"cumVolYoy" tushare:"cumvol_yoy"`
+	Cumamt       float64 `json:"cumamt" tushare:"cumamt"`
+	CumamtYoy    float64 `json:"cumamtYoy" tushare:"cumamt_yoy"`
+	OpenInterest int     `json:"openInterest" tushare:"open_interest"`
+	InterestWow  float64 `json:"interestWow" tushare:"interest_wow"`
+	McClose      float64 `json:"mcClose" tushare:"mc_close"`
+	CloseWow     float64 `json:"closeWow" tushare:"close_wow"`
+	WeekDate     string  `json:"weekDate" tushare:"week_date"`
 }
 
 type StockIncomeResp struct {
-	AnnDate      string  `json:"annDate"`      // 公告日期
-	BasicEps     float64 `json:"basicEps"`     // 基本每股收益
-	TotalRevenue float64 `json:"totalRevenue"` // 营业总收入
-	TotalCogs    float64 `json:"totalCogs"`    // 营业总成本
-	OperExp      float64 `json:"operExp"`      // 营业支出
-	TotalProfit  float64 `json:"totalProfit"`  // 利润总额
-	IncomeTax    float64 `json:"incomeTax"`    // 所得税费用
-	NIncome      float64 `json:"nIncome"`      // 净利润
-	TComprIncome float64 `json:"tComprIncome"` // 综合收益总额
+	AnnDate      string  `json:"annDate" tushare:"ann_date,date"`       // 公告日期
+	BasicEps     float64 `json:"basicEps" tushare:"basic_eps"`          // 基本每股收益
+	TotalRevenue float64 `json:"totalRevenue" tushare:"total_revenue"`  // 营业总收入
+	TotalCogs    float64 `json:"totalCogs" tushare:"total_cogs"`        // 营业总成本
+	OperExp      float64 `json:"operExp" tushare:"oper_exp"`            // 营业支出
+	TotalProfit  float64 `json:"totalProfit" tushare:"total_profit"`    // 利润总额
+	IncomeTax    float64 `json:"incomeTax" tushare:"income_tax"`        // 所得税费用
+	NIncome      float64 `json:"nIncome" tushare:"n_income"`            // 净利润
+	TComprIncome float64 `json:"tComprIncome" tushare:"t_compr_income"` // 综合收益总额
 }
 
 type StockForecastResp struct {
-	AnnDate       string  `json:"annDate"`       // 公告日期
-	Type          string  `json:"type"`          // 预告类型
-	PChangeMin    float64 `json:"pChangeMin"`    // 预告净利润变动幅度下限
-	PChangeMax    float64 `json:"pChangeMax"`    // 预告净利润变动幅度上限
-	NetProfitMin  float64 `json:"netProfitMin"`  // 预告净利润下限
-	NetProfitMax  float64 `json:"netProfitMax"`  // 预告净利润上限
-	LastParentNet float64 `json:"lastParentNet"` // 上年同期归属母公司净利润
-	ChangeReason  string  `json:"changeReason"`  // 预告净利润变动原因
+	AnnDate       string  `json:"annDate" tushare:"ann_date,date"`         // 公告日期
+	Type          string  `json:"type" tushare:"type"`                     // 预告类型
+	PChangeMin    float64 `json:"pChangeMin" tushare:"p_change_min"`       // 预告净利润变动幅度下限
+	PChangeMax    float64 `json:"pChangeMax" tushare:"p_change_max"`       // 预告净利润变动幅度上限
+	NetProfitMin  float64 `json:"netProfitMin" tushare:"net_profit_min"`   // 预告净利润下限
+	NetProfitMax  float64 `json:"netProfitMax" tushare:"net_profit_max"`   // 预告净利润上限
+	LastParentNet float64 `json:"lastParentNet" tushare:"last_parent_net"` // 上年同期归属母公司净利润
+	ChangeReason  string  `json:"changeReason" tushare:"change_reason"`    // 预告净利润变动原因
 }
 
 type StockTop10Resp struct {
-	AnnDate        string  `json:"annDate"`        // 公告日期
-	HolderName     string  `json:"holderName"`     // 股东名称
-	HoldAmount     float64 `json:"holdAmount"`     // 持股数量
-	HoldRatio      float64 `json:"holdRatio"`      // 持股比例
-	HoldFloatRatio float64 `json:"holdFloatRatio"` // 流通股比例
-	HoldChange     float64 `json:"holdChange"`     // 变动
-	HolderType     string  `json:"holderType"`     // 股东类型
+	AnnDate        string  `json:"annDate" tushare:"ann_date,date"`           // 公告日期
+	HolderName     string  `json:"holderName" tushare:"holder_name"`          // 股东名称
+	HoldAmount     float64 `json:"holdAmount" tushare:"hold_amount"`          // 持股数量
+	HoldRatio      float64 `json:"holdRatio" tushare:"hold_ratio"`            // 持股比例
+	HoldFloatRatio float64 `json:"holdFloatRatio" tushare:"hold_float_ratio"` // 流通股比例
+	HoldChange     float64 `json:"holdChange" tushare:"hold_change"`          // 变动
+	HolderType     string  `json:"holderType" tushare:"holder_type"`          // 股东类型
 }
 
 type StockHsgtTop10Resp struct {
-	Name   string  `json:"name"`   // 股票名称
-	Close  float64 `json:"close"`  // 收盘价
-	Change float64 `json:"change"` // 涨跌幅
-	Rank   int     `json:"rank"`   // 排名
-	Amount float64 `json:"amount"` // 持股数量
+	Name   string  `json:"name" tushare:"name"`     // 股票名称
+	Close  float64 `json:"close" tushare:"close"`   // 收盘价
+	Change float64 `json:"change" tushare:"change"` // 涨跌幅
+	Rank   int     `json:"rank" tushare:"rank"`     // 排名
+	Amount float64 `json:"amount" tushare:"amount"` // 持股数量
 }
 
 type EconomicsShiborResp struct {
-	Date   string  `json:"date"`
-	On     float64 `json:"on"`
-	OneW   float64 `json:"oneW"`
-	TwoW   float64 `json:"twoW"`
-	OneM   float64 `json:"oneM"`
-	ThreeM float64 `json:"threeM"`
-	SixM   float64 `json:"sixM"`
-	NineM  float64 `json:"nineM"`
-	OneY   float64 `json:"oneY"`
+	Date   string  `json:"date" tushare:"date,date"`
+	On     float64 `json:"on" tushare:"on"`
+	OneW   float64 `json:"oneW" tushare:"1w"`
+	TwoW   float64 `json:"twoW" tushare:"2w"`
+	OneM   float64 `json:"oneM" tushare:"1m"`
+	ThreeM float64 `json:"threeM" tushare:"3m"`
+	SixM   float64 `json:"sixM" tushare:"6m"`
+	NineM  float64 `json:"nineM" tushare:"9m"`
+	OneY   float64 `json:"oneY" tushare:"1y"`
 }
 
 type EconomicsCnGDPResp struct {
-	GDP    float64 `json:"gdp"`
-	GDPYoy float64 `json:"gdpYoy"`
-	PI     float64 `json:"pi"`
-	PIYoy  float64 `json:"piYoy"`
-	SI     float64 `json:"si"`
-	SIYoy  float64 `json:"siYoy"`
-	TI     float64 `json:"ti"`
-	TIYoy  float64 `json:"tiYoy"`
+	GDP    float64 `json:"gdp" tushare:"gdp"`
+	GDPYoy float64 `json:"gdpYoy" tushare:"gdp_yoy"`
+	PI     float64 `json:"pi" tushare:"pi"`
+	PIYoy  float64 `json:"piYoy" tushare:"pi_yoy"`
+	SI     float64 `json:"si" tushare:"si"`
+	SIYoy  float64 `json:"siYoy" tushare:"si_yoy"`
+	TI     float64 `json:"ti" tushare:"ti"`
+	TIYoy  float64 `json:"tiYoy" tushare:"ti_yoy"`
 }
 
 type EconomicsCnCPIResp struct {
-	Month    string  `json:"month"`
-	NtYoy    float64 `json:"ntYoy"`
-	NtMom    float64 `json:"ntMom"`
-	NtAccu   float64 `json:"ntAccu"`
-	TownYoy  float64 `json:"townYoy"`
-	TownMom  float64 `json:"townMom"`
-	TownAccu float64 `json:"townAccu"`
-	CntYoy   float64 `json:"cntYoy"`
-	CntMom   float64 `json:"cntMom"`
-	CntAccu  float64 `json:"cntAccu"`
+	Month    string  `json:"month" tushare:"month"`
+	NtYoy    float64 `json:"ntYoy" tushare:"nt_yoy"`
+	NtMom    float64 `json:"ntMom" tushare:"nt_mom"`
+	NtAccu   float64 `json:"ntAccu" tushare:"nt_accu"`
+	TownYoy  float64 `json:"townYoy" tushare:"town_yoy"`
+	TownMom  float64 `json:"townMom" tushare:"town_mom"`
+	TownAccu float64 `json:"townAccu" tushare:"town_accu"`
+	CntYoy   float64 `json:"cntYoy" tushare:"cnt_yoy"`
+	CntMom   float64 `json:"cntMom" tushare:"cnt_mom"`
+	CntAccu  float64 `json:"cntAccu" tushare:"cnt_accu"`
+}
+
+type IndexDailyResp struct {
+	TradeDate time.Time `json:"tradeDate" tushare:"trade_date"`
+	Close     float64   `json:"close" tushare:"close"`
+}
+
+type DailyBasicResp struct {
+	TradeDate    string  `json:"tradeDate" tushare:"trade_date,date"`
+	TurnoverRate float64 `json:"turnoverRate" tushare:"turnover_rate"` // 换手率（%）
+}
+
+type IndexWeightResp struct {
+	ConCode   string `json:"conCode" tushare:"con_code"`
+	TradeDate string `json:"tradeDate" tushare:"trade_date,date"`
 }
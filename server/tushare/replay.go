@@ -0,0 +1,163 @@
+package tushare
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"go.uber.org/zap"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Fixture 录制的一次请求与响应
+type Fixture struct {
+	ApiName  string          `json:"api_name"`
+	Params   json.RawMessage `json:"params"`
+	Fields   string          `json:"fields"`
+	Response json.RawMessage `json:"response"`
+}
+
+// fixtureKey 以 api_name 与规范化后的 params 作为回放的匹配键
+func fixtureKey(apiName string, params json.RawMessage) (string, error) {
+	canonical, err := canonicalParams(params)
+	if err != nil {
+		return "", err
+	}
+	sum := sha1.Sum(append([]byte(apiName+":"), canonical...))
+	return hex.EncodeToString(sum[:8]), nil
+}
+
+// canonicalParams 重新序列化 params，使 key 顺序与空值写法一致
+func canonicalParams(params json.RawMessage) ([]byte, error) {
+	if len(params) == 0 || string(params) == "null" {
+		return []byte("{}"), nil
+	}
+	var v map[string]interface{}
+	if err := json.Unmarshal(params, &v); err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+func fixturePath(dir, apiName, key string) string {
+	return filepath.Join(dir, apiName, key+".json")
+}
+
+// record 将一次请求与原始响应写入录制目录
+func (c *Client) record(apiName string, params interface{}, fields string, body []byte) {
+	rawParams, err := json.Marshal(params)
+	if err != nil {
+		zap.S().Errorf("[record] [json.Marshal] [err] = %s", err.Error())
+		return
+	}
+	key, err := fixtureKey(apiName, rawParams)
+	if err != nil {
+		zap.S().Errorf("[record] [fixtureKey] [err] = %s", err.Error())
+		return
+	}
+
+	data, err := json.MarshalIndent(&Fixture{
+		ApiName:  apiName,
+		Params:   rawParams,
+		Fields:   fields,
+		Response: body,
+	}, "", "  ")
+	if err != nil {
+		zap.S().Errorf("[record] [json.MarshalIndent] [err] = %s", err.Error())
+		return
+	}
+
+	path := fixturePath(c.recordDir, apiName, key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		zap.S().Errorf("[record] [os.MkdirAll] [err] = %s", err.Error())
+		return
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		zap.S().Errorf("[record] [os.WriteFile] [err] = %s", err.Error())
+	}
+}
+
+// ReplayServer 按 api_name 与 params 回放录制的响应，模拟 tushare 接口
+type ReplayServer struct {
+	mu       sync.RWMutex
+	fixtures map[string]*Fixture
+}
+
+// NewReplayServer 加载目录下所有录制文件
+func NewReplayServer(dir string) (*ReplayServer, error) {
+	s := &ReplayServer{fixtures: make(map[string]*Fixture)}
+
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(path, ".json") {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		var f Fixture
+		if err := json.Unmarshal(data, &f); err != nil {
+			return fmt.Errorf("replay: %s: %w", path, err)
+		}
+		return s.Add(&f)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Add 注册一条回放数据，相同请求后加入的覆盖先加入的
+func (s *ReplayServer) Add(f *Fixture) error {
+	key, err := fixtureKey(f.ApiName, f.Params)
+	if err != nil {
+		return fmt.Errorf("replay: %s: %w", f.ApiName, err)
+	}
+
+	s.mu.Lock()
+	s.fixtures[key] = f
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *ReplayServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ApiName string          `json:"api_name"`
+		Params  json.RawMessage `json:"params"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	key, err := fixtureKey(req.ApiName, req.Params)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.RLock()
+	f, ok := s.fixtures[key]
+	s.mu.RUnlock()
+	if !ok {
+		zap.S().Warnf("[ReplayServer] no fixture for %s %s", req.ApiName, string(req.Params))
+		json.NewEncoder(w).Encode(&TuShareResp{
+			Code: -1,
+			Msg:  fmt.Sprintf("replay: no fixture for %s %s", req.ApiName, string(req.Params)),
+		})
+		return
+	}
+
+	w.Write(f.Response)
+}
@@ -0,0 +1,72 @@
+package tushare
+
+import (
+	"context"
+	"errors"
+	"financia/config"
+	"net/http/httptest"
+	"testing"
+)
+
+func Test_Replay(t *testing.T) {
+	replay, err := NewReplayServer("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(replay)
+	defer srv.Close()
+
+	old := defaultClient
+	SetDefault(newTestClient(srv.URL))
+	defer SetDefault(old)
+
+	list, err := DailyStockAll(context.Background(), &DailyReq{TsCode: "000001.SZ", StartDate: "20240102"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 3 || list[0].Close != 9.11 {
+		t.Fatalf("list = %+v", list)
+	}
+
+	// 未录制的请求返回业务错误
+	if _, err := DailyStockAll(context.Background(), &DailyReq{TsCode: "600000.SH"}); err == nil {
+		t.Fatal("want error for missing fixture")
+	}
+}
+
+func Test_RecordAndReplay(t *testing.T) {
+	upstream, err := NewReplayServer("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(upstream)
+	defer srv.Close()
+
+	dir := t.TempDir()
+	recorder := newTestClient(srv.URL)
+	recorder.recordDir = dir
+	req := &DailyReq{TsCode: "000001.SZ", StartDate: "20240102"}
+	if _, err := recorder.Post(context.Background(), "daily", req, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	replay, err := NewReplayServer(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv2 := httptest.NewServer(replay)
+	defer srv2.Close()
+
+	resp, err := NewClient(config.TuShareConfig{Url: srv2.URL}).Post(context.Background(), "daily", req, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Items) != 3 {
+		t.Fatalf("items = %d", len(resp.Items))
+	}
+
+	var apiErr *APIError
+	if _, err := NewClient(config.TuShareConfig{Url: srv2.URL}).Post(context.Background(), "income", req, ""); !errors.As(err, &apiErr) {
+		t.Fatalf("err = %v", err)
+	}
+}
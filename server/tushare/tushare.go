@@ -5,416 +5,205 @@ import (
 	"financia/public"
 	"financia/public/db/model"
 	"financia/util"
-	"github.com/spf13/cast"
-	"go.uber.org/zap"
 	"time"
 )
 
-func DailyStockAll(_ context.Context, req *DailyReq) []*model.StockData {
-	r := tuSharePost(public.TuShareDaily, req, "")
-
-	var resp DailyResp
-	if err := marshalResp(r, &resp); err != nil {
-		zap.S().Errorf("[DailyStockAll] [marshalResp] [err] = %s", err.Error())
-		return nil
-	}
-
-	data := make([]*model.StockData, 0, len(resp.Items))
-	for _, item := range resp.Items {
-		data = append(data, &model.StockData{
-			TsCode:    cast.ToString(item[0]),
-			TradeDate: util.ConvertDateStrToTime(cast.ToString(item[1]), util.TimeDateOnlyWithOutSep),
-			Open:      cast.ToFloat64(item[2]),
-			High:      cast.ToFloat64(item[3]),
-			Low:       cast.ToFloat64(item[4]),
-			Close:     cast.ToFloat64(item[5]),
-			PreClose:  cast.ToFloat64(item[6]),
-			Change:    cast.ToFloat64(item[7]),
-			PctChg:    cast.ToFloat64(item[8]),
-			Vol:       cast.ToInt64(item[9]),
-			Amount:    cast.ToFloat64(item[10]),
-		})
-	}
+// 各接口单次返回的最大行数
+const (
+	dailyPageLimit       = 6000
+	fundDailyPageLimit   = 2000
+	adjFactorPageLimit   = 2000
+	indexWeightPageLimit = 6000
+)
 
-	return data
+// forecastRow 业绩预告，update_flag 用于过滤旧版本
+type forecastRow struct {
+	StockForecastResp
+	UpdateFlag string `tushare:"update_flag"`
 }
 
-func DailyFundAll(_ context.Context, req *DailyReq) []*model.FundData {
-	r := tuSharePost(public.TuShareFundDaily, req, "")
-
-	var resp DailyResp
-	if err := marshalResp(r, &resp); err != nil {
-		zap.S().Errorf("[DailyFundAll] [marshalResp] [err] = %s", err.Error())
-		return nil
-	}
-
-	data := make([]*model.FundData, 0, len(resp.Items))
-	for _, item := range resp.Items {
-		data = append(data, &model.FundData{
-			TsCode:    cast.ToString(item[0]),
-			TradeDate: util.ConvertDateStrToTime(cast.ToString(item[1]), util.TimeDateOnlyWithOutSep),
-			Open:      cast.ToFloat64(item[2]),
-			High:      cast.ToFloat64(item[3]),
-			Low:       cast.ToFloat64(item[4]),
-			Close:     cast.ToFloat64(item[5]),
-			PreClose:  cast.ToFloat64(item[6]),
-			Change:    cast.ToFloat64(item[7]),
-			PctChg:    cast.ToFloat64(item[8]),
-			Vol:       cast.ToFloat64(item[9]),
-			Amount:    cast.ToFloat64(item[10]),
-		})
-	}
-
-	return data
+// hsgtTop10Row 沪深股通十大成交股，market_type 用于区分沪市与深市
+type hsgtTop10Row struct {
+	StockHsgtTop10Resp
+	MarketType string `tushare:"market_type"`
 }
 
-func FundSalesRatio(_ context.Context) []*FundSalesRatioResp {
-	r := tuSharePost(public.TuShareFundSalesRatio, nil, "")
-
-	var resp DailyResp
-	if err := marshalResp(r, &resp); err != nil {
-		zap.S().Errorf("[FundSalesRatio] [marshalResp] [err] = %s", err.Error())
-		return nil
-	}
-
-	list := make([]*FundSalesRatioResp, 0, len(resp.Items))
+func DailyStockAll(ctx context.Context, req *DailyReq) ([]*model.StockData, error) {
+	return query[model.StockData](ctx, public.TuShareDaily, req)
+}
 
-	for _, item := range resp.Items {
-		list = append(list, &FundSalesRatioResp{
-			Year:      cast.ToString(item[0]),
-			Bank:      cast.ToFloat64(item[1]),
-			SecComp:   cast.ToFloat64(item[2]),
-			FundComp:  cast.ToFloat64(item[3]),
-			IndepComp: cast.ToFloat64(item[4]),
-			Rests:     cast.ToFloat64(item[5]),
-		})
+// DailyStockByDate 某个交易日全部股票的日线，分页拉取
+func DailyStockByDate(ctx context.Context, tradeDate string) ([]*model.StockData, error) {
+	return queryPages[model.StockData](ctx, public.TuShareDaily, &DailyReq{TradeDate: tradeDate}, dailyPageLimit)
+}
 
-	}
+// AdjFactor 复权因子，可按 ts_code 或 trade_date 查询
+func AdjFactor(ctx context.Context, req *DailyReq) ([]*model.StockAdjFactor, error) {
+	return query[model.StockAdjFactor](ctx, public.TuShareAdjFactor, req)
+}
 
-	return list
+// AdjFactorByDate 某个交易日全部股票的复权因子，分页拉取
+func AdjFactorByDate(ctx context.Context, tradeDate string) ([]*model.StockAdjFactor, error) {
+	return queryPages[model.StockAdjFactor](ctx, public.TuShareAdjFactor, &DailyReq{TradeDate: tradeDate}, adjFactorPageLimit)
 }
 
-func FundSalesVol(_ context.Context) []*FundSalesVolResp {
-	r := tuSharePost(public.TuShareFundSalesVol, nil, "")
+func DailyFundAll(ctx context.Context, req *DailyReq) ([]*model.FundData, error) {
+	return query[model.FundData](ctx, public.TuShareFundDaily, req)
+}
 
-	var resp DailyResp
-	if err := marshalResp(r, &resp); err != nil {
-		zap.S().Errorf("[FundSalesVol] [marshalResp] [err] = %s", err.Error())
-		return nil
-	}
+// DailyFundByDate 某个交易日全部基金的日线，分页拉取
+func DailyFundByDate(ctx context.Context, tradeDate string) ([]*model.FundData, error) {
+	return queryPages[model.FundData](ctx, public.TuShareFundDaily, &DailyReq{TradeDate: tradeDate}, fundDailyPageLimit)
+}
 
-	list := make([]*FundSalesVolResp, 0, len(resp.Items))
-	for _, item := range resp.Items {
-		list = append(list, &FundSalesVolResp{
-			Year:      cast.ToString(item[0]),
-			Quarter:   cast.ToString(item[1]),
-			InstName:  cast.ToString(item[2]),
-			FundScale: cast.ToFloat64(item[3]),
-			Scale:     cast.ToFloat64(item[4]),
-			Rank:      cast.ToInt(item[5]),
-		})
-	}
+func FundSalesRatio(ctx context.Context) ([]*FundSalesRatioResp, error) {
+	return query[FundSalesRatioResp](ctx, public.TuShareFundSalesRatio, nil)
+}
 
-	return list
+func FundSalesVol(ctx context.Context) ([]*FundSalesVolResp, error) {
+	return query[FundSalesVolResp](ctx, public.TuShareFundSalesVol, nil)
 }
 
-func FutTradeCal(_ context.Context) ([]*FutTradeCalResp, []*FutTradeCalResp) {
+func FutTradeCal(ctx context.Context) ([]*FutTradeCalResp, []*FutTradeCalResp, error) {
 	now := time.Now().Add(-31 * 24 * time.Hour).Format(util.TimeDateOnlyWithOutSep)
 	end := time.Now().Add(52 * 24 * time.Hour).Format(util.TimeDateOnlyWithOutSep)
-	r := tuSharePost(public.TuShareFutTradeCal, &DailyReq{
-		Exchange:  "SSE",
-		StartDate: now,
-		EndDate:   end,
-	}, "")
-
-	var resp DailyResp
-	if err := marshalResp(r, &resp); err != nil {
-		zap.S().Errorf("[FutTradeCal] [marshalResp] [err] = %s", err.Error())
-		return nil, nil
-	}
 
-	var sse []*FutTradeCalResp
-	for _, item := range resp.Items {
-		sse = append(sse, &FutTradeCalResp{
-			CalDate: cast.ToString(item[1]),
-			IsOpen:  cast.ToInt(item[2]),
-		})
+	sse, err := TradeCal(ctx, "SSE", now, end)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	r = tuSharePost(public.TuShareFutTradeCal, &DailyReq{
-		Exchange:  "SZSE",
-		StartDate: now,
-		EndDate:   end,
-	}, "")
-
-	if err := marshalResp(r, &resp); err != nil {
-		zap.S().Errorf("[FutTradeCal] [marshalResp] [err] = %s", err.Error())
-		return nil, nil
+	szse, err := TradeCal(ctx, "SZSE", now, end)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	var szse []*FutTradeCalResp
-	for _, item := range resp.Items {
-		szse = append(szse, &FutTradeCalResp{
-			CalDate: cast.ToString(item[1]),
-			IsOpen:  cast.ToInt(item[2]),
-		})
-	}
+	return sse, szse, nil
+}
 
-	return sse, szse
+// TradeCal 交易所交易日历，日期格式 YYYYMMDD
+func TradeCal(ctx context.Context, exchange, start, end string) ([]*FutTradeCalResp, error) {
+	return query[FutTradeCalResp](ctx, public.TuShareFutTradeCal, &DailyReq{
+		Exchange:  exchange,
+		StartDate: start,
+		EndDate:   end,
+	})
 }
 
-func FutWeeklyDetail(_ context.Context, prd string) []*FutWeeklyDetailResp {
-	r := tuSharePost(public.TuShareFutWeeklyDetail, &DailyReq{
+func FutWeeklyDetail(ctx context.Context, prd string) ([]*FutWeeklyDetailResp, error) {
+	rows, err := query[FutWeeklyDetailResp](ctx, public.TuShareFutWeeklyDetail, &DailyReq{
 		Prd: prd,
-	}, "")
-
-	var resp DailyResp
-	if err := marshalResp(r, &resp); err != nil {
-		zap.S().Errorf("[FutWeeklyDetail] [marshalResp] [err] = %s", err.Error())
-		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	zap.S().Debugf("[FutWeeklyDetail] [resp] = %#v", resp.Items[0])
-	list := make([]*FutWeeklyDetailResp, 0, len(resp.Items))
-	for _, item := range resp.Items {
-		weekDate := cast.ToString(item[16])
-		if weekDate == "" {
+	list := make([]*FutWeeklyDetailResp, 0, len(rows))
+	for _, row := range rows {
+		if row.WeekDate == "" {
 			continue
 		}
-		list = append(list, &FutWeeklyDetailResp{
-			Vol:          cast.ToInt(item[3]),
-			VolYoy:       cast.ToFloat64(item[4]),
-			Amount:       cast.ToFloat64(item[5]),
-			AmountYoy:    cast.ToFloat64(item[6]),
-			CumVol:       cast.ToInt(item[7]),
-			CumVolYoy:    cast.ToFloat64(item[8]),
-			Cumamt:       cast.ToFloat64(item[9]),
-			CumamtYoy:    cast.ToFloat64(item[10]),
-			OpenInterest: cast.ToInt(item[11]),
-			InterestWow:  cast.ToFloat64(item[12]),
-			McClose:      cast.ToFloat64(item[13]),
-			CloseWow:     cast.ToFloat64(item[14]),
-			WeekDate:     weekDate,
-		})
+		list = append(list, row)
 	}
 
-	return list
+	return list, nil
 }
 
-func StockIncome(_ context.Context, tsCode string) []*StockIncomeResp {
-	r := tuSharePost(public.TuShareStockIncome, &DailyReq{
+func StockIncome(ctx context.Context, tsCode string) ([]*StockIncomeResp, error) {
+	return query[StockIncomeResp](ctx, public.TuShareStockIncome, &DailyReq{
 		TsCode:     tsCode,
 		ReportType: 1,
-	}, "ann_date,basic_eps,total_revenue,total_cogs,"+
-		"oper_exp,total_profit,income_tax,n_income,t_compr_income")
-
-	var resp DailyResp
-	if err := marshalResp(r, &resp); err != nil {
-		zap.S().Errorf("[StockIncome] [marshalResp] [err] = %s", err.Error())
-		return nil
-	}
-
-	list := make([]*StockIncomeResp, 0, len(resp.Items))
-	for _, item := range resp.Items {
-		list = append(list, &StockIncomeResp{
-			AnnDate:      util.ConvertDateStrToTime(cast.ToString(item[0]), util.TimeDateOnlyWithOutSep).Format(time.DateOnly),
-			BasicEps:     cast.ToFloat64(item[1]),
-			TotalRevenue: cast.ToFloat64(item[2]),
-			TotalCogs:    cast.ToFloat64(item[3]),
-			OperExp:      cast.ToFloat64(item[4]),
-			TotalProfit:  cast.ToFloat64(item[5]),
-			IncomeTax:    cast.ToFloat64(item[6]),
-			NIncome:      cast.ToFloat64(item[7]),
-			TComprIncome: cast.ToFloat64(item[8]),
-		})
-	}
-
-	return list
+	})
 }
 
-func StockForecast(_ context.Context, tsCode string) []*StockForecastResp {
-	r := tuSharePost(public.TuShareStockForecast, &DailyReq{
+func StockForecast(ctx context.Context, tsCode string) ([]*StockForecastResp, error) {
+	rows, err := query[forecastRow](ctx, public.TuShareStockForecast, &DailyReq{
 		TsCode: tsCode,
-	}, "ann_date,type,p_change_min,p_change_max,net_profit_min,"+
-		"net_profit_max,last_parent_net,change_reason,update_flag")
-
-	var resp DailyResp
-	if err := marshalResp(r, &resp); err != nil {
-		zap.S().Errorf("[StockForecast] [marshalResp] [err] = %s", err.Error())
-		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	list := make([]*StockForecastResp, 0, len(resp.Items))
-	for _, item := range resp.Items {
-		updateFlag := cast.ToString(item[8])
-		if updateFlag == "1" {
+	list := make([]*StockForecastResp, 0, len(rows))
+	for _, row := range rows {
+		if row.UpdateFlag == "1" {
 			continue
 		}
-
-		list = append(list, &StockForecastResp{
-			AnnDate:       util.ConvertDateStrToTime(cast.ToString(item[0]), util.TimeDateOnlyWithOutSep).Format(time.DateOnly),
-			Type:          cast.ToString(item[1]),
-			PChangeMin:    cast.ToFloat64(item[2]),
-			PChangeMax:    cast.ToFloat64(item[3]),
-			NetProfitMin:  cast.ToFloat64(item[4]),
-			NetProfitMax:  cast.ToFloat64(item[5]),
-			LastParentNet: cast.ToFloat64(item[6]),
-			ChangeReason:  cast.ToString(item[7]),
-		})
+		list = append(list, &row.StockForecastResp)
 	}
 
-	return list
+	return list, nil
 }
 
-func StockHolderTop10(_ context.Context, tsCode string) []*StockTop10Resp {
-	r := tuSharePost(public.TuShareStockHolderTop10, &DailyReq{
+func StockHolderTop10(ctx context.Context, tsCode string) ([]*StockTop10Resp, error) {
+	return query[StockTop10Resp](ctx, public.TuShareStockHolderTop10, &DailyReq{
 		TsCode: tsCode,
-	}, "ann_date,holder_name,hold_amount,hold_ratio,hold_float_ratio,hold_change,holder_type")
-
-	var resp DailyResp
-	if err := marshalResp(r, &resp); err != nil {
-		zap.S().Errorf("[StockHolderTop10] [marshalResp] [err] = %s", err.Error())
-		return nil
-	}
-
-	list := make([]*StockTop10Resp, 0, len(resp.Items))
-	for _, item := range resp.Items {
-		list = append(list, &StockTop10Resp{
-			AnnDate:        util.ConvertDateStrToTime(cast.ToString(item[0]), util.TimeDateOnlyWithOutSep).Format(time.DateOnly),
-			HolderName:     cast.ToString(item[1]),
-			HoldAmount:     cast.ToFloat64(item[2]),
-			HoldRatio:      cast.ToFloat64(item[3]),
-			HoldFloatRatio: cast.ToFloat64(item[4]),
-			HoldChange:     cast.ToFloat64(item[5]),
-			HolderType:     cast.ToString(item[6]),
-		})
-	}
-
-	return list
+	})
 }
 
-func StockHsgtTop10(_ context.Context, date string) ([]*StockHsgtTop10Resp, []*StockHsgtTop10Resp) {
-	r := tuSharePost(public.TuShareStockHsgtTop10, &DailyReq{
+func StockHsgtTop10(ctx context.Context, date string) ([]*StockHsgtTop10Resp, []*StockHsgtTop10Resp, error) {
+	rows, err := query[hsgtTop10Row](ctx, public.TuShareStockHsgtTop10, &DailyReq{
 		TradeDate: date,
-	}, "name,close,change,rank,market_type,amount")
-
-	var resp DailyResp
-	if err := marshalResp(r, &resp); err != nil {
-		zap.S().Errorf("[StockHsgtTop10] [marshalResp] [err] = %s", err.Error())
-		return nil, nil
+	})
+	if err != nil {
+		return nil, nil, err
 	}
 
 	var sh []*StockHsgtTop10Resp
 	var sz []*StockHsgtTop10Resp
 
-	for _, item := range resp.Items {
-		marketType := cast.ToString(item[4])
-		if marketType == "1" {
-			sh = append(sh, &StockHsgtTop10Resp{
-				Name:   cast.ToString(item[0]),
-				Close:  cast.ToFloat64(item[1]),
-				Change: cast.ToFloat64(item[2]),
-				Rank:   cast.ToInt(item[3]),
-				Amount: cast.ToFloat64(item[5]),
-			})
-		} else if marketType == "3" {
-			sz = append(sz, &StockHsgtTop10Resp{
-				Name:   cast.ToString(item[0]),
-				Close:  cast.ToFloat64(item[1]),
-				Change: cast.ToFloat64(item[2]),
-				Rank:   cast.ToInt(item[3]),
-				Amount: cast.ToFloat64(item[5]),
-			})
+	for _, row := range rows {
+		if row.MarketType == "1" {
+			sh = append(sh, &row.StockHsgtTop10Resp)
+		} else if row.MarketType == "3" {
+			sz = append(sz, &row.StockHsgtTop10Resp)
 		}
 	}
 
-	return sh, sz
+	return sh, sz, nil
 }
 
-func EconomicsShibor(_ context.Context) []*EconomicsShiborResp {
-	r := tuSharePost(public.TuShareEconomicsShibor, &DailyReq{
-		StartDate: "20240101",
-	}, "")
+// IndexDaily 指数日线，日期格式为 20060102
+func IndexDaily(ctx context.Context, tsCode, start, end string) ([]*IndexDailyResp, error) {
+	return query[IndexDailyResp](ctx, public.TuShareIndexDaily, &DailyReq{
+		TsCode:    tsCode,
+		StartDate: start,
+		EndDate:   end,
+	})
+}
 
-	var resp DailyResp
-	if err := marshalResp(r, &resp); err != nil {
-		zap.S().Errorf("[EconomicsShibor] [marshalResp] [err] = %s", err.Error())
-		return nil
-	}
+// DailyBasic 股票每日指标，只取换手率
+func DailyBasic(ctx context.Context, tsCode, start, end string) ([]*DailyBasicResp, error) {
+	return query[DailyBasicResp](ctx, public.TuShareDailyBasic, &DailyReq{
+		TsCode:    tsCode,
+		StartDate: start,
+		EndDate:   end,
+	})
+}
 
-	list := make([]*EconomicsShiborResp, 0, len(resp.Items))
-
-	for _, item := range resp.Items {
-		list = append(list, &EconomicsShiborResp{
-			Date:   util.ConvertDateStrToTime(cast.ToString(item[0]), util.TimeDateOnlyWithOutSep).Format(time.DateOnly),
-			On:     cast.ToFloat64(item[1]),
-			OneW:   cast.ToFloat64(item[2]),
-			TwoW:   cast.ToFloat64(item[3]),
-			OneM:   cast.ToFloat64(item[4]),
-			ThreeM: cast.ToFloat64(item[5]),
-			SixM:   cast.ToFloat64(item[6]),
-			NineM:  cast.ToFloat64(item[7]),
-			OneY:   cast.ToFloat64(item[8]),
-		})
-	}
+// IndexWeight 指数成分，每月公布一次
+func IndexWeight(ctx context.Context, indexCode, start, end string) ([]*IndexWeightResp, error) {
+	return queryPages[IndexWeightResp](ctx, public.TuShareIndexWeight, &DailyReq{
+		IndexCode: indexCode,
+		StartDate: start,
+		EndDate:   end,
+	}, indexWeightPageLimit)
+}
 
-	return list
+func EconomicsShibor(ctx context.Context) ([]*EconomicsShiborResp, error) {
+	return query[EconomicsShiborResp](ctx, public.TuShareEconomicsShibor, &DailyReq{
+		StartDate: "20240101",
+	})
 }
 
-func EconomicsCnGDP(_ context.Context, quarter string) []*EconomicsCnGDPResp {
-	r := tuSharePost(public.TuShareEconomicsCnGDP, &DailyReq{
+func EconomicsCnGDP(ctx context.Context, quarter string) ([]*EconomicsCnGDPResp, error) {
+	return query[EconomicsCnGDPResp](ctx, public.TuShareEconomicsCnGDP, &DailyReq{
 		Q: quarter,
-	}, "gdp,gdp_yoy,pi,pi_yoy,si,si_yoy,ti,ti_yoy")
-
-	var resp DailyResp
-	if err := marshalResp(r, &resp); err != nil {
-		zap.S().Errorf("[EconomicsCnGDP] [marshalResp] [err] = %s", err.Error())
-		return nil
-	}
-
-	list := make([]*EconomicsCnGDPResp, 0, len(resp.Items))
-	for _, item := range resp.Items {
-		list = append(list, &EconomicsCnGDPResp{
-			GDP:    cast.ToFloat64(item[0]),
-			GDPYoy: cast.ToFloat64(item[1]),
-			PI:     cast.ToFloat64(item[2]),
-			PIYoy:  cast.ToFloat64(item[3]),
-			SI:     cast.ToFloat64(item[4]),
-			SIYoy:  cast.ToFloat64(item[5]),
-			TI:     cast.ToFloat64(item[6]),
-			TIYoy:  cast.ToFloat64(item[7]),
-		})
-	}
-
-	return list
+	})
 }
 
-func EconomicsCnCPI(_ context.Context) []*EconomicsCnCPIResp {
-	r := tuSharePost(public.TuShareEconomicsCnCPI, &DailyReq{
+func EconomicsCnCPI(ctx context.Context) ([]*EconomicsCnCPIResp, error) {
+	return query[EconomicsCnCPIResp](ctx, public.TuShareEconomicsCnCPI, &DailyReq{
 		StartM: "202401",
-	}, "month,nt_yoy,nt_mom,nt_accu,town_yoy,town_mom,town_accu,cnt_yoy,cnt_mom,cnt_accu")
-
-	var resp DailyResp
-	if err := marshalResp(r, &resp); err != nil {
-		zap.S().Errorf("[EconomicsCnCPI] [marshalResp] [err] = %s", err.Error())
-		return nil
-	}
-
-	list := make([]*EconomicsCnCPIResp, 0, len(resp.Items))
-	for _, item := range resp.Items {
-		list = append(list, &EconomicsCnCPIResp{
-			Month:    cast.ToString(item[0]),
-			NtYoy:    cast.ToFloat64(item[1]),
-			NtMom:    cast.ToFloat64(item[2]),
-			NtAccu:   cast.ToFloat64(item[3]),
-			TownYoy:  cast.ToFloat64(item[4]),
-			TownMom:  cast.ToFloat64(item[5]),
-			TownAccu: cast.ToFloat64(item[6]),
-			CntYoy:   cast.ToFloat64(item[7]),
-			CntMom:   cast.ToFloat64(item[8]),
-			CntAccu:  cast.ToFloat64(item[9]),
-		})
-	}
-
-	return list
+	})
 }
@@ -0,0 +1,88 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"financia/public"
+	"financia/public/db/dao"
+	"financia/public/db/model"
+	"financia/server/tushare"
+	"fmt"
+	"go.uber.org/zap"
+)
+
+// ErrInvalidItem 自选条目的资产类型或期货品种不支持
+var ErrInvalidItem = errors.New("watchlist: invalid item")
+
+// NewWatchlistItem 按资产类型查出代码、名称与最新收盘价，股票、基金使用 refId，期货使用 prd
+// 收盘价获取失败时只记录日志，加入时的价格为空
+func NewWatchlistItem(ctx context.Context, asset string, refId int, prd string) (*model.WatchlistItem, error) {
+	item := &model.WatchlistItem{Asset: asset, RefId: refId}
+	switch asset {
+	case public.AssetStock:
+		info, err := dao.GetStockInfo(ctx, refId)
+		if err != nil {
+			return nil, err
+		}
+		item.Code, item.Name = info.TsCode, info.Name
+		if list, err := dao.GetStockDataLimit(ctx, info.TsCode, 1); err != nil {
+			zap.S().Errorf("[NewWatchlistItem] [GetStockDataLimit] [err] = %s", err.Error())
+		} else if len(list) > 0 {
+			item.AddedPrice = &list[0].Close
+		}
+	case public.AssetFund:
+		info, err := dao.GetFundInfo(ctx, refId)
+		if err != nil {
+			return nil, err
+		}
+		item.Code, item.Name = info.TsCode, info.Name
+		if list, err := dao.GetFundDataLimit(ctx, info.TsCode, 1); err != nil {
+			zap.S().Errorf("[NewWatchlistItem] [GetFundDataLimit] [err] = %s", err.Error())
+		} else if len(list) > 0 {
+			item.AddedPrice = &list[0].Close
+		}
+	case public.AssetFut:
+		name, ok := public.FutProductName(prd)
+		if !ok {
+			return nil, fmt.Errorf("%w: fut %q", ErrInvalidItem, prd)
+		}
+		item.RefId, item.Code, item.Name = 0, prd, name
+		// 期货只有周度数据，取最近一周主力合约的收盘价
+		list, err := tushare.FutWeeklyDetail(ctx, prd)
+		if err != nil {
+			zap.S().Errorf("[NewWatchlistItem] [FutWeeklyDetail] [err] = %s", err.Error())
+			break
+		}
+		var latest *tushare.FutWeeklyDetailResp
+		for _, v := range list {
+			if latest == nil || v.WeekDate > latest.WeekDate {
+				latest = v
+			}
+		}
+		if latest != nil {
+			item.AddedPrice = &latest.McClose
+		}
+	default:
+		return nil, fmt.Errorf("%w: asset %q", ErrInvalidItem, asset)
+	}
+	return item, nil
+}
+
+// Follow 关注股票或基金：已在任一列表中时不变，否则加入默认列表
+func Follow(ctx context.Context, userId int64, asset string, refId int) error {
+	followed, err := dao.IsFollowed(ctx, userId, asset, refId)
+	if err != nil || followed {
+		return err
+	}
+
+	watchlist, err := dao.GetDefaultWatchlist(ctx, userId)
+	if err != nil {
+		return err
+	}
+	item, err := NewWatchlistItem(ctx, asset, refId, "")
+	if err != nil {
+		return err
+	}
+	item.WatchlistId, item.UserId = watchlist.Id, userId
+	return dao.AddWatchlistItem(ctx, item)
+}
@@ -0,0 +1,202 @@
+package alert
+
+import (
+	"errors"
+	"financia/public"
+	"financia/public/db/dao"
+	"financia/public/db/model"
+	"financia/server/alert"
+	"financia/util"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"time"
+)
+
+// 每个用户最多的规则数量与触发记录返回的条数
+const (
+	maxRules  = 50
+	listLimit = 50
+)
+
+func CreateRule(c *gin.Context) {
+	var req CreateRuleReq
+	if err := c.ShouldBind(&req); err != nil {
+		util.FailRespWithCodeAndZap(c, util.ShouldBindJSONError, "[CreateRule] [ShouldBindJSON] [err] = %s", err.Error())
+		return
+	}
+	if err := checkThreshold(req.Type, req.Threshold); err != nil {
+		util.FailRespWithCodeAndZap(c, util.ReqDataError, "[CreateRule] [checkThreshold] [err] = %s", err.Error())
+		return
+	}
+
+	userId := util.GetUid(c)
+	count, err := dao.CountAlertRules(c, userId)
+	if err != nil {
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[CreateRule] [CountAlertRules] [err] = %s", err.Error())
+		return
+	}
+	if count >= maxRules {
+		util.FailRespWithCodeAndZap(c, util.ReqDataError, "[CreateRule] [CountAlertRules] [err] = %s", fmt.Sprintf("user %d has %d rules", userId, count))
+		return
+	}
+
+	rule := &model.AlertRule{
+		UserId:    userId,
+		Asset:     req.Asset,
+		RefId:     req.Id,
+		Type:      req.Type,
+		Threshold: req.Threshold,
+		Period:    rsiPeriod(req.Type, req.Period),
+		Cooldown:  cooldownHours(req.Cooldown),
+		Enabled:   true,
+	}
+	rule.TsCode, rule.Name, err = assetInfo(c, req.Asset, req.Id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		util.FailRespWithCode(c, util.DataEmptyError)
+		return
+	}
+	if err != nil {
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[CreateRule] [assetInfo] [err] = %s", err.Error())
+		return
+	}
+
+	if err := dao.CreateAlertRule(c, rule); err != nil {
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[CreateRule] [CreateAlertRule] [err] = %s", err.Error())
+		return
+	}
+
+	util.SuccessResp(c, &CreateRuleResp{
+		Rule: rule,
+	})
+}
+
+func ListRule(c *gin.Context) {
+	list, err := dao.ListAlertRules(c, util.GetUid(c))
+	if err != nil {
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[ListRule] [ListAlertRules] [err] = %s", err.Error())
+		return
+	}
+
+	util.SuccessResp(c, &ListRuleResp{
+		List: list,
+	})
+}
+
+func UpdateRule(c *gin.Context) {
+	var req UpdateRuleReq
+	if err := c.ShouldBind(&req); err != nil {
+		util.FailRespWithCodeAndZap(c, util.ShouldBindJSONError, "[UpdateRule] [ShouldBindJSON] [err] = %s", err.Error())
+		return
+	}
+
+	rule, err := dao.GetAlertRule(c, util.GetUid(c), req.Id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		util.FailRespWithCode(c, util.DataEmptyError)
+		return
+	}
+	if err != nil {
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[UpdateRule] [GetAlertRule] [err] = %s", err.Error())
+		return
+	}
+	if err := checkThreshold(rule.Type, req.Threshold); err != nil {
+		util.FailRespWithCodeAndZap(c, util.ReqDataError, "[UpdateRule] [checkThreshold] [err] = %s", err.Error())
+		return
+	}
+
+	rule.Threshold = req.Threshold
+	rule.Period = rsiPeriod(rule.Type, req.Period)
+	if req.Cooldown != 0 {
+		rule.Cooldown = req.Cooldown
+	}
+	if req.Enabled != nil {
+		rule.Enabled = *req.Enabled
+	}
+	if err := dao.UpdateAlertRule(c, rule); err != nil {
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[UpdateRule] [UpdateAlertRule] [err] = %s", err.Error())
+		return
+	}
+
+	util.SuccessResp(c, nil)
+}
+
+func DeleteRule(c *gin.Context) {
+	var req DeleteRuleReq
+	if err := c.ShouldBind(&req); err != nil {
+		util.FailRespWithCodeAndZap(c, util.ShouldBindJSONError, "[DeleteRule] [ShouldBindJSON] [err] = %s", err.Error())
+		return
+	}
+
+	deleted, err := dao.DeleteAlertRule(c, util.GetUid(c), req.Id)
+	if err != nil {
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[DeleteRule] [DeleteAlertRule] [err] = %s", err.Error())
+		return
+	}
+	if !deleted {
+		util.FailRespWithCode(c, util.DataEmptyError)
+		return
+	}
+
+	util.SuccessResp(c, nil)
+}
+
+func History(c *gin.Context) {
+	var req HistoryReq
+	if err := c.ShouldBind(&req); err != nil {
+		util.FailRespWithCodeAndZap(c, util.ShouldBindJSONError, "[History] [ShouldBindJSON] [err] = %s", err.Error())
+		return
+	}
+
+	list, err := dao.ListAlertFires(c, util.GetUid(c), req.RuleId, listLimit)
+	if err != nil {
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[History] [ListAlertFires] [err] = %s", err.Error())
+		return
+	}
+
+	util.SuccessResp(c, &HistoryResp{
+		List: list,
+	})
+}
+
+// checkThreshold RSI 的阈值在 0 到 100 之间
+func checkThreshold(ruleType string, threshold float64) error {
+	if (ruleType == alert.TypeRSIAbove || ruleType == alert.TypeRSIBelow) && threshold >= 100 {
+		return fmt.Errorf("rsi threshold %v out of range", threshold)
+	}
+	return nil
+}
+
+// rsiPeriod 只有 RSI 规则保存周期，未填写时使用默认值
+func rsiPeriod(ruleType string, period int) int {
+	if ruleType != alert.TypeRSIAbove && ruleType != alert.TypeRSIBelow {
+		return 0
+	}
+	if period == 0 {
+		return alert.DefaultRSIPeriod
+	}
+	return period
+}
+
+// cooldownHours 提醒间隔的小时数，未填写时使用默认值
+func cooldownHours(hours int) int {
+	if hours == 0 {
+		return int(alert.DefaultCooldown / time.Hour)
+	}
+	return hours
+}
+
+// assetInfo 股票、基金的代码与名称
+func assetInfo(c *gin.Context, asset string, id int) (string, string, error) {
+	if asset == public.AssetStock {
+		info, err := dao.GetStockInfo(c, id)
+		if err != nil {
+			return "", "", err
+		}
+		return info.TsCode, info.Name, nil
+	}
+	info, err := dao.GetFundInfo(c, id)
+	if err != nil {
+		return "", "", err
+	}
+	return info.TsCode, info.Name, nil
+}
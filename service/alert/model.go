@@ -0,0 +1,40 @@
+package alert
+
+import "financia/public/db/model"
+
+type CreateRuleReq struct {
+	Asset     string  `form:"asset" binding:"required,oneof=stock fund"`
+	Id        int     `form:"id" binding:"required"` // 股票、基金 ID
+	Type      string  `form:"type" binding:"required,oneof=cross_above cross_below pct_change predict_change rsi_above rsi_below"`
+	Threshold float64 `form:"threshold" binding:"required,gt=0"`          // 价格、涨跌幅（%）或 RSI
+	Period    int     `form:"period" binding:"omitempty,min=2,max=100"`   // RSI 周期，默认 14
+	Cooldown  int     `form:"cooldown" binding:"omitempty,min=1,max=720"` // 两次提醒的最短间隔，单位小时，默认 24
+}
+
+type CreateRuleResp struct {
+	Rule *model.AlertRule `json:"rule"`
+}
+
+type ListRuleResp struct {
+	List []*model.AlertRule `json:"list"`
+}
+
+type UpdateRuleReq struct {
+	Id        int64   `form:"id" binding:"required"`
+	Threshold float64 `form:"threshold" binding:"required,gt=0"`
+	Period    int     `form:"period" binding:"omitempty,min=2,max=100"`
+	Cooldown  int     `form:"cooldown" binding:"omitempty,min=1,max=720"` // 未填写时不修改
+	Enabled   *bool   `form:"enabled"`                                    // 未填写时不修改
+}
+
+type DeleteRuleReq struct {
+	Id int64 `form:"id" binding:"required"`
+}
+
+type HistoryReq struct {
+	RuleId int64 `form:"ruleId"` // 为空时返回全部规则的记录
+}
+
+type HistoryResp struct {
+	List []*model.AlertFire `json:"list"`
+}
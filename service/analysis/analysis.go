@@ -0,0 +1,57 @@
+package analysis
+
+import (
+	"errors"
+	"financia/public/db/dao"
+	"financia/util"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// listLimit 分析历史返回的条数
+const listLimit = 50
+
+func GetAnalysis(c *gin.Context) {
+	var req GetAnalysisReq
+	if err := c.ShouldBind(&req); err != nil {
+		util.FailRespWithCodeAndZap(c, util.ShouldBindJSONError, "[GetAnalysis] [ShouldBindJSON] [err] = %s", err.Error())
+		return
+	}
+
+	// 只能查看自己看过的分析
+	viewed, err := dao.HasAiAnalysisView(c, util.GetUid(c), req.Id)
+	if err != nil {
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[GetAnalysis] [HasAiAnalysisView] [err] = %s", err.Error())
+		return
+	}
+	if !viewed {
+		util.FailRespWithCode(c, util.DataEmptyError)
+		return
+	}
+
+	analysis, err := dao.GetAiAnalysis(c, req.Id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		util.FailRespWithCode(c, util.DataEmptyError)
+		return
+	}
+	if err != nil {
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[GetAnalysis] [GetAiAnalysis] [err] = %s", err.Error())
+		return
+	}
+
+	util.SuccessResp(c, &GetAnalysisResp{
+		Analysis: analysis,
+	})
+}
+
+func ListAnalysis(c *gin.Context) {
+	list, err := dao.ListAiAnalysisHistory(c, util.GetUid(c), listLimit)
+	if err != nil {
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[ListAnalysis] [ListAiAnalysisHistory] [err] = %s", err.Error())
+		return
+	}
+
+	util.SuccessResp(c, &ListAnalysisResp{
+		List: list,
+	})
+}
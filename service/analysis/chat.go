@@ -0,0 +1,125 @@
+package analysis
+
+import (
+	"errors"
+	"financia/public/db/dao"
+	"financia/server"
+	"financia/server/llm"
+	"financia/server/tushare"
+	"financia/util"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func CreateChat(c *gin.Context) {
+	var req CreateChatReq
+	if err := c.ShouldBind(&req); err != nil {
+		util.FailRespWithCodeAndZap(c, util.ShouldBindJSONError, "[CreateChat] [ShouldBindJSON] [err] = %s", err.Error())
+		return
+	}
+
+	sections := server.ContextSections(req.Asset, map[string]bool{
+		llm.SectionIncome:   req.Income,
+		llm.SectionForecast: req.Forecast,
+		llm.SectionHolder:   req.Holder,
+		llm.SectionPeer:     req.Peer,
+		llm.SectionMacro:    req.Macro,
+	})
+	chat, err := server.CreateChat(c, util.GetUid(c), req.Asset, req.Id, sections)
+	if err != nil {
+		util.FailRespWithCodeAndZap(c, tushare.RespCode(err), "[CreateChat] [CreateChat] [err] = %s", err.Error())
+		return
+	}
+
+	util.SuccessResp(c, &CreateChatResp{
+		ChatId: chat.Id,
+	})
+}
+
+func GetChat(c *gin.Context) {
+	var req GetChatReq
+	if err := c.ShouldBind(&req); err != nil {
+		util.FailRespWithCodeAndZap(c, util.ShouldBindJSONError, "[GetChat] [ShouldBindJSON] [err] = %s", err.Error())
+		return
+	}
+
+	chat, err := dao.GetAiChat(c, util.GetUid(c), req.ChatId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		util.FailRespWithCode(c, util.DataEmptyError)
+		return
+	}
+	if err != nil {
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[GetChat] [GetAiChat] [err] = %s", err.Error())
+		return
+	}
+
+	messages, err := dao.GetAiChatMessages(c, chat.Id)
+	if err != nil {
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[GetChat] [GetAiChatMessages] [err] = %s", err.Error())
+		return
+	}
+
+	util.SuccessResp(c, &GetChatResp{
+		Chat:     chat,
+		Messages: messages,
+	})
+}
+
+func ListChat(c *gin.Context) {
+	list, err := dao.ListAiChats(c, util.GetUid(c), listLimit)
+	if err != nil {
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[ListChat] [ListAiChats] [err] = %s", err.Error())
+		return
+	}
+
+	util.SuccessResp(c, &ListChatResp{
+		List: list,
+	})
+}
+
+func SendChat(c *gin.Context) {
+	var req SendChatReq
+	if err := c.ShouldBind(&req); err != nil {
+		util.FailRespWithCodeAndZap(c, util.ShouldBindJSONError, "[SendChat] [ShouldBindJSON] [err] = %s", err.Error())
+		return
+	}
+
+	chat, err := dao.GetAiChat(c, util.GetUid(c), req.ChatId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		util.FailRespWithCode(c, util.DataEmptyError)
+		return
+	}
+	if err != nil {
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[SendChat] [GetAiChat] [err] = %s", err.Error())
+		return
+	}
+
+	err = server.Chat(c, chat, req.Content)
+	if errors.Is(err, server.ErrQuotaExceeded) {
+		util.FailRespWithCode(c, util.QuotaExceededError)
+		return
+	}
+	if err != nil {
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[SendChat] [Chat] [err] = %s", err.Error())
+	}
+}
+
+func DeleteChat(c *gin.Context) {
+	var req DeleteChatReq
+	if err := c.ShouldBind(&req); err != nil {
+		util.FailRespWithCodeAndZap(c, util.ShouldBindJSONError, "[DeleteChat] [ShouldBindJSON] [err] = %s", err.Error())
+		return
+	}
+
+	deleted, err := dao.DeleteAiChat(c, util.GetUid(c), req.ChatId)
+	if err != nil {
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[DeleteChat] [DeleteAiChat] [err] = %s", err.Error())
+		return
+	}
+	if !deleted {
+		util.FailRespWithCode(c, util.DataEmptyError)
+		return
+	}
+
+	util.SuccessResp(c, nil)
+}
@@ -0,0 +1,51 @@
+package analysis
+
+import "financia/public/db/model"
+
+type GetAnalysisReq struct {
+	Id int64 `form:"id" binding:"required"`
+}
+
+type GetAnalysisResp struct {
+	Analysis *model.AiAnalysis `json:"analysis"`
+}
+
+type ListAnalysisResp struct {
+	List []*model.AiAnalysisHistory `json:"list"`
+}
+
+type CreateChatReq struct {
+	Asset    string `form:"asset" binding:"required,oneof=stock fund"`
+	Id       int    `form:"id" binding:"required"`
+	Income   bool   `form:"income"`   // 附带利润表，仅股票
+	Forecast bool   `form:"forecast"` // 附带业绩预告，仅股票
+	Holder   bool   `form:"holder"`   // 附带前十大股东，仅股票
+	Peer     bool   `form:"peer"`     // 附带同行业公司，仅股票
+	Macro    bool   `form:"macro"`    // 附带 SHIBOR 与 CPI
+}
+
+type CreateChatResp struct {
+	ChatId int64 `json:"chatId"`
+}
+
+type GetChatReq struct {
+	ChatId int64 `form:"chatId" binding:"required"`
+}
+
+type GetChatResp struct {
+	Chat     *model.AiChat          `json:"chat"`
+	Messages []*model.AiChatMessage `json:"messages"`
+}
+
+type ListChatResp struct {
+	List []*model.AiChat `json:"list"`
+}
+
+type SendChatReq struct {
+	ChatId  int64  `form:"chatId" binding:"required"`
+	Content string `form:"content" binding:"required,max=1000"`
+}
+
+type DeleteChatReq struct {
+	ChatId int64 `form:"chatId" binding:"required"`
+}
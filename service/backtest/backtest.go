@@ -0,0 +1,157 @@
+package backtest
+
+import (
+	"encoding/json"
+	"errors"
+	"financia/public"
+	"financia/public/db/dao"
+	"financia/public/db/model"
+	"financia/server"
+	"financia/server/backtest"
+	"financia/util"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"time"
+)
+
+const (
+	// listLimit 回测任务列表返回的条数
+	listLimit = 50
+	// maxPendingJobs 每个用户同时排队或运行的任务数
+	maxPendingJobs = 3
+	// maxSpanYears 回测区间的最长年数
+	maxSpanYears = 10
+)
+
+func CreateBacktest(c *gin.Context) {
+	var req CreateBacktestReq
+	if err := c.ShouldBind(&req); err != nil {
+		util.FailRespWithCodeAndZap(c, util.ShouldBindJSONError, "[CreateBacktest] [ShouldBindJSON] [err] = %s", err.Error())
+		return
+	}
+
+	start := util.ConvertDateStrToTime(req.StartDate, time.DateOnly)
+	end := util.ConvertDateStrToTime(req.EndDate, time.DateOnly)
+	if start.IsZero() || end.IsZero() || !start.Before(end) || end.After(start.AddDate(maxSpanYears, 0, 0)) {
+		util.FailRespWithCodeAndZap(c, util.ReqDataError, "[CreateBacktest] [ConvertDateStrToTime] [err] = %s", req.StartDate+" ~ "+req.EndDate)
+		return
+	}
+
+	params := &backtest.Params{
+		Strategy:   req.Strategy,
+		Predictor:  req.Predictor,
+		Window:     req.Window,
+		Threshold:  req.Threshold,
+		Fast:       req.Fast,
+		Slow:       req.Slow,
+		Period:     req.Period,
+		Lower:      req.Lower,
+		Upper:      req.Upper,
+		Commission: req.Commission,
+		Slippage:   req.Slippage,
+	}
+	// 校验参数并补全默认值
+	if _, err := backtest.NewStrategy(params); err != nil {
+		util.FailRespWithCodeAndZap(c, util.ReqDataError, "[CreateBacktest] [NewStrategy] [err] = %s", err.Error())
+		return
+	}
+	paramsStr, err := json.Marshal(params)
+	if err != nil {
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[CreateBacktest] [json.Marshal] [err] = %s", err.Error())
+		return
+	}
+
+	var tsCode string
+	if req.Asset == public.AssetStock {
+		info, err := dao.GetStockInfo(c, req.Id)
+		if err != nil {
+			util.FailRespWithCodeAndZap(c, util.InternalServerError, "[CreateBacktest] [GetStockInfo] [err] = %s", err.Error())
+			return
+		}
+		tsCode = info.TsCode
+	} else {
+		info, err := dao.GetFundInfo(c, req.Id)
+		if err != nil {
+			util.FailRespWithCodeAndZap(c, util.InternalServerError, "[CreateBacktest] [GetFundInfo] [err] = %s", err.Error())
+			return
+		}
+		tsCode = info.TsCode
+	}
+
+	pending, err := dao.CountUnfinishedBacktestJobs(c, util.GetUid(c))
+	if err != nil {
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[CreateBacktest] [CountUnfinishedBacktestJobs] [err] = %s", err.Error())
+		return
+	}
+	if pending >= maxPendingJobs {
+		util.FailRespWithCode(c, util.BusyError)
+		return
+	}
+
+	job := &model.BacktestJob{
+		UserId:    util.GetUid(c),
+		Asset:     req.Asset,
+		TsCode:    tsCode,
+		StartDate: start,
+		EndDate:   end,
+		Params:    string(paramsStr),
+		Status:    public.JobStatusPending,
+	}
+	if err := dao.CreateBacktestJob(c, job); err != nil {
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[CreateBacktest] [CreateBacktestJob] [err] = %s", err.Error())
+		return
+	}
+	if err := server.SubmitBacktest(job.Id); err != nil {
+		if err := dao.UpdateBacktestJob(c, job.Id, public.JobStatusFailed, err.Error(), ""); err != nil {
+			zap.S().Errorf("[CreateBacktest] [UpdateBacktestJob] [err] = %s", err.Error())
+		}
+		util.FailRespWithCodeAndZap(c, util.BusyError, "[CreateBacktest] [SubmitBacktest] [err] = %s", err.Error())
+		return
+	}
+
+	util.SuccessResp(c, &CreateBacktestResp{
+		JobId: job.Id,
+	})
+}
+
+func GetBacktest(c *gin.Context) {
+	var req GetBacktestReq
+	if err := c.ShouldBind(&req); err != nil {
+		util.FailRespWithCodeAndZap(c, util.ShouldBindJSONError, "[GetBacktest] [ShouldBindJSON] [err] = %s", err.Error())
+		return
+	}
+
+	job, err := dao.GetBacktestJob(c, req.JobId)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && job.UserId != util.GetUid(c)) {
+		util.FailRespWithCode(c, util.DataEmptyError)
+		return
+	}
+	if err != nil {
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[GetBacktest] [GetBacktestJob] [err] = %s", err.Error())
+		return
+	}
+
+	resp := &GetBacktestResp{Job: job}
+	if job.Status == public.JobStatusDone {
+		resp.Report = new(backtest.Report)
+		if err := json.Unmarshal([]byte(job.Result), resp.Report); err != nil {
+			util.FailRespWithCodeAndZap(c, util.InternalServerError, "[GetBacktest] [json.Unmarshal] [err] = %s", err.Error())
+			return
+		}
+	}
+
+	util.SuccessResp(c, resp)
+}
+
+func ListBacktest(c *gin.Context) {
+	list, err := dao.ListBacktestJobs(c, util.GetUid(c), listLimit)
+	if err != nil {
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[ListBacktest] [ListBacktestJobs] [err] = %s", err.Error())
+		return
+	}
+
+	util.SuccessResp(c, &ListBacktestResp{
+		List: list,
+	})
+}
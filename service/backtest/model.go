@@ -0,0 +1,42 @@
+package backtest
+
+import (
+	"financia/public/db/model"
+	"financia/server/backtest"
+)
+
+type CreateBacktestReq struct {
+	Asset     string  `form:"asset" binding:"required,oneof=stock fund"`
+	Id        int     `form:"id" binding:"required"`
+	StartDate string  `form:"startDate" binding:"required"`
+	EndDate   string  `form:"endDate" binding:"required"`
+	Strategy  string  `form:"strategy" binding:"required,oneof=predict macross rsi"`
+	Predictor string  `form:"predictor" binding:"omitempty,oneof=naive linear holtwinters"` // predict 策略使用的预测器，默认 linear
+	Window    int     `form:"window" binding:"omitempty,min=2,max=250"`                     // predict 策略的输入窗口，默认 31
+	Threshold float64 `form:"threshold"`                                                    // predict 策略的最小预测涨幅
+	Fast      int     `form:"fast" binding:"omitempty,min=1,max=250"`                       // macross 快线周期，默认 5
+	Slow      int     `form:"slow" binding:"omitempty,min=2,max=250"`                       // macross 慢线周期，默认 20
+	Period    int     `form:"period" binding:"omitempty,min=1,max=250"`                     // rsi 周期，默认 14
+	Lower     float64 `form:"lower" binding:"omitempty,gt=0,lt=100"`
+	Upper     float64 `form:"upper" binding:"omitempty,gt=0,lt=100"`
+	// 手续费率与滑点，按成交金额计
+	Commission float64 `form:"commission,default=0.0003" binding:"gte=0,lt=0.1"`
+	Slippage   float64 `form:"slippage,default=0.0005" binding:"gte=0,lt=0.1"`
+}
+
+type CreateBacktestResp struct {
+	JobId int64 `json:"jobId"`
+}
+
+type GetBacktestReq struct {
+	JobId int64 `form:"jobId" binding:"required"`
+}
+
+type GetBacktestResp struct {
+	Job    *model.BacktestJob `json:"job"`
+	Report *backtest.Report   `json:"report"` // 任务完成前为 null
+}
+
+type ListBacktestResp struct {
+	List []*model.BacktestJob `json:"list"`
+}
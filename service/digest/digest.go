@@ -0,0 +1,115 @@
+package digest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"financia/public/db/dao"
+	"financia/public/db/model"
+	"financia/server"
+	"financia/util"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"net/http"
+)
+
+func GetDigest(c *gin.Context) {
+	sub, err := dao.GetDigestSubscription(c, util.GetUid(c))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		util.SuccessResp(c, &GetDigestResp{SendHour: server.DefaultDigestHour})
+		return
+	}
+	if err != nil {
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[GetDigest] [GetDigestSubscription] [err] = %s", err.Error())
+		return
+	}
+
+	util.SuccessResp(c, &GetDigestResp{
+		Enabled:  sub.Enabled,
+		SendHour: sub.SendHour,
+	})
+}
+
+// Subscribe 开启每日摘要或修改发送时间
+func Subscribe(c *gin.Context) {
+	var req SubscribeReq
+	if err := c.ShouldBind(&req); err != nil {
+		util.FailRespWithCodeAndZap(c, util.ShouldBindJSONError, "[Subscribe] [ShouldBindJSON] [err] = %s", err.Error())
+		return
+	}
+
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[Subscribe] [rand.Read] [err] = %s", err.Error())
+		return
+	}
+	sub := &model.DigestSubscription{
+		UserId:   util.GetUid(c),
+		Enabled:  true,
+		SendHour: req.SendHour,
+		Token:    hex.EncodeToString(token),
+	}
+	if err := dao.SaveDigestSubscription(c, sub); err != nil {
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[Subscribe] [SaveDigestSubscription] [err] = %s", err.Error())
+		return
+	}
+
+	util.SuccessResp(c, nil)
+}
+
+func CancelDigest(c *gin.Context) {
+	if err := dao.DisableDigestSubscription(c, util.GetUid(c)); err != nil {
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[CancelDigest] [DisableDigestSubscription] [err] = %s", err.Error())
+		return
+	}
+
+	util.SuccessResp(c, nil)
+}
+
+// UnsubscribePage 邮件中的退订链接，按令牌显示确认页面，不修改订阅，不需要登录
+func UnsubscribePage(c *gin.Context) {
+	var req UnsubscribeReq
+	if err := c.ShouldBind(&req); err != nil {
+		zap.S().Errorf("[UnsubscribePage] [ShouldBindJSON] [err] = %s", err.Error())
+		renderPage(c, http.StatusBadRequest, &pageData{Message: "退订链接无效"})
+		return
+	}
+
+	_, err := dao.GetDigestSubscriptionByToken(c, req.Token)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		renderPage(c, http.StatusNotFound, &pageData{Message: "退订链接无效"})
+		return
+	}
+	if err != nil {
+		zap.S().Errorf("[UnsubscribePage] [GetDigestSubscriptionByToken] [err] = %s", err.Error())
+		renderPage(c, http.StatusInternalServerError, &pageData{Message: "服务器内部错误，请稍后再试"})
+		return
+	}
+
+	renderPage(c, http.StatusOK, &pageData{Token: req.Token})
+}
+
+// Unsubscribe 按令牌退订，不需要登录
+// 确认页面的表单与邮件客户端的一键退订（RFC 8058，请求体为 List-Unsubscribe=One-Click）都 POST 到退订链接
+func Unsubscribe(c *gin.Context) {
+	var req UnsubscribeReq
+	if err := c.ShouldBind(&req); err != nil {
+		zap.S().Errorf("[Unsubscribe] [ShouldBindJSON] [err] = %s", err.Error())
+		renderPage(c, http.StatusBadRequest, &pageData{Message: "退订链接无效"})
+		return
+	}
+
+	err := dao.UnsubscribeDigest(c, req.Token)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		renderPage(c, http.StatusNotFound, &pageData{Message: "退订链接无效"})
+		return
+	}
+	if err != nil {
+		zap.S().Errorf("[Unsubscribe] [UnsubscribeDigest] [err] = %s", err.Error())
+		renderPage(c, http.StatusInternalServerError, &pageData{Message: "服务器内部错误，请稍后再试"})
+		return
+	}
+
+	renderPage(c, http.StatusOK, &pageData{Message: "已退订每日摘要"})
+}
@@ -0,0 +1,14 @@
+package digest
+
+type GetDigestResp struct {
+	Enabled  bool `json:"enabled"`
+	SendHour int  `json:"sendHour"`
+}
+
+type SubscribeReq struct {
+	SendHour int `form:"sendHour,default=20" binding:"min=18,max=23"` // 发送的整点，北京时间
+}
+
+type UnsubscribeReq struct {
+	Token string `form:"token" binding:"required,max=64"`
+}
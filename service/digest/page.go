@@ -0,0 +1,42 @@
+package digest
+
+import (
+	"bytes"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"html/template"
+	"net/http"
+)
+
+// pageData 退订页面，Token 不为空时显示确认按钮，否则显示 Message
+type pageData struct {
+	Token   string
+	Message string
+}
+
+var unsubscribePage = template.Must(template.New("unsubscribe").Parse(`<!DOCTYPE html>
+<html lang="zh-CN">
+<head><meta charset="utf-8"><meta name="viewport" content="width=device-width, initial-scale=1"><title>退订每日摘要</title></head>
+<body style="font-family:sans-serif;max-width:480px;margin:60px auto;text-align:center">
+{{- if .Token}}
+<p>确定不再接收每日摘要邮件？</p>
+<form method="post">
+<input type="hidden" name="token" value="{{.Token}}">
+<button type="submit">确认退订</button>
+</form>
+{{- else}}
+<p>{{.Message}}</p>
+{{- end}}
+</body>
+</html>
+`))
+
+func renderPage(c *gin.Context, status int, data *pageData) {
+	var buf bytes.Buffer
+	if err := unsubscribePage.Execute(&buf, data); err != nil {
+		zap.S().Errorf("[renderPage] [Execute] [err] = %s", err.Error())
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+	c.Data(status, "text/html; charset=utf-8", buf.Bytes())
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/go-redis/redis/v8"
 	"go.uber.org/zap"
 	"sort"
+	"time"
 )
 
 func ShiborEconomics(c *gin.Context) {
@@ -21,14 +22,18 @@ func ShiborEconomics(c *gin.Context) {
 		return
 	}
 	if errors.Is(err, redis.Nil) {
-		list := tushare.EconomicsShibor(c)
+		list, err := tushare.EconomicsShibor(c)
+		if err != nil {
+			util.FailRespWithCodeAndZap(c, tushare.RespCode(err), "[ShiborEconomics] [EconomicsShibor] [err] = ", err.Error())
+			return
+		}
 		sort.Slice(list, func(i, j int) bool {
 			return list[i].Date < list[j].Date
 		})
 
 		go func() {
 			listStr, _ := json.Marshal(list)
-			if _, err := rdb.Set(c, public.RedisKeyShiborEconomics, listStr, 0).Result(); err != nil {
+			if _, err := rdb.Set(c, public.RedisKeyShiborEconomics, listStr, time.Duration(util.SecondsUntilMidnight())*time.Second).Result(); err != nil {
 				util.FailRespWithCodeAndZap(c, util.InternalServerError, "[ShiborEconomics] [rdb.Set] [err] = ", err.Error())
 				return
 			}
@@ -77,11 +82,15 @@ func CnGdpEconomics(c *gin.Context) {
 		return
 	}
 	if errors.Is(err, redis.Nil) {
-		list := tushare.EconomicsCnGDP(c, q)
+		list, err := tushare.EconomicsCnGDP(c, q)
+		if err != nil {
+			util.FailRespWithCodeAndZap(c, tushare.RespCode(err), "[CnGdpEconomics] [EconomicsCnGDP] [err] = ", err.Error())
+			return
+		}
 
 		go func() {
 			listStr, _ := json.Marshal(list)
-			if _, err := rdb.Set(c, public.RedisKeyCnGdpEconomics+q, listStr, 0).Result(); err != nil {
+			if _, err := rdb.Set(c, public.RedisKeyCnGdpEconomics+q, listStr, time.Duration(util.SecondsUntilMidnight())*time.Second).Result(); err != nil {
 				zap.S().Error("[CnGdpEconomics] [rdb.Set] [err] = ", err.Error())
 				return
 			}
@@ -112,14 +121,18 @@ func CnCpiEconomics(c *gin.Context) {
 		return
 	}
 	if errors.Is(err, redis.Nil) {
-		list := tushare.EconomicsCnCPI(c)
+		list, err := tushare.EconomicsCnCPI(c)
+		if err != nil {
+			util.FailRespWithCodeAndZap(c, tushare.RespCode(err), "[CnCpiEconomics] [EconomicsCnCPI] [err] = ", err.Error())
+			return
+		}
 		sort.Slice(list, func(i, j int) bool {
 			return list[i].Month < list[j].Month
 		})
 
 		go func() {
 			listStr, _ := json.Marshal(list)
-			_, err := rdb.Set(c, public.RedisKeyCnCpiEconomics, listStr, 0).Result()
+			_, err := rdb.Set(c, public.RedisKeyCnCpiEconomics, listStr, time.Duration(util.SecondsUntilMidnight())*time.Second).Result()
 			if err != nil {
 				zap.S().Error("[CnCpiEconomics] [rdb.Set] [err] = ", err.Error())
 				return
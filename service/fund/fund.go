@@ -8,10 +8,12 @@ import (
 	"financia/public/db/connector"
 	"financia/public/db/dao"
 	"financia/public/db/model"
-	"financia/server/python"
-	"financia/server/spark"
+	"financia/server"
+	"financia/server/forecast"
+	"financia/server/llm"
 	"financia/server/tushare"
 	"financia/util"
+	"financia/util/indicator"
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/go-redis/redis/v8"
@@ -35,6 +37,40 @@ func DataFund(c *gin.Context) {
 		return
 	}
 
+	if req.Period == "" {
+		req.Period = public.PeriodDay
+	}
+
+	rdb := connector.GetRedis().WithContext(c)
+	follow, err := dao.IsFollowed(c, util.GetUid(c), public.AssetFund, req.Id)
+	if err != nil {
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[DataFund] [IsFollowed] [err] = ", err.Error())
+		return
+	}
+
+	// 聚合 K 线走缓存
+	barsKey := fmt.Sprintf(public.RedisKeyFundBars, info.TsCode, req.Period, req.StartDate, req.EndDate)
+	if req.Period != public.PeriodDay {
+		result, err := rdb.Get(c, barsKey).Result()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			util.FailRespWithCodeAndZap(c, util.InternalServerError, "[DataFund] [rdb.Get] [err] = ", err.Error())
+			return
+		}
+		if result != "" {
+			respList := make([]*DataFundSimple, 0)
+			if err := json.Unmarshal([]byte(result), &respList); err != nil {
+				util.FailRespWithCodeAndZap(c, util.InternalServerError, "[DataFund] [json.Unmarshal] [err] = ", err.Error())
+				return
+			}
+			util.SuccessResp(c, &DataFundResp{
+				Follow: follow,
+				Have:   true,
+				List:   respList,
+			})
+			return
+		}
+	}
+
 	list, err := dao.GetFundData(c, info.TsCode, req.StartDate, req.EndDate)
 	if err != nil {
 		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[DataFund] [GetFundData] [err] = ", err.Error())
@@ -42,19 +78,36 @@ func DataFund(c *gin.Context) {
 	}
 
 	if len(list) == 0 {
-		data := tushare.DailyFundAll(c, &tushare.DailyReq{
+		data, err := tushare.DailyFundAll(c, &tushare.DailyReq{
 			TsCode: info.TsCode,
 		})
-		if err := dao.InsertFundData(c, data); err != nil {
-			zap.S().Error("[DataFund] [InsertStockData] [err] = ", err.Error())
+		if err != nil {
+			util.FailRespWithCodeAndZap(c, tushare.RespCode(err), "[DataFund] [DailyFundAll] [err] = %s", err.Error())
+			return
+		}
+		if err := dao.UpsertFundData(c, data); err != nil {
+			zap.S().Error("[DataFund] [UpsertFundData] [err] = ", err.Error())
 		}
 		list, err = dao.GetFundData(c, info.TsCode, req.StartDate, req.EndDate)
+		if err != nil {
+			util.FailRespWithCodeAndZap(c, util.InternalServerError, "[DataFund] [GetFundData] [err] = ", err.Error())
+			return
+		}
 	}
 
-	respList := make([]*DataFundSimple, 0, len(list))
-	for _, v := range list {
+	// 聚合周期按交易日历划分，日历不可用时按自然周期聚合且不缓存
+	var cal util.Calendar
+	if req.Period != public.PeriodDay && len(list) > 0 {
+		if cal, err = server.TradeCalendar(c, list[0].TradeDate); err != nil {
+			zap.S().Errorf("[DataFund] [TradeCalendar] [err] = %s", err.Error())
+		}
+	}
+	bars := util.AggregateBars(model.FundBars(list), req.Period, cal)
+
+	respList := make([]*DataFundSimple, 0, len(bars))
+	for _, v := range bars {
 		respList = append(respList, &DataFundSimple{
-			TradeDate: v.TradeDate.Format(time.DateOnly),
+			TradeDate: v.Date.Format(time.DateOnly),
 			Open:      v.Open,
 			High:      v.High,
 			Low:       v.Low,
@@ -67,8 +120,21 @@ func DataFund(c *gin.Context) {
 		})
 	}
 
+	// 最后一个周期尚未收盘时数据还会变化，不缓存
+	if req.Period != public.PeriodDay && len(bars) > 0 && cal.Closed(bars[len(bars)-1].Date, req.Period) {
+		go func() {
+			listStr, _ := json.Marshal(respList)
+			ctx := context.Background()
+			connector.GetRedis().WithContext(ctx).Set(ctx, barsKey, listStr, time.Duration(util.SecondsUntilMidnight())*time.Second)
+		}()
+	}
+
 	// 异步更新数据
 	go func() {
+		if len(list) == 0 {
+			return
+		}
+
 		ctx := context.Background()
 		rdb := connector.GetRedis().WithContext(ctx)
 		key := fmt.Sprintf(public.RedisKeyFundDataDoToday, info.TsCode)
@@ -79,26 +145,21 @@ func DataFund(c *gin.Context) {
 
 		last := list[len(list)-1]
 		date := strings.ReplaceAll(last.TradeDate.Add(time.Hour*24).Format(time.DateOnly), "-", "")
-		data := tushare.DailyFundAll(ctx, &tushare.DailyReq{
+		data, err := tushare.DailyFundAll(ctx, &tushare.DailyReq{
 			TsCode:    info.TsCode,
 			StartDate: date,
 		})
-		if err := dao.InsertFundData(ctx, data); err != nil {
-			zap.S().Error("[DataFund] [InsertFundData] [err] = ", err.Error())
+		if err != nil && !errors.Is(err, tushare.ErrEmptyResult) {
+			zap.S().Error("[DataFund] [DailyFundAll] [err] = ", err.Error())
+			return
+		}
+		if err := dao.UpsertFundData(ctx, data); err != nil {
+			zap.S().Error("[DataFund] [UpsertFundData] [err] = ", err.Error())
 		}
 
 		rdb.Set(ctx, key, "1", time.Duration(util.SecondsUntilMidnight())*time.Second)
 	}()
 
-	rdb := connector.GetRedis().WithContext(c)
-	userId := util.GetUid(c)
-	redisKey := fmt.Sprintf(public.RedisKeyFundFollow, userId)
-	follow, err := rdb.SIsMember(c, redisKey, req.Id).Result()
-	if err != nil {
-		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[DataFund] [rdb.SIsMember] [err] = ", err.Error())
-		return
-	}
-
 	util.SuccessResp(c, &DataFundResp{
 		Follow: follow,
 		Have:   true,
@@ -117,7 +178,11 @@ func GraphFund(c *gin.Context) {
 		return
 	}
 	if errors.Is(err, redis.Nil) {
-		radio := tushare.FundSalesRatio(c)
+		radio, err := tushare.FundSalesRatio(c)
+		if err != nil {
+			util.FailRespWithCodeAndZap(c, tushare.RespCode(err), "[GraphFund] [FundSalesRatio] [err] = %s", err.Error())
+			return
+		}
 
 		go func() {
 			listStr, _ := json.Marshal(radio)
@@ -143,7 +208,11 @@ func GraphFund(c *gin.Context) {
 		return
 	}
 	if errors.Is(err, redis.Nil) {
-		vol := tushare.FundSalesVol(c)
+		vol, err := tushare.FundSalesVol(c)
+		if err != nil {
+			util.FailRespWithCodeAndZap(c, tushare.RespCode(err), "[GraphFund] [FundSalesVol] [err] = %s", err.Error())
+			return
+		}
 
 		sort.Slice(vol, func(i, j int) bool {
 			// year sec
@@ -180,29 +249,33 @@ func GraphFund(c *gin.Context) {
 func HaveFund(c *gin.Context) {
 	var req HaveFundReq
 	if err := c.ShouldBind(&req); err != nil {
-		util.FailRespWithCodeAndZap(c, util.ShouldBindJSONError, "[DataFund] [ShouldBindJSON] [err] = ", err.Error())
+		util.FailRespWithCodeAndZap(c, util.ShouldBindJSONError, "[HaveFund] [ShouldBindJSON] [err] = ", err.Error())
 		return
 	}
 
 	info, err := dao.GetFundInfo(c, req.Id)
 	if err != nil {
-		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[DataFund] [GetFundInfo] [err] = ", err.Error())
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[HaveFund] [GetFundInfo] [err] = ", err.Error())
 		return
 	}
 
 	have, err := dao.CheckFundData(c, info.TsCode)
 	if err != nil {
-		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[DataFund] [CheckFundData] [err] = ", err.Error())
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[HaveFund] [CheckFundData] [err] = ", err.Error())
 		return
 	}
 
 	if !have {
-		data := tushare.DailyFundAll(c, &tushare.DailyReq{
+		data, err := tushare.DailyFundAll(c, &tushare.DailyReq{
 			TsCode: info.TsCode,
 		})
+		if err != nil && !errors.Is(err, tushare.ErrEmptyResult) {
+			util.FailRespWithCodeAndZap(c, tushare.RespCode(err), "[HaveFund] [DailyFundAll] [err] = %s", err.Error())
+			return
+		}
 		have = len(data) > 0
-		if err := dao.InsertFundData(c, data); err != nil {
-			util.FailRespWithCodeAndZap(c, util.InternalServerError, "[DataFund] [InsertFundData] [err] = ", err.Error())
+		if err := dao.UpsertFundData(c, data); err != nil {
+			util.FailRespWithCodeAndZap(c, util.InternalServerError, "[HaveFund] [UpsertFundData] [err] = ", err.Error())
 			return
 		}
 		if have {
@@ -222,13 +295,13 @@ func HaveFund(c *gin.Context) {
 func ListFund(c *gin.Context) {
 	var req ListFundReq
 	if err := c.ShouldBind(&req); err != nil {
-		util.FailRespWithCodeAndZap(c, util.ShouldBindJSONError, "[ListStock] [ShouldBindJSON] [err] = ", err.Error())
+		util.FailRespWithCodeAndZap(c, util.ShouldBindJSONError, "[ListFund] [ShouldBindJSON] [err] = ", err.Error())
 		return
 	}
 
 	list, count, err := dao.GetFundList(c, req.Search, req.FundType, req.InvestType, req.Page, req.PageSize)
 	if err != nil {
-		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[ListStock] [GetStockList] [err] = ", err.Error())
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[ListFund] [GetFundList] [err] = ", err.Error())
 		return
 	}
 
@@ -283,29 +356,14 @@ func FollowFund(c *gin.Context) {
 	}
 
 	userId := util.GetUid(c)
-	rdb := connector.GetRedis().WithContext(c)
-	redisKey := fmt.Sprintf(public.RedisKeyFundFollow, userId)
-
-	exists, err := rdb.SIsMember(c, redisKey, req.Id).Result()
-	if err != nil {
-		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[FollowFund] [rdb.SIsMember] [err] = ", err.Error())
-		return
-	}
-	if req.Follow == exists {
-		util.SuccessResp(c, nil)
-		return
-	}
-
 	if req.Follow {
-		_, err = rdb.SAdd(c, redisKey, req.Id).Result()
-		if err != nil {
-			util.FailRespWithCodeAndZap(c, util.InternalServerError, "[FollowFund] [rdb.SAdd] [err] = ", err.Error())
+		if err := server.Follow(c, userId, public.AssetFund, req.Id); err != nil {
+			util.FailRespWithCodeAndZap(c, util.InternalServerError, "[FollowFund] [Follow] [err] = ", err.Error())
 			return
 		}
 	} else {
-		_, err = rdb.SRem(c, redisKey, req.Id).Result()
-		if err != nil {
-			util.FailRespWithCodeAndZap(c, util.InternalServerError, "[FollowFund] [rdb.SRem] [err] = ", err.Error())
+		if err := dao.Unfollow(c, userId, public.AssetFund, req.Id); err != nil {
+			util.FailRespWithCodeAndZap(c, util.InternalServerError, "[FollowFund] [Unfollow] [err] = ", err.Error())
 			return
 		}
 	}
@@ -346,29 +404,29 @@ func PredictFund(c *gin.Context) {
 		last7 = append(last7, fundData[len(fundData)-7+i].Close)
 	}
 
-	rdb := connector.GetRedis().WithContext(c)
-	result, err := rdb.Get(c, fmt.Sprintf(public.RedisKeyFundPredict, req.Id)).Result()
-	if err != nil && !errors.Is(err, redis.Nil) {
-		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[PredictFund] [rdb.Get] [err] = ", err.Error())
+	path, err := forecast.CachedFund(c, req.Id)
+	if err != nil {
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[PredictFund] [CachedFund] [err] = ", err.Error())
 		return
 	}
-	if !errors.Is(err, redis.Nil) {
+	if path == nil {
+		status, err := server.EnqueuePredict(c, public.AssetFund, req.Id, fundInfo.TsCode)
+		if err != nil {
+			util.FailRespWithCodeAndZap(c, util.InternalServerError, "[PredictFund] [EnqueuePredict] [err] = %s", err.Error())
+			return
+		}
 		util.SuccessResp(c, &PredictFundResp{
-			List: last7,
-			Val:  cast.ToFloat64(result),
+			List:   last7,
+			Status: status,
 		})
 		return
 	}
 
-	val, err := python.PythonPredictFund(req.Id, fundData)
-	if err != nil {
-		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[PredictFund] [PythonPredict] [err] = ", err.Error())
-		return
-	}
-
 	util.SuccessResp(c, &PredictFundResp{
-		List: last7,
-		Val:  val,
+		List:   last7,
+		Val:    path[0].Val,
+		Path:   path,
+		Status: public.JobStatusDone,
 	})
 }
 
@@ -379,51 +437,64 @@ func AiFund(c *gin.Context) {
 		return
 	}
 
-	fundInfo, err := dao.GetFundInfo(c, req.Id)
+	data, tradeDate, err := server.LoadPromptData(c, public.AssetFund, req.Id)
 	if err != nil {
-		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[AiFund] [GetFundInfo] [err] = %s", err.Error())
+		util.FailRespWithCodeAndZap(c, tushare.RespCode(err), "[AiFund] [LoadPromptData] [err] = %s", err.Error())
 		return
 	}
 
-	limit30, err := dao.GetFundDataLimit30(c, fundInfo.TsCode)
-	if err != nil {
-		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[AiFund] [GetFundData] [err] = %s", err.Error())
+	err = server.Analyze(c, &server.Analysis{
+		UserId:    util.GetUid(c),
+		Asset:     public.AssetFund,
+		TradeDate: tradeDate,
+		Sections:  server.ContextSections(public.AssetFund, map[string]bool{llm.SectionMacro: req.Macro}),
+		Data:      data,
+	})
+	if errors.Is(err, server.ErrQuotaExceeded) {
+		util.FailRespWithCode(c, util.QuotaExceededError)
 		return
 	}
+	if err != nil {
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[AiFund] [Analyze] [err] = %s", err.Error())
+	}
+}
 
-	if len(limit30) == 0 {
-		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[AiFund] [GetFundData] [err] = 数据为空", "")
+func IndicatorsFund(c *gin.Context) {
+	var req IndicatorsFundReq
+	if err := c.ShouldBind(&req); err != nil {
+		util.FailRespWithCodeAndZap(c, util.ShouldBindJSONError, "[IndicatorsFund] [ShouldBindJSON] [err] = %s", err.Error())
 		return
 	}
 
-	var close []float64
-	for _, v := range limit30 {
-		close = append(close, v.Close)
+	specs, err := indicator.ParseSpecs(req.Indicators)
+	if err != nil {
+		util.FailRespWithCodeAndZap(c, util.ReqDataError, "[IndicatorsFund] [ParseIndicatorSpecs] [err] = %s", err.Error())
+		return
 	}
-
-	sort.Slice(limit30, func(i, j int) bool {
-		return limit30[i].TradeDate.Before(limit30[j].TradeDate)
-	})
-
-	last7 := make([]float64, 0, 7)
-	for i := range limit30[len(limit30)-7:] {
-		last7 = append(last7, limit30[i].Close)
+	start := util.ConvertDateStrToTime(req.StartDate, time.DateOnly)
+	if start.IsZero() {
+		util.FailRespWithCodeAndZap(c, util.ReqDataError, "[IndicatorsFund] [ConvertDateStrToTime] [err] = %s", req.StartDate)
+		return
 	}
 
-	var val float64
-
-	rdb := connector.GetRedis().WithContext(c)
-	result, err := rdb.Get(c, fmt.Sprintf(public.RedisKeyFundPredict, req.Id)).Result()
-	if err != nil && !errors.Is(err, redis.Nil) {
-		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[PredictFund] [rdb.Get] [err] = ", err.Error())
+	info, err := dao.GetFundInfo(c, req.Id)
+	if err != nil {
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[IndicatorsFund] [GetFundInfo] [err] = %s", err.Error())
 		return
 	}
-	if !errors.Is(err, redis.Nil) {
-		val = cast.ToFloat64(result)
-	} else {
-		val, _ = python.PythonPredictFund(req.Id, limit30)
+
+	// 向前多取数据，约 1.5 个自然日对应 1 个交易日
+	from := start.AddDate(0, 0, -(indicator.MaxLookback(specs)*3/2 + 10))
+	list, err := dao.GetFundData(c, info.TsCode, from.Format(time.DateOnly), req.EndDate)
+	if err != nil {
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[IndicatorsFund] [GetFundData] [err] = %s", err.Error())
+		return
 	}
 
-	spark.SendSparkHttp(c, close, cast.ToString(util.GetUid(c)), val)
-	return
+	dates, series := indicator.ComputeFrom(model.FundBars(list), specs, start)
+
+	util.SuccessResp(c, &IndicatorsFundResp{
+		Dates: dates,
+		List:  series,
+	})
 }
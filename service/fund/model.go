@@ -1,11 +1,16 @@
 package fund
 
-import "financia/server/tushare"
+import (
+	"financia/server/predictor"
+	"financia/server/tushare"
+	"financia/util/indicator"
+)
 
 type DataFundReq struct {
 	Id        int    `form:"id" binding:"required"`
 	StartDate string `form:"startDate" binding:"required"`
 	EndDate   string `form:"endDate" binding:"required"`
+	Period    string `form:"period" binding:"omitempty,oneof=D W M Q"` // K 线周期，默认日线
 }
 
 type DataFundResp struct {
@@ -89,14 +94,30 @@ type PredictFundReq struct {
 }
 
 type PredictFundResp struct {
-	List []float64 `json:"list"`
-	Val  float64   `json:"val"`
+	List []float64             `json:"list"`
+	Val  float64               `json:"val"`  // 下一交易日预测值
+	Path []*predictor.Forecast `json:"path"` // 未来 1 至 20 个交易日的预测值与 95% 区间
+	// 预测状态，done 时返回预测值；未缓存时提交预测任务并返回 pending 或 running，稍后重试
+	Status string `json:"status"`
 }
 
 type AiFundReq struct {
-	Id int `form:"id" binding:"required"`
+	Id    int  `form:"id" binding:"required"`
+	Macro bool `form:"macro"` // 附带 SHIBOR 与 CPI
 }
 
 type AiFundResp struct {
 	Content string `json:"content"`
 }
+
+type IndicatorsFundReq struct {
+	Id         int    `form:"id" binding:"required"`
+	StartDate  string `form:"startDate" binding:"required"`
+	EndDate    string `form:"endDate" binding:"required"`
+	Indicators string `form:"indicators" binding:"required"` // 如 ma:20,ema:60,macd:12:26:9,rsi:14
+}
+
+type IndicatorsFundResp struct {
+	Dates []string            `json:"dates"`
+	List  []*indicator.Series `json:"list"`
+}
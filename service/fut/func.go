@@ -7,8 +7,12 @@ import (
 	"time"
 )
 
-func tuShareSet(c *gin.Context, resp *CalFutResp) {
-	resp.Sse, resp.Szse = tushare.FutTradeCal(c)
+func tuShareSet(c *gin.Context, resp *CalFutResp) error {
+	var err error
+	resp.Sse, resp.Szse, err = tushare.FutTradeCal(c)
+	if err != nil {
+		return err
+	}
 
 	for i, v := range resp.Sse {
 		resp.Sse[i].CalDate = util.ConvertDateStrToTime(v.CalDate, util.TimeDateOnlyWithOutSep).Format(time.DateOnly)
@@ -16,4 +20,5 @@ func tuShareSet(c *gin.Context, resp *CalFutResp) {
 	for i, v := range resp.Szse {
 		resp.Szse[i].CalDate = util.ConvertDateStrToTime(v.CalDate, util.TimeDateOnlyWithOutSep).Format(time.DateOnly)
 	}
+	return nil
 }
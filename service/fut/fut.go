@@ -3,6 +3,7 @@ package fut
 import (
 	"encoding/json"
 	"errors"
+	"financia/public"
 	"financia/public/db/connector"
 	"financia/server/tushare"
 	"financia/util"
@@ -14,31 +15,12 @@ import (
 )
 
 func QueryFut(c *gin.Context) {
+	list := make([]*QueryFutSimple, 0, len(public.FutProducts))
+	for _, v := range public.FutProducts {
+		list = append(list, &QueryFutSimple{Prd: v.Prd, Name: v.Name})
+	}
 	util.SuccessResp(c, &QueryFutResp{
-		List: []*QueryFutSimple{
-			{Prd: "CU", Name: "铜"},
-			{Prd: "SR", Name: "白糖"},
-			{Prd: "CF", Name: "棉花"},
-			{Prd: "AL", Name: "铝"},
-			{Prd: "ZN", Name: "锌"},
-			{Prd: "JD", Name: "鸡蛋"},
-			{Prd: "FG", Name: "玻璃"},
-			{Prd: "AP", Name: "苹果"},
-			{Prd: "PP", Name: "聚丙烯"},
-			{Prd: "RB", Name: "螺纹钢"},
-			{Prd: "RO", Name: "菜籽油"},
-			{Prd: "M", Name: "豆粕"},
-			{Prd: "JM", Name: "焦煤"},
-			{Prd: "ZC", Name: "动力煤"},
-			{Prd: "Y", Name: "豆油"},
-			{Prd: "SS", Name: "不锈钢"},
-			{Prd: "BU", Name: "沥青"},
-			{Prd: "C", Name: "玉米"},
-			{Prd: "AU", Name: "黄金"},
-			{Prd: "RU", Name: "天胶"},
-			{Prd: "RR", Name: "粳米"},
-			{Prd: "RS", Name: "油菜籽"},
-		},
+		List: list,
 	})
 }
 
@@ -52,7 +34,10 @@ func CalFut(c *gin.Context) {
 	result, err := rdb.Get(c, "cal_fut").Result()
 	if err != nil {
 		zap.S().Errorf("[CalFut] [rdb.Get] [err] = %s", err.Error())
-		tuShareSet(c, resp)
+		if err := tuShareSet(c, resp); err != nil {
+			util.FailRespWithCodeAndZap(c, tushare.RespCode(err), "[CalFut] [tuShareSet] [err] = %s", err.Error())
+			return
+		}
 		// 当天0点过期
 		exp := util.SecondsUntilMidnight()
 		rdbStr, _ := json.Marshal(resp)
@@ -62,8 +47,11 @@ func CalFut(c *gin.Context) {
 		}
 	} else {
 		if err = json.Unmarshal([]byte(result), resp); err != nil {
-			util.FailRespWithCodeAndZap(c, util.InternalServerError, "[CalFut] [json.Unmarshal] [err] = %s", err.Error())
-			tuShareSet(c, resp)
+			zap.S().Errorf("[CalFut] [json.Unmarshal] [err] = %s", err.Error())
+			if err := tuShareSet(c, resp); err != nil {
+				util.FailRespWithCodeAndZap(c, tushare.RespCode(err), "[CalFut] [tuShareSet] [err] = %s", err.Error())
+				return
+			}
 		}
 	}
 
@@ -95,7 +83,11 @@ func DetailFut(c *gin.Context) {
 		return
 	}
 
-	list := tushare.FutWeeklyDetail(c, req.Prd)
+	list, err := tushare.FutWeeklyDetail(c, req.Prd)
+	if err != nil {
+		util.FailRespWithCodeAndZap(c, tushare.RespCode(err), "[DetailFut] [FutWeeklyDetail] [err] = %s", err.Error())
+		return
+	}
 	sort.Slice(list, func(i, j int) bool {
 		return list[i].WeekDate < list[j].WeekDate
 	})
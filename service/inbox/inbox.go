@@ -0,0 +1,44 @@
+package inbox
+
+import (
+	"financia/public/db/dao"
+	"financia/util"
+	"github.com/gin-gonic/gin"
+)
+
+// listLimit 站内信列表返回的条数
+const listLimit = 50
+
+func ListInbox(c *gin.Context) {
+	userId := util.GetUid(c)
+	list, err := dao.ListInboxMessages(c, userId, listLimit)
+	if err != nil {
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[ListInbox] [ListInboxMessages] [err] = %s", err.Error())
+		return
+	}
+	unread, err := dao.CountUnreadInboxMessages(c, userId)
+	if err != nil {
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[ListInbox] [CountUnreadInboxMessages] [err] = %s", err.Error())
+		return
+	}
+
+	util.SuccessResp(c, &ListInboxResp{
+		Unread: unread,
+		List:   list,
+	})
+}
+
+func ReadInbox(c *gin.Context) {
+	var req ReadInboxReq
+	if err := c.ShouldBind(&req); err != nil {
+		util.FailRespWithCodeAndZap(c, util.ShouldBindJSONError, "[ReadInbox] [ShouldBindJSON] [err] = %s", err.Error())
+		return
+	}
+
+	if err := dao.ReadInboxMessages(c, util.GetUid(c), req.Ids); err != nil {
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[ReadInbox] [ReadInboxMessages] [err] = %s", err.Error())
+		return
+	}
+
+	util.SuccessResp(c, nil)
+}
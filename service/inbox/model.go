@@ -0,0 +1,12 @@
+package inbox
+
+import "financia/public/db/model"
+
+type ListInboxResp struct {
+	Unread int64                 `json:"unread"`
+	List   []*model.InboxMessage `json:"list"`
+}
+
+type ReadInboxReq struct {
+	Ids []int64 `form:"ids"` // 为空时全部标记为已读
+}
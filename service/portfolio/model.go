@@ -0,0 +1,60 @@
+package portfolio
+
+import (
+	"financia/public/db/model"
+	"financia/server/portfolio"
+)
+
+type CreatePortfolioReq struct {
+	Name      string `form:"name" binding:"required,max=50"`
+	Method    string `form:"method,default=fifo" binding:"oneof=fifo average"` // 成本计算方法
+	Benchmark string `form:"benchmark" binding:"max=20"`                       // 基准指数代码，默认沪深300
+}
+
+type CreatePortfolioResp struct {
+	Id int64 `json:"id"`
+}
+
+type ListPortfolioResp struct {
+	List []*model.Portfolio `json:"list"`
+}
+
+type DeletePortfolioReq struct {
+	Id int64 `form:"id" binding:"required"`
+}
+
+type AddTransactionReq struct {
+	PortfolioId int64   `form:"portfolioId" binding:"required"`
+	Asset       string  `form:"asset" binding:"required,oneof=stock fund"`
+	Id          int     `form:"id" binding:"required"` // 股票、基金 ID
+	Side        string  `form:"side" binding:"required,oneof=buy sell"`
+	Quantity    float64 `form:"quantity" binding:"required,gt=0"`
+	Price       float64 `form:"price" binding:"required,gt=0"`
+	Fee         float64 `form:"fee" binding:"gte=0"`
+	TradeDate   string  `form:"tradeDate" binding:"required"` // 2006-01-02
+}
+
+type AddTransactionResp struct {
+	Transaction *model.PortfolioTransaction `json:"transaction"`
+}
+
+type ListTransactionReq struct {
+	PortfolioId int64 `form:"portfolioId" binding:"required"`
+}
+
+type ListTransactionResp struct {
+	List []*model.PortfolioTransaction `json:"list"`
+}
+
+type DeleteTransactionReq struct {
+	Id int64 `form:"id" binding:"required"`
+}
+
+type ReportReq struct {
+	Id int64 `form:"id" binding:"required"`
+}
+
+type ReportResp struct {
+	Name string `json:"name"`
+	*portfolio.Report
+}
@@ -0,0 +1,254 @@
+package portfolio
+
+import (
+	"errors"
+	"financia/public"
+	"financia/public/db/dao"
+	"financia/public/db/model"
+	"financia/server"
+	"financia/server/portfolio"
+	"financia/util"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"slices"
+	"time"
+)
+
+func CreatePortfolio(c *gin.Context) {
+	var req CreatePortfolioReq
+	if err := c.ShouldBind(&req); err != nil {
+		util.FailRespWithCodeAndZap(c, util.ShouldBindJSONError, "[CreatePortfolio] [ShouldBindJSON] [err] = %s", err.Error())
+		return
+	}
+	if req.Benchmark == "" {
+		req.Benchmark = portfolio.DefaultBenchmark
+	}
+
+	p := &model.Portfolio{
+		UserId:    util.GetUid(c),
+		Name:      req.Name,
+		Method:    req.Method,
+		Benchmark: req.Benchmark,
+	}
+	if err := dao.CreatePortfolio(c, p); err != nil {
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[CreatePortfolio] [CreatePortfolio] [err] = %s", err.Error())
+		return
+	}
+
+	util.SuccessResp(c, &CreatePortfolioResp{
+		Id: p.Id,
+	})
+}
+
+func ListPortfolio(c *gin.Context) {
+	list, err := dao.ListPortfolios(c, util.GetUid(c))
+	if err != nil {
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[ListPortfolio] [ListPortfolios] [err] = %s", err.Error())
+		return
+	}
+
+	util.SuccessResp(c, &ListPortfolioResp{
+		List: list,
+	})
+}
+
+func DeletePortfolio(c *gin.Context) {
+	var req DeletePortfolioReq
+	if err := c.ShouldBind(&req); err != nil {
+		util.FailRespWithCodeAndZap(c, util.ShouldBindJSONError, "[DeletePortfolio] [ShouldBindJSON] [err] = %s", err.Error())
+		return
+	}
+
+	deleted, err := dao.DeletePortfolio(c, util.GetUid(c), req.Id)
+	if err != nil {
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[DeletePortfolio] [DeletePortfolio] [err] = %s", err.Error())
+		return
+	}
+	if !deleted {
+		util.FailRespWithCode(c, util.DataEmptyError)
+		return
+	}
+
+	util.SuccessResp(c, nil)
+}
+
+func AddTransaction(c *gin.Context) {
+	var req AddTransactionReq
+	if err := c.ShouldBind(&req); err != nil {
+		util.FailRespWithCodeAndZap(c, util.ShouldBindJSONError, "[AddTransaction] [ShouldBindJSON] [err] = %s", err.Error())
+		return
+	}
+
+	tradeDate := util.ConvertDateStrToTime(req.TradeDate, time.DateOnly)
+	if tradeDate.IsZero() || tradeDate.After(time.Now()) {
+		util.FailRespWithCodeAndZap(c, util.ReqDataError, "[AddTransaction] [ConvertDateStrToTime] [err] = %s", req.TradeDate)
+		return
+	}
+
+	p, err := dao.GetPortfolio(c, util.GetUid(c), req.PortfolioId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		util.FailRespWithCode(c, util.DataEmptyError)
+		return
+	}
+	if err != nil {
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[AddTransaction] [GetPortfolio] [err] = %s", err.Error())
+		return
+	}
+
+	tx := &model.PortfolioTransaction{
+		PortfolioId: p.Id,
+		Asset:       req.Asset,
+		Side:        req.Side,
+		Quantity:    req.Quantity,
+		Price:       req.Price,
+		Fee:         req.Fee,
+		TradeDate:   tradeDate,
+	}
+	tx.TsCode, tx.Name, err = assetInfo(c, req.Asset, req.Id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		util.FailRespWithCode(c, util.DataEmptyError)
+		return
+	}
+	if err != nil {
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[AddTransaction] [assetInfo] [err] = %s", err.Error())
+		return
+	}
+
+	list, err := dao.GetPortfolioTransactions(c, p.Id)
+	if err != nil {
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[AddTransaction] [GetPortfolioTransactions] [err] = %s", err.Error())
+		return
+	}
+	if err := server.CheckPortfolio(p, append(list, tx)); err != nil {
+		util.FailRespWithCodeAndZap(c, util.ReqDataError, "[AddTransaction] [CheckPortfolio] [err] = %s", err.Error())
+		return
+	}
+	if err := dao.CreatePortfolioTransaction(c, tx); err != nil {
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[AddTransaction] [CreatePortfolioTransaction] [err] = %s", err.Error())
+		return
+	}
+
+	util.SuccessResp(c, &AddTransactionResp{
+		Transaction: tx,
+	})
+}
+
+func ListTransaction(c *gin.Context) {
+	var req ListTransactionReq
+	if err := c.ShouldBind(&req); err != nil {
+		util.FailRespWithCodeAndZap(c, util.ShouldBindJSONError, "[ListTransaction] [ShouldBindJSON] [err] = %s", err.Error())
+		return
+	}
+
+	p, err := dao.GetPortfolio(c, util.GetUid(c), req.PortfolioId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		util.FailRespWithCode(c, util.DataEmptyError)
+		return
+	}
+	if err != nil {
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[ListTransaction] [GetPortfolio] [err] = %s", err.Error())
+		return
+	}
+
+	list, err := dao.GetPortfolioTransactions(c, p.Id)
+	if err != nil {
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[ListTransaction] [GetPortfolioTransactions] [err] = %s", err.Error())
+		return
+	}
+
+	util.SuccessResp(c, &ListTransactionResp{
+		List: list,
+	})
+}
+
+// DeleteTransaction 删除一笔交易，删除后之后的卖出超过持仓时拒绝
+func DeleteTransaction(c *gin.Context) {
+	var req DeleteTransactionReq
+	if err := c.ShouldBind(&req); err != nil {
+		util.FailRespWithCodeAndZap(c, util.ShouldBindJSONError, "[DeleteTransaction] [ShouldBindJSON] [err] = %s", err.Error())
+		return
+	}
+
+	tx, err := dao.GetPortfolioTransaction(c, req.Id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		util.FailRespWithCode(c, util.DataEmptyError)
+		return
+	}
+	if err != nil {
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[DeleteTransaction] [GetPortfolioTransaction] [err] = %s", err.Error())
+		return
+	}
+	p, err := dao.GetPortfolio(c, util.GetUid(c), tx.PortfolioId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		util.FailRespWithCode(c, util.DataEmptyError)
+		return
+	}
+	if err != nil {
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[DeleteTransaction] [GetPortfolio] [err] = %s", err.Error())
+		return
+	}
+
+	list, err := dao.GetPortfolioTransactions(c, p.Id)
+	if err != nil {
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[DeleteTransaction] [GetPortfolioTransactions] [err] = %s", err.Error())
+		return
+	}
+	list = slices.DeleteFunc(list, func(v *model.PortfolioTransaction) bool {
+		return v.Id == tx.Id
+	})
+	if err := server.CheckPortfolio(p, list); err != nil {
+		util.FailRespWithCodeAndZap(c, util.ReqDataError, "[DeleteTransaction] [CheckPortfolio] [err] = %s", err.Error())
+		return
+	}
+	if err := dao.DeletePortfolioTransaction(c, tx.Id); err != nil {
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[DeleteTransaction] [DeletePortfolioTransaction] [err] = %s", err.Error())
+		return
+	}
+
+	util.SuccessResp(c, nil)
+}
+
+func Report(c *gin.Context) {
+	var req ReportReq
+	if err := c.ShouldBind(&req); err != nil {
+		util.FailRespWithCodeAndZap(c, util.ShouldBindJSONError, "[Report] [ShouldBindJSON] [err] = %s", err.Error())
+		return
+	}
+
+	p, err := dao.GetPortfolio(c, util.GetUid(c), req.Id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		util.FailRespWithCode(c, util.DataEmptyError)
+		return
+	}
+	if err != nil {
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[Report] [GetPortfolio] [err] = %s", err.Error())
+		return
+	}
+
+	report, err := server.PortfolioReport(c, p)
+	if err != nil {
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[Report] [PortfolioReport] [err] = %s", err.Error())
+		return
+	}
+
+	util.SuccessResp(c, &ReportResp{
+		Name:   p.Name,
+		Report: report,
+	})
+}
+
+// assetInfo 股票、基金的代码与名称
+func assetInfo(c *gin.Context, asset string, id int) (string, string, error) {
+	if asset == public.AssetStock {
+		info, err := dao.GetStockInfo(c, id)
+		if err != nil {
+			return "", "", err
+		}
+		return info.TsCode, info.Name, nil
+	}
+	info, err := dao.GetFundInfo(c, id)
+	if err != nil {
+		return "", "", err
+	}
+	return info.TsCode, info.Name, nil
+}
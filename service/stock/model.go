@@ -1,11 +1,18 @@
 package stock
 
-import "financia/server/tushare"
+import (
+	"financia/public/db/model"
+	"financia/server/predictor"
+	"financia/server/tushare"
+	"financia/util/indicator"
+)
 
 type DataStockReq struct {
 	Id        int    `form:"id" binding:"required"`
 	StartDate string `form:"startDate" binding:"required"`
 	EndDate   string `form:"endDate" binding:"required"`
+	Adj       string `form:"adj" binding:"omitempty,oneof=none qfq hfq"` // 复权方式，默认不复权
+	Period    string `form:"period" binding:"omitempty,oneof=D W M Q"`   // K 线周期，默认日线
 }
 
 type DataStockResp struct {
@@ -126,12 +133,16 @@ type Top10HsgtStockResp struct {
 }
 
 type PredictStockReq struct {
-	Id int `form:"id" binding:"required"`
+	Id  int    `form:"id" binding:"required"`
+	Adj string `form:"adj" binding:"omitempty,oneof=none qfq hfq"` // 复权方式，默认前复权
 }
 
 type PredictStockResp struct {
-	List []float64 `json:"list"`
-	Val  float64   `json:"val"`
+	List []float64             `json:"list"`
+	Val  float64               `json:"val"`  // 下一交易日预测值
+	Path []*predictor.Forecast `json:"path"` // 未来 1 至 20 个交易日的预测值与 95% 区间
+	// 预测状态，done 时返回预测值；未缓存时提交预测任务并返回 pending 或 running，稍后重试
+	Status string `json:"status"`
 }
 
 type FollowStockReq struct {
@@ -140,7 +151,12 @@ type FollowStockReq struct {
 }
 
 type AiStockReq struct {
-	Id int `form:"id" binding:"required"`
+	Id       int  `form:"id" binding:"required"`
+	Income   bool `form:"income"`   // 附带利润表
+	Forecast bool `form:"forecast"` // 附带业绩预告
+	Holder   bool `form:"holder"`   // 附带前十大股东
+	Peer     bool `form:"peer"`     // 附带同行业公司
+	Macro    bool `form:"macro"`    // 附带 SHIBOR 与 CPI
 }
 
 type AiStockResp struct {
@@ -164,12 +180,26 @@ type RankStockSimple struct {
 }
 
 type AccuracyStockReq struct {
-	Id int `form:"id" binding:"required"`
+	Id  int    `form:"id" binding:"required"`
+	Adj string `form:"adj" binding:"omitempty,oneof=none qfq hfq"` // recent 的复权方式，默认前复权，none 为预测当天的价格
 }
 
+// AccuracyStockResp 已实现预测的样本外准确率，由每日任务统计
 type AccuracyStockResp struct {
-	Accuracy string    `json:"accuracy"`
-	R2       string    `json:"r2"`
-	List     []float64 `json:"list"`
-	Val      float64   `json:"val"`
+	List    []*model.PredictAccuracy `json:"list"`    // 该股票各模型、各步数的准确率
+	Overall []*model.PredictAccuracy `json:"overall"` // 各模型在全部股票上的准确率
+	Recent  []*model.StockPredict    `json:"recent"`  // 最近已实现的下一交易日预测，按日期升序
+}
+
+type IndicatorsStockReq struct {
+	Id         int    `form:"id" binding:"required"`
+	StartDate  string `form:"startDate" binding:"required"`
+	EndDate    string `form:"endDate" binding:"required"`
+	Indicators string `form:"indicators" binding:"required"`              // 如 ma:20,ema:60,macd:12:26:9,rsi:14
+	Adj        string `form:"adj" binding:"omitempty,oneof=none qfq hfq"` // 复权方式，默认不复权
+}
+
+type IndicatorsStockResp struct {
+	Dates []string            `json:"dates"`
+	List  []*indicator.Series `json:"list"`
 }
@@ -8,17 +8,20 @@ import (
 	"financia/public/db/connector"
 	"financia/public/db/dao"
 	"financia/public/db/model"
-	"financia/server/python"
-	"financia/server/spark"
+	"financia/server"
+	"financia/server/forecast"
+	"financia/server/llm"
 	"financia/server/tushare"
 	"financia/service/fut"
 	"financia/util"
+	"financia/util/indicator"
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/go-redis/redis/v8"
 	"github.com/spf13/cast"
 	"go.uber.org/zap"
 	"math"
+	"slices"
 	"sort"
 	"strings"
 	"time"
@@ -26,6 +29,9 @@ import (
 
 var dataStock int
 
+// accuracyRecent 准确率接口返回的最近已实现预测条数
+const accuracyRecent = 30
+
 func DataStock(c *gin.Context) {
 	var req DataStockReq
 	if err := c.ShouldBind(&req); err != nil {
@@ -39,6 +45,36 @@ func DataStock(c *gin.Context) {
 		return
 	}
 
+	if req.Period == "" {
+		req.Period = public.PeriodDay
+	}
+	if req.Adj == "" {
+		req.Adj = public.AdjNone
+	}
+
+	// 聚合 K 线走缓存
+	rdb := connector.GetRedis().WithContext(c)
+	barsKey := fmt.Sprintf(public.RedisKeyStockBars, info.TsCode, req.Period, req.Adj, req.StartDate, req.EndDate)
+	if req.Period != public.PeriodDay {
+		result, err := rdb.Get(c, barsKey).Result()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			util.FailRespWithCodeAndZap(c, util.InternalServerError, "[DataStock] [rdb.Get] [err] = %s", err.Error())
+			return
+		}
+		if result != "" {
+			respList := make([]*DataStockSimple, 0)
+			if err := json.Unmarshal([]byte(result), &respList); err != nil {
+				util.FailRespWithCodeAndZap(c, util.InternalServerError, "[DataStock] [json.Unmarshal] [err] = %s", err.Error())
+				return
+			}
+			util.SuccessResp(c, &DataStockResp{
+				Have: true,
+				List: respList,
+			})
+			return
+		}
+	}
+
 	list, err := dao.GetStockData(c, info.TsCode, req.StartDate, req.EndDate)
 	if err != nil {
 		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[DataStock] [GetStockData] [err] = %s", err.Error())
@@ -46,19 +82,41 @@ func DataStock(c *gin.Context) {
 	}
 
 	if len(list) == 0 {
-		data := tushare.DailyStockAll(c, &tushare.DailyReq{
+		data, err := tushare.DailyStockAll(c, &tushare.DailyReq{
 			TsCode: info.TsCode,
 		})
-		if err := dao.InsertStockData(c, data); err != nil {
-			zap.S().Error("[DataStock] [InsertStockData] [err] = ", err.Error())
+		if err != nil {
+			util.FailRespWithCodeAndZap(c, tushare.RespCode(err), "[DataStock] [DailyStockAll] [err] = %s", err.Error())
+			return
+		}
+		if err := dao.UpsertStockData(c, data); err != nil {
+			zap.S().Error("[DataStock] [UpsertStockData] [err] = ", err.Error())
 		}
 		list, err = dao.GetStockData(c, info.TsCode, req.StartDate, req.EndDate)
+		if err != nil {
+			util.FailRespWithCodeAndZap(c, util.InternalServerError, "[DataStock] [GetStockData] [err] = %s", err.Error())
+			return
+		}
 	}
 
-	respList := make([]*DataStockSimple, 0, len(list))
-	for _, v := range list {
+	if err := server.AdjustStockData(c, info.TsCode, list, req.Adj); err != nil {
+		util.FailRespWithCodeAndZap(c, tushare.RespCode(err), "[DataStock] [AdjustStockData] [err] = %s", err.Error())
+		return
+	}
+
+	// 聚合周期按交易日历划分，日历不可用时按自然周期聚合且不缓存
+	var cal util.Calendar
+	if req.Period != public.PeriodDay && len(list) > 0 {
+		if cal, err = server.TradeCalendar(c, list[0].TradeDate); err != nil {
+			zap.S().Errorf("[DataStock] [TradeCalendar] [err] = %s", err.Error())
+		}
+	}
+	bars := util.AggregateBars(model.StockBars(list), req.Period, cal)
+
+	respList := make([]*DataStockSimple, 0, len(bars))
+	for _, v := range bars {
 		respList = append(respList, &DataStockSimple{
-			TradeDate: v.TradeDate.Format(time.DateOnly),
+			TradeDate: v.Date.Format(time.DateOnly),
 			Open:      v.Open,
 			High:      v.High,
 			Low:       v.Low,
@@ -66,13 +124,26 @@ func DataStock(c *gin.Context) {
 			PreClose:  v.PreClose,
 			Change:    v.Change,
 			PctChg:    v.PctChg,
-			Vol:       v.Vol,
+			Vol:       int64(v.Vol),
 			Amount:    v.Amount,
 		})
 	}
 
+	// 最后一个周期尚未收盘时数据还会变化，不缓存
+	if req.Period != public.PeriodDay && len(bars) > 0 && cal.Closed(bars[len(bars)-1].Date, req.Period) {
+		go func() {
+			listStr, _ := json.Marshal(respList)
+			ctx := context.Background()
+			connector.GetRedis().WithContext(ctx).Set(ctx, barsKey, listStr, time.Duration(util.SecondsUntilMidnight())*time.Second)
+		}()
+	}
+
 	// 异步更新数据
 	go func() {
+		if len(list) == 0 {
+			return
+		}
+
 		ctx := context.Background()
 		rdb := connector.GetRedis().WithContext(ctx)
 		key := fmt.Sprintf(public.RedisKeyStockDataDoToday, info.TsCode)
@@ -83,15 +154,19 @@ func DataStock(c *gin.Context) {
 
 		last := list[len(list)-1]
 		date := strings.ReplaceAll(last.TradeDate.Add(time.Hour*24).Format(time.DateOnly), "-", "")
-		data := tushare.DailyStockAll(ctx, &tushare.DailyReq{
+		data, err := tushare.DailyStockAll(ctx, &tushare.DailyReq{
 			TsCode:    info.TsCode,
 			StartDate: date,
 		})
+		if err != nil && !errors.Is(err, tushare.ErrEmptyResult) {
+			zap.S().Error("[DataStock] [DailyStockAll] [err] = ", err.Error())
+			return
+		}
 
 		zap.S().Debugf("异步更新数据 %s, 开始时间 last = %s,date = %s", info.TsCode, last.TradeDate, date)
 
-		if err := dao.InsertStockData(ctx, data); err != nil {
-			zap.S().Error("[DataStock] [InsertStockData] [err] = ", err.Error())
+		if err := dao.UpsertStockData(ctx, data); err != nil {
+			zap.S().Error("[DataStock] [UpsertStockData] [err] = ", err.Error())
 		}
 
 		rdb.Set(ctx, key, "1", time.Duration(util.SecondsUntilMidnight())*time.Second)
@@ -167,12 +242,16 @@ func HaveStock(c *gin.Context) {
 	}
 
 	if !have {
-		data := tushare.DailyStockAll(c, &tushare.DailyReq{
+		data, err := tushare.DailyStockAll(c, &tushare.DailyReq{
 			TsCode: info.TsCode,
 		})
+		if err != nil && !errors.Is(err, tushare.ErrEmptyResult) {
+			util.FailRespWithCodeAndZap(c, tushare.RespCode(err), "[HaveStock] [DailyStockAll] [err] = %s", err.Error())
+			return
+		}
 		have = len(data) > 0
-		if err := dao.InsertStockData(c, data); err != nil {
-			util.FailRespWithCodeAndZap(c, util.InternalServerError, "[HaveStock] [InsertStockData] [err] = %s", err.Error())
+		if err := dao.UpsertStockData(c, data); err != nil {
+			util.FailRespWithCodeAndZap(c, util.InternalServerError, "[HaveStock] [UpsertStockData] [err] = %s", err.Error())
 			return
 		}
 	}
@@ -196,9 +275,9 @@ func IncomeStock(c *gin.Context) {
 		return
 	}
 
-	incomeList := tushare.StockIncome(c, stockInfo.TsCode)
-	if incomeList == nil || len(incomeList) == 0 {
-		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[IncomeStock] [StockIncome] [err] = %s", "incomeList is nil")
+	incomeList, err := tushare.StockIncome(c, stockInfo.TsCode)
+	if err != nil {
+		util.FailRespWithCodeAndZap(c, tushare.RespCode(err), "[IncomeStock] [StockIncome] [err] = %s", err.Error())
 		return
 	}
 
@@ -220,12 +299,9 @@ func InfoStock(c *gin.Context) {
 		return
 	}
 
-	rdb := connector.GetRedis().WithContext(c)
-	userId := util.GetUid(c)
-	redisKey := fmt.Sprintf(public.RedisKeyStockFollow, userId)
-	follow, err := rdb.SIsMember(c, redisKey, req.Id).Result()
+	follow, err := dao.IsFollowed(c, util.GetUid(c), public.AssetStock, req.Id)
 	if err != nil {
-		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[InfoStock] [rdb.SIsMember] [err] = %s", err.Error())
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[InfoStock] [IsFollowed] [err] = %s", err.Error())
 		return
 	}
 
@@ -307,9 +383,9 @@ func ForecastStock(c *gin.Context) {
 		return
 	}
 
-	forecast := tushare.StockForecast(c, stockInfo.TsCode)
-	if forecast == nil || len(forecast) == 0 {
-		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[ForecastStock] [StockForecast] [err] = %s", "forecast is nil")
+	forecast, err := tushare.StockForecast(c, stockInfo.TsCode)
+	if err != nil {
+		util.FailRespWithCodeAndZap(c, tushare.RespCode(err), "[ForecastStock] [StockForecast] [err] = %s", err.Error())
 		return
 	}
 
@@ -335,9 +411,9 @@ func Top10Stock(c *gin.Context) {
 		return
 	}
 
-	top10 := tushare.StockHolderTop10(c, stockInfo.TsCode)
-	if top10 == nil || len(top10) == 0 {
-		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[Top10Stock] [StockHolderTop10] [err] = %s", "top10 is nil")
+	top10, err := tushare.StockHolderTop10(c, stockInfo.TsCode)
+	if err != nil {
+		util.FailRespWithCodeAndZap(c, tushare.RespCode(err), "[Top10Stock] [StockHolderTop10] [err] = %s", err.Error())
 		return
 	}
 
@@ -388,7 +464,11 @@ func Top10HsgtStock(c *gin.Context) {
 		}
 	}
 
-	sh, sz := tushare.StockHsgtTop10(c, date)
+	sh, sz, err := tushare.StockHsgtTop10(c, date)
+	if err != nil {
+		util.FailRespWithCodeAndZap(c, tushare.RespCode(err), "[Top10HsgtStock] [StockHsgtTop10] [err] = %s", err.Error())
+		return
+	}
 
 	util.SuccessResp(c, &Top10HsgtStockResp{
 		ShList: sh,
@@ -409,14 +489,22 @@ func PredictStock(c *gin.Context) {
 		return
 	}
 
-	stockData, err := dao.GetStockDataLimit30(c, stockInfo.TsCode)
+	stockData, err := dao.GetStockDataLimit(c, stockInfo.TsCode, forecast.History())
 	if err != nil {
-		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[PredictStock] [GetStockDataLimit30] [err] = %s", err.Error())
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[PredictStock] [GetStockDataLimit] [err] = %s", err.Error())
 		return
 	}
 
 	if len(stockData) == 0 {
-		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[PredictStock] [GetStockDataLimit30] [err] = %s", "stockData is nil")
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[PredictStock] [GetStockDataLimit] [err] = %s", "stockData is nil")
+		return
+	}
+
+	if req.Adj == "" {
+		req.Adj = public.AdjQfq
+	}
+	if err := server.AdjustStockData(c, stockInfo.TsCode, stockData, req.Adj); err != nil {
+		util.FailRespWithCodeAndZap(c, tushare.RespCode(err), "[PredictStock] [AdjustStockData] [err] = %s", err.Error())
 		return
 	}
 
@@ -429,29 +517,46 @@ func PredictStock(c *gin.Context) {
 		last7 = append(last7, stockData[len(stockData)-7+i].Close)
 	}
 
-	rdb := connector.GetRedis().WithContext(c)
-	result, err := rdb.Get(c, fmt.Sprintf(public.RedisKeyStockPredict, req.Id)).Result()
-	if err != nil && !errors.Is(err, redis.Nil) {
-		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[PredictStock] [rdb.Get] [err] = %s", err.Error())
-		return
-	}
-	if !errors.Is(err, redis.Nil) {
+	// 只缓存默认的前复权预测
+	if req.Adj != public.AdjQfq {
+		path, err := forecast.StockUncached(c, stockData)
+		if err != nil {
+			util.FailRespWithCodeAndZap(c, util.InternalServerError, "[PredictStock] [StockUncached] [err] = %s", err.Error())
+			return
+		}
+
 		util.SuccessResp(c, &PredictStockResp{
-			List: last7,
-			Val:  cast.ToFloat64(result),
+			List:   last7,
+			Val:    path[0].Val,
+			Path:   path,
+			Status: public.JobStatusDone,
 		})
 		return
 	}
 
-	val, err := python.PythonPredictStock(req.Id, stockData)
+	path, err := forecast.CachedStock(c, req.Id)
 	if err != nil {
-		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[PredictStock] [PythonPredictStock] [err] = %s", err.Error())
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[PredictStock] [CachedStock] [err] = %s", err.Error())
+		return
+	}
+	if path == nil {
+		status, err := server.EnqueuePredict(c, public.AssetStock, req.Id, stockInfo.TsCode)
+		if err != nil {
+			util.FailRespWithCodeAndZap(c, util.InternalServerError, "[PredictStock] [EnqueuePredict] [err] = %s", err.Error())
+			return
+		}
+		util.SuccessResp(c, &PredictStockResp{
+			List:   last7,
+			Status: status,
+		})
 		return
 	}
 
 	util.SuccessResp(c, &PredictStockResp{
-		List: last7,
-		Val:  val,
+		List:   last7,
+		Val:    path[0].Val,
+		Path:   path,
+		Status: public.JobStatusDone,
 	})
 }
 
@@ -463,27 +568,14 @@ func FollowStock(c *gin.Context) {
 	}
 
 	userId := util.GetUid(c)
-	rdb := connector.GetRedis().WithContext(c)
-	redisKey := fmt.Sprintf(public.RedisKeyStockFollow, userId)
-
-	exists, err := rdb.SIsMember(c, redisKey, req.Id).Result()
-	if err != nil {
-		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[FollowStock] [rdb.SIsMember] [err] = %s", err.Error())
-		return
-	}
-	if req.Follow == exists {
-		util.SuccessResp(c, nil)
-		return
-	}
-
 	if req.Follow {
-		if _, err = rdb.SAdd(c, redisKey, req.Id).Result(); err != nil {
-			util.FailRespWithCodeAndZap(c, util.InternalServerError, "[FollowStock] [rdb.SAdd] [err] = %s", err.Error())
+		if err := server.Follow(c, userId, public.AssetStock, req.Id); err != nil {
+			util.FailRespWithCodeAndZap(c, util.InternalServerError, "[FollowStock] [Follow] [err] = %s", err.Error())
 			return
 		}
 	} else {
-		if _, err = rdb.SRem(c, redisKey, req.Id).Result(); err != nil {
-			util.FailRespWithCodeAndZap(c, util.InternalServerError, "[FollowStock] [rdb.SRem] [err] = %s", err.Error())
+		if err := dao.Unfollow(c, userId, public.AssetStock, req.Id); err != nil {
+			util.FailRespWithCodeAndZap(c, util.InternalServerError, "[FollowStock] [Unfollow] [err] = %s", err.Error())
 			return
 		}
 	}
@@ -498,53 +590,32 @@ func AiStock(c *gin.Context) {
 		return
 	}
 
-	stockInfo, err := dao.GetStockInfo(c, req.Id)
-	if err != nil {
-		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[AiStock] [GetStockInfo] [err] = %s", err.Error())
-		return
-	}
-
-	limit30, err := dao.GetStockDataLimit30(c, stockInfo.TsCode)
+	data, tradeDate, err := server.LoadPromptData(c, public.AssetStock, req.Id)
 	if err != nil {
-		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[AiStock] [GetStockDataLimit30] [err] = %s", err.Error())
-		return
-	}
-
-	if len(limit30) == 0 {
-		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[AiStock] [GetStockDataLimit30] [err] = %s", "limit30 is nil")
-		return
-	}
-
-	var close []float64
-	for _, v := range limit30 {
-		close = append(close, v.Close)
-	}
-
-	sort.Slice(limit30, func(i, j int) bool {
-		return limit30[i].TradeDate.Before(limit30[j].TradeDate)
+		util.FailRespWithCodeAndZap(c, tushare.RespCode(err), "[AiStock] [LoadPromptData] [err] = %s", err.Error())
+		return
+	}
+
+	err = server.Analyze(c, &server.Analysis{
+		UserId:    util.GetUid(c),
+		Asset:     public.AssetStock,
+		TradeDate: tradeDate,
+		Sections: server.ContextSections(public.AssetStock, map[string]bool{
+			llm.SectionIncome:   req.Income,
+			llm.SectionForecast: req.Forecast,
+			llm.SectionHolder:   req.Holder,
+			llm.SectionPeer:     req.Peer,
+			llm.SectionMacro:    req.Macro,
+		}),
+		Data: data,
 	})
-
-	last7 := make([]float64, 0, 7)
-	for i := range limit30[len(limit30)-7:] {
-		last7 = append(last7, limit30[i].Close)
-	}
-
-	var val float64
-
-	rdb := connector.GetRedis().WithContext(c)
-	result, err := rdb.Get(c, fmt.Sprintf(public.RedisKeyFundPredict, req.Id)).Result()
-	if err != nil && !errors.Is(err, redis.Nil) {
-		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[PredictStock] [rdb.Get] [err] = ", err.Error())
+	if errors.Is(err, server.ErrQuotaExceeded) {
+		util.FailRespWithCode(c, util.QuotaExceededError)
 		return
 	}
-	if !errors.Is(err, redis.Nil) {
-		val = cast.ToFloat64(result)
-	} else {
-		val, _ = python.PythonPredictStock(req.Id, limit30)
+	if err != nil {
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[AiStock] [Analyze] [err] = %s", err.Error())
 	}
-
-	spark.SendSparkHttp(c, close, cast.ToString(util.GetUid(c)), val)
-	return
 }
 
 func RankStock(c *gin.Context) {
@@ -630,65 +701,96 @@ func AccuracyStock(c *gin.Context) {
 		return
 	}
 
-	// 获取股票信息
 	stockInfo, err := dao.GetStockInfo(c, req.Id)
 	if err != nil {
 		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[AccuracyStock] [GetStockInfo] [err] = %s", err.Error())
 		return
 	}
 
-	stockData, err := dao.GetAllStockData(c, stockInfo.TsCode)
+	list, err := dao.GetPredictAccuracy(c, public.AssetStock, stockInfo.TsCode)
 	if err != nil {
-		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[AccuracyStock] [GetAllStockData] [err] = %s", err.Error())
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[AccuracyStock] [GetPredictAccuracy] [err] = %s", err.Error())
 		return
 	}
 
-	// 限制预测数量，降低服务压力
-	if len(stockData) > 1500 {
-		stockData = stockData[len(stockData)-1500:]
+	overall, err := dao.GetPredictAccuracy(c, public.AssetStock, "")
+	if err != nil {
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[AccuracyStock] [GetPredictAccuracy] [err] = %s", err.Error())
+		return
 	}
 
-	predictList, err := python.PythonPredictAllStock(stockInfo.Id, stockData)
+	recent, err := dao.GetRecentStockPredicts(c, stockInfo.TsCode, 1, accuracyRecent)
 	if err != nil {
-		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[AccuracyStock] [PythonPredictAllStock] [err] = %s", err.Error())
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[AccuracyStock] [GetRecentStockPredicts] [err] = %s", err.Error())
 		return
 	}
+	slices.Reverse(recent)
 
-	// 去掉stockData前30条数据
-	stockData = stockData[30:]
+	// 预测按当天的价格口径保存，按请求的复权方式换算
+	if req.Adj == "" {
+		req.Adj = public.AdjQfq
+	}
+	if req.Adj != public.AdjNone && len(recent) > 0 {
+		factors, err := dao.GetStockAdjFactors(c, stockInfo.TsCode)
+		if err != nil {
+			util.FailRespWithCodeAndZap(c, util.InternalServerError, "[AccuracyStock] [GetStockAdjFactors] [err] = %s", err.Error())
+			return
+		}
+		base := 1.0
+		if req.Adj == public.AdjQfq && len(factors) > 0 {
+			base = factors[len(factors)-1].AdjFactor
+		}
+		model.AdjustStockPredicts(recent, factors, base)
+	}
 
-	var (
-		trueNum, total int
-		sumClose       float64
-	)
+	util.SuccessResp(c, &AccuracyStockResp{
+		List:    list,
+		Overall: overall,
+		Recent:  recent,
+	})
+}
 
-	last7 := make([]float64, 0, 7)
-	for i := 0; i < 7; i++ {
-		last7 = append(last7, stockData[len(stockData)-7+i].Close)
+func IndicatorsStock(c *gin.Context) {
+	var req IndicatorsStockReq
+	if err := c.ShouldBind(&req); err != nil {
+		util.FailRespWithCodeAndZap(c, util.ShouldBindJSONError, "[IndicatorsStock] [ShouldBindJSON] [err] = %s", err.Error())
+		return
 	}
 
-	for i, v := range stockData {
-		sumClose += v.Close
-		total++
-		// 相乘符号大于0，相同走势
-		if (v.Close-v.PreClose)*(predictList[i]-v.PreClose) > 0 {
-			trueNum++
-		}
+	specs, err := indicator.ParseSpecs(req.Indicators)
+	if err != nil {
+		util.FailRespWithCodeAndZap(c, util.ReqDataError, "[IndicatorsStock] [ParseIndicatorSpecs] [err] = %s", err.Error())
+		return
+	}
+	start := util.ConvertDateStrToTime(req.StartDate, time.DateOnly)
+	if start.IsZero() {
+		util.FailRespWithCodeAndZap(c, util.ReqDataError, "[IndicatorsStock] [ConvertDateStrToTime] [err] = %s", req.StartDate)
+		return
+	}
+
+	info, err := dao.GetStockInfo(c, req.Id)
+	if err != nil {
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[IndicatorsStock] [GetStockInfo] [err] = %s", err.Error())
+		return
+	}
+
+	// 向前多取数据，约 1.5 个自然日对应 1 个交易日
+	from := start.AddDate(0, 0, -(indicator.MaxLookback(specs)*3/2 + 10))
+	list, err := dao.GetStockData(c, info.TsCode, from.Format(time.DateOnly), req.EndDate)
+	if err != nil {
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[IndicatorsStock] [GetStockData] [err] = %s", err.Error())
+		return
 	}
 
-	avg := sumClose / float64(total)
-	var sRes, Stot float64
-	for i, v := range stockData {
-		sRes += math.Pow(v.Close-predictList[i], 2)
-		Stot += math.Pow(v.Close-avg, 2)
+	if err := server.AdjustStockData(c, info.TsCode, list, req.Adj); err != nil {
+		util.FailRespWithCodeAndZap(c, tushare.RespCode(err), "[IndicatorsStock] [AdjustStockData] [err] = %s", err.Error())
+		return
 	}
 
-	accuracy := float64(trueNum) / float64(total) * 100
+	dates, series := indicator.ComputeFrom(model.StockBars(list), specs, start)
 
-	util.SuccessResp(c, &AccuracyStockResp{
-		Accuracy: fmt.Sprintf("%.2f", accuracy),
-		R2:       fmt.Sprintf("%.2f", 100-(100*(sRes/Stot))),
-		List:     last7,
-		Val:      math.Floor(predictList[len(predictList)-1]*1000) / 1000,
+	util.SuccessResp(c, &IndicatorsStockResp{
+		Dates: dates,
+		List:  series,
 	})
 }
@@ -7,30 +7,22 @@ import (
 	"financia/public/db/connector"
 	"financia/public/db/dao"
 	"financia/public/db/model"
-	"financia/server/python"
+	"financia/server"
 	"fmt"
 	"github.com/go-redis/redis/v8"
 	"github.com/spf13/cast"
 	"go.uber.org/zap"
-	"golang.org/x/sync/errgroup"
-	"sort"
-	"sync"
 )
 
-func predict(c context.Context, userId int64, resp *UserInfoResp) error {
-	rdb := connector.GetRedis().WithContext(c)
-	eg, ctx := errgroup.WithContext(c)
+func predict(ctx context.Context, userId int64, resp *UserInfoResp) error {
+	rdb := connector.GetRedis().WithContext(ctx)
 	stockIdList, fundIdList, err := dao.GetFollowList(ctx, userId)
 	if err != nil {
 		zap.S().Error("[Info] [GetFollowList] [err] = ", err.Error())
 		return nil
 	}
 
-	eg.Go(func() error {
-		if len(stockIdList) == 0 {
-			return nil
-		}
-
+	if len(stockIdList) > 0 {
 		stockList, stocksToPredict, err := stockPredictKeys(rdb, stockIdList, ctx)
 		if err != nil {
 			zap.S().Error("[Info] [stockPredictKeys] [err] = ", err.Error())
@@ -38,59 +30,22 @@ func predict(c context.Context, userId int64, resp *UserInfoResp) error {
 		}
 		resp.StockList = stockList
 
-		// 并发调用 Python 预测
-		var mu sync.Mutex
-		eg2, _ := errgroup.WithContext(ctx)
-
+		// 未缓存的提交预测任务，不在请求中等待结果
 		for _, stock := range stocksToPredict {
-			stock := stock // 避免闭包问题
-			eg2.Go(func() error {
-				// 获取最近 30 天的股票数据
-				stockData, err := dao.GetStockDataLimit30(ctx, stock.TsCode)
-				if err != nil {
-					zap.S().Error("[Info] [GetStockDataLimit30] [err] = ", err.Error())
-					return err
-				}
-
-				// 排序数据，确保时间顺序正确
-				sort.Slice(stockData, func(i, j int) bool {
-					return stockData[i].TradeDate.Before(stockData[j].TradeDate)
-				})
-
-				// 调用 Python 进行预测
-				val, err := python.PythonPredictStock(stock.Id, stockData)
-				if err != nil {
-					zap.S().Error("[PredictStock] [PythonPredictStock] [err] = ", err.Error())
-					return err
-				}
-
-				// 加锁，确保多线程安全
-				mu.Lock()
-				resp.StockList = append(resp.StockList, &UserInfoData{
-					Id:      stock.Id,
-					Name:    stock.Name,
-					Val:     stockData[len(stockData)-1].Close,
-					NextVal: val,
-				})
-				mu.Unlock()
-
-				return nil
+			status, err := server.EnqueuePredict(ctx, public.AssetStock, stock.Id, stock.TsCode)
+			if err != nil {
+				zap.S().Error("[Info] [EnqueuePredict] [err] = ", err.Error())
+				continue
+			}
+			resp.StockPending = append(resp.StockPending, &UserInfoPending{
+				Id:     stock.Id,
+				Name:   stock.Name,
+				Status: status,
 			})
 		}
+	}
 
-		// 等待所有 Python 预测任务完成
-		if err := eg2.Wait(); err != nil {
-			return err
-		}
-
-		return nil
-	})
-
-	eg.Go(func() error {
-		if len(fundIdList) == 0 {
-			return nil
-		}
-
+	if len(fundIdList) > 0 {
 		fundList, fundsToPredict, err := fundPredictKeys(rdb, fundIdList, ctx)
 		if err != nil {
 			zap.S().Error("[Info] [fundPredictKeys] [err] = ", err.Error())
@@ -98,52 +53,18 @@ func predict(c context.Context, userId int64, resp *UserInfoResp) error {
 		}
 		resp.FundList = fundList
 
-		var mu sync.Mutex
-		eg2, _ := errgroup.WithContext(ctx)
-
 		for _, fund := range fundsToPredict {
-			fund := fund
-			eg2.Go(func() error {
-				fundData, err := dao.GetFundDataLimit30(ctx, fund.TsCode)
-				if err != nil {
-					zap.S().Error("[Info] [GetFundDataLimit30] [err] = ", err.Error())
-					return err
-				}
-
-				sort.Slice(fundData, func(i, j int) bool {
-					return fundData[i].TradeDate.Before(fundData[j].TradeDate)
-				})
-
-				val, err := python.PythonPredictFund(int(fund.Id), fundData)
-				if err != nil {
-					zap.S().Error("[PredictFund] [PythonPredictFund] [err] = ", err.Error())
-					return err
-				}
-
-				mu.Lock()
-				resp.FundList = append(resp.FundList, &UserInfoData{
-					Id:      int(fund.Id),
-					Name:    fund.Name,
-					Val:     fundData[len(fundData)-1].Close,
-					NextVal: val,
-				})
-				mu.Unlock()
-
-				return nil
+			status, err := server.EnqueuePredict(ctx, public.AssetFund, int(fund.Id), fund.TsCode)
+			if err != nil {
+				zap.S().Error("[Info] [EnqueuePredict] [err] = ", err.Error())
+				continue
+			}
+			resp.FundPending = append(resp.FundPending, &UserInfoPending{
+				Id:     int(fund.Id),
+				Name:   fund.Name,
+				Status: status,
 			})
 		}
-
-		if err := eg2.Wait(); err != nil {
-			return err
-		}
-
-		return nil
-	})
-
-	// 等待所有任务完成
-	if err := eg.Wait(); err != nil {
-		zap.S().Error("[Info] [err] = ", err.Error())
-		return err
 	}
 
 	return nil
@@ -185,7 +106,7 @@ func stockPredictKeys(rdb *redis.Client, stockIdList []int, ctx context.Context)
 				NextVal: cast.ToFloat64(predictResults[i]), // Redis 缓存预测值
 			})
 		} else {
-			// 需要进行 Python 预测
+			// 需要提交预测任务
 			stocksToPredict = append(stocksToPredict, v)
 		}
 	}
@@ -33,6 +33,9 @@ type UserInfoResp struct {
 	UserName  string          `json:"username"`
 	StockList []*UserInfoData `json:"stockList"`
 	FundList  []*UserInfoData `json:"fundList"`
+	// 尚未缓存预测结果、已提交预测任务的关注
+	StockPending []*UserInfoPending `json:"stockPending"`
+	FundPending  []*UserInfoPending `json:"fundPending"`
 }
 
 type UserInfoData struct {
@@ -42,6 +45,12 @@ type UserInfoData struct {
 	NextVal float64 `json:"nextVal"`
 }
 
+type UserInfoPending struct {
+	Id     int    `json:"id"`
+	Name   string `json:"name"`
+	Status string `json:"status"` // pending、running 或 failed
+}
+
 type TipResp struct {
 	Exists    bool      `json:"exists"`
 	StockRise TipSimple `json:"stockRise,omitempty"`
@@ -30,14 +30,19 @@ func Code(c *gin.Context) {
 
 	code = public.GenerateVerificationCode(6)
 
-	go server.SendEmail(req.Email, public.EmailTitle, code)
-
 	err = dao.SetEmailCode(c, req.Email, code)
 	if err != nil {
 		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[Code] [SetEmailCode] [err] = ", err.Error())
 		return
 	}
 
+	// 发送失败时删除验证码，允许立即重新获取
+	if err := server.SendCode(c, req.Email, code, dao.EmailCodeTTL); err != nil {
+		_ = dao.DelEmailCode(c, req.Email)
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[Code] [SendCode] [err] = ", err.Error())
+		return
+	}
+
 	util.SuccessResp(c, nil)
 }
 
@@ -0,0 +1,59 @@
+package watchlist
+
+import "financia/public/db/model"
+
+type ListWatchlistResp struct {
+	List []*WatchlistSimple `json:"list"`
+}
+
+type WatchlistSimple struct {
+	*model.Watchlist
+	Items []*model.WatchlistItem `json:"items"`
+}
+
+type CreateWatchlistReq struct {
+	Name string `form:"name" binding:"required,max=50"`
+}
+
+type CreateWatchlistResp struct {
+	Id int64 `json:"id"`
+}
+
+type RenameWatchlistReq struct {
+	Id   int64  `form:"id" binding:"required"`
+	Name string `form:"name" binding:"required,max=50"`
+}
+
+type DeleteWatchlistReq struct {
+	Id int64 `form:"id" binding:"required"`
+}
+
+type SortWatchlistReq struct {
+	Ids []int64 `form:"ids" binding:"required"` // 列表 ID，按新的顺序排列
+}
+
+type AddItemReq struct {
+	WatchlistId int64  `form:"watchlistId" binding:"required"`
+	Asset       string `form:"asset" binding:"required,oneof=stock fund fut"`
+	Id          int    `form:"id" binding:"required_unless=Asset fut"` // 股票、基金 ID
+	Prd         string `form:"prd" binding:"required_if=Asset fut"`    // 期货品种代码
+	Note        string `form:"note" binding:"max=500"`
+}
+
+type AddItemResp struct {
+	Item *model.WatchlistItem `json:"item"`
+}
+
+type UpdateItemReq struct {
+	Id   int64  `form:"id" binding:"required"`
+	Note string `form:"note" binding:"max=500"`
+}
+
+type DeleteItemReq struct {
+	Id int64 `form:"id" binding:"required"`
+}
+
+type SortItemReq struct {
+	WatchlistId int64   `form:"watchlistId" binding:"required"`
+	Ids         []int64 `form:"ids" binding:"required"` // 条目 ID，按新的顺序排列
+}
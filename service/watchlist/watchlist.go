@@ -0,0 +1,235 @@
+package watchlist
+
+import (
+	"errors"
+	"financia/public/db/dao"
+	"financia/public/db/model"
+	"financia/server"
+	"financia/util"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func ListWatchlist(c *gin.Context) {
+	userId := util.GetUid(c)
+	lists, err := dao.ListWatchlists(c, userId)
+	if err != nil {
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[ListWatchlist] [ListWatchlists] [err] = %s", err.Error())
+		return
+	}
+	items, err := dao.ListWatchlistItems(c, userId)
+	if err != nil {
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[ListWatchlist] [ListWatchlistItems] [err] = %s", err.Error())
+		return
+	}
+
+	itemMap := make(map[int64][]*model.WatchlistItem, len(lists))
+	for _, v := range items {
+		itemMap[v.WatchlistId] = append(itemMap[v.WatchlistId], v)
+	}
+	respList := make([]*WatchlistSimple, 0, len(lists))
+	for _, v := range lists {
+		list := itemMap[v.Id]
+		if list == nil {
+			list = make([]*model.WatchlistItem, 0)
+		}
+		respList = append(respList, &WatchlistSimple{Watchlist: v, Items: list})
+	}
+
+	util.SuccessResp(c, &ListWatchlistResp{
+		List: respList,
+	})
+}
+
+func CreateWatchlist(c *gin.Context) {
+	var req CreateWatchlistReq
+	if err := c.ShouldBind(&req); err != nil {
+		util.FailRespWithCodeAndZap(c, util.ShouldBindJSONError, "[CreateWatchlist] [ShouldBindJSON] [err] = %s", err.Error())
+		return
+	}
+
+	userId := util.GetUid(c)
+	exists, err := dao.WatchlistNameExists(c, userId, req.Name, 0)
+	if err != nil {
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[CreateWatchlist] [WatchlistNameExists] [err] = %s", err.Error())
+		return
+	}
+	if exists {
+		util.FailRespWithCodeAndZap(c, util.ReqDataError, "[CreateWatchlist] [WatchlistNameExists] [err] = %s", req.Name+" exists")
+		return
+	}
+
+	watchlist := &model.Watchlist{UserId: userId, Name: req.Name}
+	if err := dao.CreateWatchlist(c, watchlist); err != nil {
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[CreateWatchlist] [CreateWatchlist] [err] = %s", err.Error())
+		return
+	}
+
+	util.SuccessResp(c, &CreateWatchlistResp{
+		Id: watchlist.Id,
+	})
+}
+
+func RenameWatchlist(c *gin.Context) {
+	var req RenameWatchlistReq
+	if err := c.ShouldBind(&req); err != nil {
+		util.FailRespWithCodeAndZap(c, util.ShouldBindJSONError, "[RenameWatchlist] [ShouldBindJSON] [err] = %s", err.Error())
+		return
+	}
+
+	userId := util.GetUid(c)
+	exists, err := dao.WatchlistNameExists(c, userId, req.Name, req.Id)
+	if err != nil {
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[RenameWatchlist] [WatchlistNameExists] [err] = %s", err.Error())
+		return
+	}
+	if exists {
+		util.FailRespWithCodeAndZap(c, util.ReqDataError, "[RenameWatchlist] [WatchlistNameExists] [err] = %s", req.Name+" exists")
+		return
+	}
+
+	found, err := dao.RenameWatchlist(c, userId, req.Id, req.Name)
+	if err != nil {
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[RenameWatchlist] [RenameWatchlist] [err] = %s", err.Error())
+		return
+	}
+	if !found {
+		util.FailRespWithCode(c, util.DataEmptyError)
+		return
+	}
+
+	util.SuccessResp(c, nil)
+}
+
+func DeleteWatchlist(c *gin.Context) {
+	var req DeleteWatchlistReq
+	if err := c.ShouldBind(&req); err != nil {
+		util.FailRespWithCodeAndZap(c, util.ShouldBindJSONError, "[DeleteWatchlist] [ShouldBindJSON] [err] = %s", err.Error())
+		return
+	}
+
+	deleted, err := dao.DeleteWatchlist(c, util.GetUid(c), req.Id)
+	if err != nil {
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[DeleteWatchlist] [DeleteWatchlist] [err] = %s", err.Error())
+		return
+	}
+	if !deleted {
+		util.FailRespWithCode(c, util.DataEmptyError)
+		return
+	}
+
+	util.SuccessResp(c, nil)
+}
+
+func SortWatchlist(c *gin.Context) {
+	var req SortWatchlistReq
+	if err := c.ShouldBind(&req); err != nil {
+		util.FailRespWithCodeAndZap(c, util.ShouldBindJSONError, "[SortWatchlist] [ShouldBindJSON] [err] = %s", err.Error())
+		return
+	}
+
+	if err := dao.SortWatchlists(c, util.GetUid(c), req.Ids); err != nil {
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[SortWatchlist] [SortWatchlists] [err] = %s", err.Error())
+		return
+	}
+
+	util.SuccessResp(c, nil)
+}
+
+func AddItem(c *gin.Context) {
+	var req AddItemReq
+	if err := c.ShouldBind(&req); err != nil {
+		util.FailRespWithCodeAndZap(c, util.ShouldBindJSONError, "[AddItem] [ShouldBindJSON] [err] = %s", err.Error())
+		return
+	}
+
+	userId := util.GetUid(c)
+	watchlist, err := dao.GetWatchlist(c, userId, req.WatchlistId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		util.FailRespWithCode(c, util.DataEmptyError)
+		return
+	}
+	if err != nil {
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[AddItem] [GetWatchlist] [err] = %s", err.Error())
+		return
+	}
+
+	item, err := server.NewWatchlistItem(c, req.Asset, req.Id, req.Prd)
+	if errors.Is(err, server.ErrInvalidItem) {
+		util.FailRespWithCodeAndZap(c, util.ReqDataError, "[AddItem] [NewWatchlistItem] [err] = %s", err.Error())
+		return
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		util.FailRespWithCode(c, util.DataEmptyError)
+		return
+	}
+	if err != nil {
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[AddItem] [NewWatchlistItem] [err] = %s", err.Error())
+		return
+	}
+
+	item.WatchlistId, item.UserId, item.Note = watchlist.Id, userId, req.Note
+	if err := dao.AddWatchlistItem(c, item); err != nil {
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[AddItem] [AddWatchlistItem] [err] = %s", err.Error())
+		return
+	}
+
+	util.SuccessResp(c, &AddItemResp{
+		Item: item,
+	})
+}
+
+func UpdateItem(c *gin.Context) {
+	var req UpdateItemReq
+	if err := c.ShouldBind(&req); err != nil {
+		util.FailRespWithCodeAndZap(c, util.ShouldBindJSONError, "[UpdateItem] [ShouldBindJSON] [err] = %s", err.Error())
+		return
+	}
+
+	found, err := dao.UpdateWatchlistItemNote(c, util.GetUid(c), req.Id, req.Note)
+	if err != nil {
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[UpdateItem] [UpdateWatchlistItemNote] [err] = %s", err.Error())
+		return
+	}
+	if !found {
+		util.FailRespWithCode(c, util.DataEmptyError)
+		return
+	}
+
+	util.SuccessResp(c, nil)
+}
+
+func DeleteItem(c *gin.Context) {
+	var req DeleteItemReq
+	if err := c.ShouldBind(&req); err != nil {
+		util.FailRespWithCodeAndZap(c, util.ShouldBindJSONError, "[DeleteItem] [ShouldBindJSON] [err] = %s", err.Error())
+		return
+	}
+
+	deleted, err := dao.DeleteWatchlistItem(c, util.GetUid(c), req.Id)
+	if err != nil {
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[DeleteItem] [DeleteWatchlistItem] [err] = %s", err.Error())
+		return
+	}
+	if !deleted {
+		util.FailRespWithCode(c, util.DataEmptyError)
+		return
+	}
+
+	util.SuccessResp(c, nil)
+}
+
+func SortItem(c *gin.Context) {
+	var req SortItemReq
+	if err := c.ShouldBind(&req); err != nil {
+		util.FailRespWithCodeAndZap(c, util.ShouldBindJSONError, "[SortItem] [ShouldBindJSON] [err] = %s", err.Error())
+		return
+	}
+
+	if err := dao.SortWatchlistItems(c, util.GetUid(c), req.WatchlistId, req.Ids); err != nil {
+		util.FailRespWithCodeAndZap(c, util.InternalServerError, "[SortItem] [SortWatchlistItems] [err] = %s", err.Error())
+		return
+	}
+
+	util.SuccessResp(c, nil)
+}
@@ -0,0 +1,115 @@
+package util
+
+import (
+	"financia/public"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Bar 通用 K 线
+type Bar struct {
+	Date     time.Time
+	Open     float64
+	High     float64
+	Low      float64
+	Close    float64
+	PreClose float64
+	Change   float64
+	PctChg   float64
+	Vol      float64
+	Amount   float64
+}
+
+// periodKey 日期所属的自然周期，周按周一至周日划分
+func periodKey(t time.Time, period string) string {
+	switch period {
+	case public.PeriodWeek:
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	case public.PeriodMonth:
+		return t.Format("2006-01")
+	case public.PeriodQuarter:
+		return fmt.Sprintf("%d-Q%d", t.Year(), (int(t.Month())-1)/3+1)
+	default:
+		return t.Format(time.DateOnly)
+	}
+}
+
+// Calendar 交易所交易日历，开市日期升序
+type Calendar []time.Time
+
+// PeriodEnd 日期所在周期按交易日历的最后一个交易日
+// 周期由同一自然周（月、季）内的开市日组成，日期不是日历中的开市日时返回 false
+func (cal Calendar) PeriodEnd(t time.Time, period string) (time.Time, bool) {
+	day := t.Format(time.DateOnly)
+	i := sort.Search(len(cal), func(i int) bool {
+		return cal[i].Format(time.DateOnly) >= day
+	})
+	if i == len(cal) || cal[i].Format(time.DateOnly) != day {
+		return time.Time{}, false
+	}
+
+	key := periodKey(cal[i], period)
+	for i+1 < len(cal) && periodKey(cal[i+1], period) == key {
+		i++
+	}
+	return cal[i], true
+}
+
+// Closed 日期是否为所在周期的最后一个交易日，即该周期已收盘完整
+func (cal Calendar) Closed(t time.Time, period string) bool {
+	end, ok := cal.PeriodEnd(t, period)
+	return ok && end.Format(time.DateOnly) == t.Format(time.DateOnly)
+}
+
+// AggregateBars 将按日期升序的日 K 线按交易日历聚合为周、月、季 K 线
+// 日期取周期内最后一个交易日，昨收取上一周期收盘价（第一根取周期内首日昨收）
+// 不在日历中的日期（日历缺失或范围不足）按自然周期归组
+func AggregateBars(bars []*Bar, period string, cal Calendar) []*Bar {
+	if period == "" || period == public.PeriodDay {
+		return bars
+	}
+
+	list := make([]*Bar, 0)
+	var (
+		cur *Bar
+		key string
+	)
+	for _, v := range bars {
+		k := periodKey(v.Date, period)
+		if end, ok := cal.PeriodEnd(v.Date, period); ok {
+			k = end.Format(time.DateOnly)
+		}
+		if cur == nil || k != key {
+			preClose := v.PreClose
+			if cur != nil {
+				preClose = cur.Close
+			}
+			cur = &Bar{
+				Open:     v.Open,
+				High:     v.High,
+				Low:      v.Low,
+				PreClose: preClose,
+			}
+			key = k
+			list = append(list, cur)
+		}
+
+		cur.Date = v.Date
+		cur.Close = v.Close
+		cur.High = max(cur.High, v.High)
+		cur.Low = min(cur.Low, v.Low)
+		cur.Vol += v.Vol
+		cur.Amount += v.Amount
+	}
+
+	for _, v := range list {
+		v.Change = v.Close - v.PreClose
+		if v.PreClose != 0 {
+			v.PctChg = v.Change / v.PreClose * 100
+		}
+	}
+
+	return list
+}
@@ -0,0 +1,78 @@
+package util
+
+import (
+	"financia/public"
+	"testing"
+	"time"
+)
+
+func Test_AggregateBars(t *testing.T) {
+	day := func(s string, open, high, low, close, vol float64) *Bar {
+		d, _ := time.Parse(time.DateOnly, s)
+		return &Bar{Date: d, Open: open, High: high, Low: low, Close: close, PreClose: open, Vol: vol, Amount: vol * close}
+	}
+	// 2024-03-29 周五，2024-04-01 周一
+	bars := []*Bar{
+		day("2024-03-28", 10, 11, 9, 10.5, 100),
+		day("2024-03-29", 10.5, 12, 10, 11, 200),
+		day("2024-04-01", 11, 13, 10.5, 12, 300),
+		day("2024-04-02", 12, 12.5, 8, 9, 400),
+	}
+
+	week := AggregateBars(bars, public.PeriodWeek, nil)
+	if len(week) != 2 {
+		t.Fatalf("week bars = %d, want 2", len(week))
+	}
+	if w := week[0]; w.Open != 10 || w.High != 12 || w.Low != 9 || w.Close != 11 || w.Vol != 300 || w.Date.Format(time.DateOnly) != "2024-03-29" {
+		t.Errorf("week[0] = %+v", w)
+	}
+	if w := week[1]; w.PreClose != 11 || w.Change != -2 || w.Low != 8 {
+		t.Errorf("week[1] = %+v", w)
+	}
+
+	if month := AggregateBars(bars, public.PeriodMonth, nil); len(month) != 2 {
+		t.Errorf("month bars = %d, want 2", len(month))
+	}
+	quarter := AggregateBars(bars, public.PeriodQuarter, nil)
+	if len(quarter) != 2 || quarter[1].Open != 11 || quarter[1].Close != 9 {
+		t.Errorf("quarter = %+v", quarter)
+	}
+	if got := AggregateBars(bars, public.PeriodDay, nil); len(got) != len(bars) {
+		t.Errorf("day bars = %d, want %d", len(got), len(bars))
+	}
+}
+
+func Test_CalendarPeriods(t *testing.T) {
+	date := func(s string) time.Time {
+		d, _ := time.Parse(time.DateOnly, s)
+		return d
+	}
+	// 2024-10-01 至 10-07 国庆休市，9 月 30 日周一为该周唯一交易日
+	cal := Calendar{date("2024-09-26"), date("2024-09-27"), date("2024-09-30"), date("2024-10-08"), date("2024-10-09")}
+
+	if end, ok := cal.PeriodEnd(date("2024-09-30"), public.PeriodWeek); !ok || !end.Equal(date("2024-09-30")) {
+		t.Errorf("week end of 09-30 = %v, %v", end, ok)
+	}
+	if !cal.Closed(date("2024-09-30"), public.PeriodWeek) || !cal.Closed(date("2024-09-30"), public.PeriodMonth) {
+		t.Error("09-30 should close its week and month")
+	}
+	if cal.Closed(date("2024-10-08"), public.PeriodWeek) {
+		t.Error("10-08 should not close its week")
+	}
+	if _, ok := cal.PeriodEnd(date("2024-10-01"), public.PeriodWeek); ok {
+		t.Error("holiday should not be in calendar")
+	}
+
+	// 时区不同的同一交易日归入同一周期
+	loc := time.FixedZone("CST", 8*3600)
+	bars := []*Bar{
+		{Date: date("2024-09-26"), Open: 1, High: 1, Low: 1, Close: 1},
+		{Date: time.Date(2024, 9, 27, 0, 0, 0, 0, loc), Open: 2, High: 2, Low: 2, Close: 2},
+		{Date: date("2024-09-30"), Open: 3, High: 3, Low: 3, Close: 3},
+		{Date: date("2024-10-08"), Open: 4, High: 4, Low: 4, Close: 4},
+	}
+	week := AggregateBars(bars, public.PeriodWeek, cal)
+	if len(week) != 3 || week[0].Close != 2 || week[1].Close != 3 || week[2].PreClose != 3 {
+		t.Errorf("week = %+v", week)
+	}
+}
@@ -0,0 +1,48 @@
+package indicator
+
+import "math"
+
+// ATRStream 流式平均真实波幅，以前 period 个真实波幅的均值为初值，之后 Wilder 平滑
+// 第一根 K 线没有昨收，真实波幅取最高价减最低价
+type ATRStream struct {
+	period    int
+	prevClose float64
+	started   bool
+	count     int
+	value     float64
+}
+
+func NewATRStream(period int) *ATRStream {
+	return &ATRStream{period: clampPeriod(period)}
+}
+
+func (s *ATRStream) Next(high, low, close float64) float64 {
+	tr := high - low
+	if s.started {
+		tr = math.Max(tr, math.Max(math.Abs(high-s.prevClose), math.Abs(low-s.prevClose)))
+	}
+	s.prevClose, s.started = close, true
+
+	s.count++
+	p := float64(s.period)
+	switch {
+	case s.count < s.period:
+		s.value += tr
+		return NaN
+	case s.count == s.period:
+		s.value = (s.value + tr) / p
+	default:
+		s.value = (s.value*(p-1) + tr) / p
+	}
+	return s.value
+}
+
+// ATR 平均真实波幅，前 period-1 个为 NaN
+func ATR(high, low, close []float64, period int) []float64 {
+	s := NewATRStream(period)
+	out := make([]float64, len(close))
+	for i := range close {
+		out[i] = s.Next(high[i], low[i], close[i])
+	}
+	return out
+}
@@ -0,0 +1,49 @@
+package indicator
+
+import "math"
+
+// BOLLStream 流式布林带
+type BOLLStream struct {
+	period int
+	k      float64
+	window *ring
+	sum    float64
+}
+
+func NewBOLLStream(period int, k float64) *BOLLStream {
+	period = clampPeriod(period)
+	return &BOLLStream{period: period, k: k, window: newRing(period)}
+}
+
+// Next 返回中轨、上轨、下轨
+func (s *BOLLStream) Next(price float64) (float64, float64, float64) {
+	old, evicted := s.window.push(price)
+	s.sum += price
+	if evicted {
+		s.sum -= old
+	}
+	if !s.window.full {
+		return NaN, NaN, NaN
+	}
+
+	mid := s.sum / float64(s.period)
+	// 总体标准差
+	variance := 0.0
+	s.window.each(func(_ int, v float64) {
+		variance += (v - mid) * (v - mid)
+	})
+	std := math.Sqrt(variance / float64(s.period))
+	return mid, mid + s.k*std, mid - s.k*std
+}
+
+// BOLL 布林带，返回中轨、上轨、下轨，前 period-1 个为 NaN
+func BOLL(prices []float64, period int, k float64) ([]float64, []float64, []float64) {
+	s := NewBOLLStream(period, k)
+	mid := make([]float64, len(prices))
+	upper := make([]float64, len(prices))
+	lower := make([]float64, len(prices))
+	for i, p := range prices {
+		mid[i], upper[i], lower[i] = s.Next(p)
+	}
+	return mid, upper, lower
+}
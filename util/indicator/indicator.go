@@ -0,0 +1,63 @@
+// Package indicator 技术指标
+//
+// 约定：
+//   - 批量函数返回的每条线与输入等长，按下标与输入的 K 线对齐
+//   - 预热期（数据不足以计算）的位置为 NaN，不做截断
+//   - 每个指标都有对应的流式版本（XxxStream），逐根追加 K 线时与批量结果一致
+//   - 周期小于 1 时按 1 处理
+package indicator
+
+import "math"
+
+// NaN 预热期的占位值
+var NaN = math.NaN()
+
+// Trim 去掉开头预热期的 NaN
+func Trim(line []float64) []float64 {
+	for i, v := range line {
+		if !math.IsNaN(v) {
+			return line[i:]
+		}
+	}
+	return nil
+}
+
+// ring 固定长度的滑动窗口
+type ring struct {
+	buf  []float64
+	next int
+	full bool
+}
+
+func newRing(size int) *ring {
+	return &ring{buf: make([]float64, size)}
+}
+
+// push 写入新值，窗口已满时返回被挤出的旧值
+func (r *ring) push(v float64) (old float64, evicted bool) {
+	old, evicted = r.buf[r.next], r.full
+	r.buf[r.next] = v
+	r.next++
+	if r.next == len(r.buf) {
+		r.next = 0
+		r.full = true
+	}
+	return old, evicted
+}
+
+// each 按时间顺序遍历窗口内的值
+func (r *ring) each(f func(i int, v float64)) {
+	if !r.full {
+		for i := 0; i < r.next; i++ {
+			f(i, r.buf[i])
+		}
+		return
+	}
+	for i := 0; i < len(r.buf); i++ {
+		f(i, r.buf[(r.next+i)%len(r.buf)])
+	}
+}
+
+func clampPeriod(period int) int {
+	return max(period, 1)
+}
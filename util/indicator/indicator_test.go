@@ -0,0 +1,186 @@
+package indicator
+
+import (
+	"math"
+	"testing"
+)
+
+// StockCharts ChartSchool 移动平均线示例中的 30 个收盘价
+var maPrices = []float64{
+	22.27, 22.19, 22.08, 22.17, 22.18, 22.13, 22.23, 22.43, 22.24, 22.29,
+	22.15, 22.39, 22.38, 22.61, 23.36, 24.05, 23.75, 23.83, 23.95, 23.63,
+	23.82, 23.87, 23.65, 23.19, 23.10, 23.33, 22.68, 23.10, 22.40, 22.17,
+}
+
+// StockCharts ChartSchool RSI 示例中的 33 个收盘价
+var rsiPrices = []float64{
+	44.3389, 44.0902, 44.1497, 43.6124, 44.3278, 44.8264, 45.0955, 45.4245, 45.8433, 46.0826,
+	45.8931, 46.0328, 45.6140, 46.2820, 46.2820, 46.0028, 46.0328, 46.4116, 46.2222, 45.6439,
+	46.2122, 46.2521, 45.7137, 46.4515, 45.7835, 45.3548, 44.0288, 44.1783, 44.2181, 44.5672,
+	43.4205, 42.6628, 43.1314,
+}
+
+// assertGolden 比较预热期之后的数值，参考数据保留两位小数
+func assertGolden(t *testing.T, name string, got []float64, warmup int, want []float64) {
+	t.Helper()
+	if len(got) != warmup+len(want) {
+		t.Fatalf("%s: len = %d, want %d", name, len(got), warmup+len(want))
+	}
+	for i := 0; i < warmup; i++ {
+		if !math.IsNaN(got[i]) {
+			t.Errorf("%s[%d] = %v, want NaN", name, i, got[i])
+		}
+	}
+	for i, w := range want {
+		if math.Abs(got[warmup+i]-w) > 0.006 {
+			t.Errorf("%s[%d] = %.4f, want %.2f", name, warmup+i, got[warmup+i], w)
+		}
+	}
+}
+
+func TestSMAGolden(t *testing.T) {
+	assertGolden(t, "sma10", SMA(maPrices, 10), 9, []float64{
+		22.22, 22.21, 22.23, 22.26, 22.30, 22.42, 22.61, 22.77, 22.91, 23.08, 23.21,
+		23.38, 23.52, 23.65, 23.71, 23.68, 23.61, 23.51, 23.43, 23.28, 23.13,
+	})
+}
+
+func TestEMAGolden(t *testing.T) {
+	assertGolden(t, "ema10", EMA(maPrices, 10), 9, []float64{
+		22.22, 22.21, 22.24, 22.27, 22.33, 22.52, 22.80, 22.97, 23.13, 23.28, 23.34,
+		23.43, 23.51, 23.53, 23.47, 23.40, 23.39, 23.26, 23.23, 23.08, 22.92,
+	})
+}
+
+func TestRSIGolden(t *testing.T) {
+	assertGolden(t, "rsi14", RSI(rsiPrices, 14), 14, []float64{
+		70.53, 66.32, 66.55, 69.41, 66.36, 57.97, 62.93, 63.26, 56.06, 62.38,
+		54.71, 50.42, 39.99, 41.46, 41.87, 45.46, 37.30, 33.08, 37.77,
+	})
+}
+
+func TestRSIEdgeCases(t *testing.T) {
+	// 数据刚好等于周期时只有预热期，不越界
+	for _, v := range RSI([]float64{1, 2, 3}, 3) {
+		if !math.IsNaN(v) {
+			t.Errorf("rsi = %v, want NaN", v)
+		}
+	}
+
+	if got := RSI([]float64{1, 2, 3, 4}, 3)[3]; got != 100 {
+		t.Errorf("rsi without losses = %v, want 100", got)
+	}
+	if got := RSI([]float64{5, 5, 5, 5}, 3)[3]; got != 50 {
+		t.Errorf("rsi of flat prices = %v, want 50", got)
+	}
+}
+
+func TestMACDAligned(t *testing.T) {
+	dif, dea, hist := MACD(maPrices, 12, 26, 9)
+	if len(dif) != len(maPrices) || len(dea) != len(maPrices) || len(hist) != len(maPrices) {
+		t.Fatalf("len = %d %d %d, want %d", len(dif), len(dea), len(hist), len(maPrices))
+	}
+	if !math.IsNaN(dif[24]) || math.IsNaN(dif[25]) {
+		t.Errorf("dif warm-up ends at %v %v, want index 25", dif[24], dif[25])
+	}
+	// DEA 需要 9 个 DIF，30 个价格不足
+	if !math.IsNaN(dea[29]) {
+		t.Errorf("dea[29] = %v, want NaN", dea[29])
+	}
+
+	ema12, ema26 := EMA(maPrices, 12), EMA(maPrices, 26)
+	if math.Abs(dif[29]-(ema12[29]-ema26[29])) > 1e-12 {
+		t.Errorf("dif[29] = %v, want %v", dif[29], ema12[29]-ema26[29])
+	}
+}
+
+func TestKDJATRVWAP(t *testing.T) {
+	high := []float64{10, 11, 12, 13}
+	low := []float64{8, 9, 10, 11}
+	close := []float64{9, 10, 11, 12}
+	vol := []float64{100, 200, 300, 400}
+
+	k, d, j := KDJ(high, low, close, 3, 3, 3)
+	// RSV = (11-8)/(12-8)*100 = 75, K = (2*50+75)/3, D = (2*50+K)/3
+	wantK := (100 + 75.0) / 3
+	wantD := (100 + wantK) / 3
+	if !math.IsNaN(k[1]) || math.Abs(k[2]-wantK) > 1e-9 || math.Abs(d[2]-wantD) > 1e-9 || math.Abs(j[2]-(3*wantK-2*wantD)) > 1e-9 {
+		t.Errorf("kdj = %v %v %v", k, d, j)
+	}
+
+	// 真实波幅均为 2
+	if atr := ATR(high, low, close, 2); !math.IsNaN(atr[0]) || atr[1] != 2 || atr[3] != 2 {
+		t.Errorf("atr = %v", atr)
+	}
+
+	vwap := VWAP(high, low, close, vol, 0)
+	if vwap[0] != 9 || math.Abs(vwap[1]-(900+2000)/300.0) > 1e-9 {
+		t.Errorf("vwap = %v", vwap)
+	}
+	if rolling := VWAP(high, low, close, vol, 2); !math.IsNaN(rolling[0]) || math.Abs(rolling[3]-(3300+4800)/700.0) > 1e-9 {
+		t.Errorf("rolling vwap = %v", rolling)
+	}
+
+	if obv := OBV(close, vol); obv[0] != 0 || obv[3] != 900 {
+		t.Errorf("obv = %v", obv)
+	}
+}
+
+// 流式版本逐根追加的结果与批量计算一致
+func TestStreamMatchesBatch(t *testing.T) {
+	n := len(rsiPrices)
+	high := make([]float64, n)
+	low := make([]float64, n)
+	vol := make([]float64, n)
+	for i, p := range rsiPrices {
+		high[i], low[i], vol[i] = p+0.5, p-0.5, float64(100+i)
+	}
+
+	equal := func(a, b float64) bool {
+		return (math.IsNaN(a) && math.IsNaN(b)) || math.Abs(a-b) < 1e-9
+	}
+	check := func(name string, batch []float64, next func(i int) float64) {
+		t.Helper()
+		for i := range batch {
+			if got := next(i); !equal(got, batch[i]) {
+				t.Errorf("%s[%d] stream = %v, batch = %v", name, i, got, batch[i])
+			}
+		}
+	}
+
+	sma, ema, wma, rsi := NewSMAStream(5), NewEMAStream(5), NewWMAStream(5), NewRSIStream(14)
+	check("sma", SMA(rsiPrices, 5), func(i int) float64 { return sma.Next(rsiPrices[i]) })
+	check("ema", EMA(rsiPrices, 5), func(i int) float64 { return ema.Next(rsiPrices[i]) })
+	check("wma", WMA(rsiPrices, 5), func(i int) float64 { return wma.Next(rsiPrices[i]) })
+	check("rsi", RSI(rsiPrices, 14), func(i int) float64 { return rsi.Next(rsiPrices[i]) })
+
+	macd := NewMACDStream(5, 10, 4)
+	_, dea, _ := MACD(rsiPrices, 5, 10, 4)
+	check("macd", dea, func(i int) float64 { _, v, _ := macd.Next(rsiPrices[i]); return v })
+
+	boll := NewBOLLStream(10, 2)
+	_, upper, _ := BOLL(rsiPrices, 10, 2)
+	check("boll", upper, func(i int) float64 { _, v, _ := boll.Next(rsiPrices[i]); return v })
+
+	kdj := NewKDJStream(9, 3, 3)
+	_, _, j := KDJ(high, low, rsiPrices, 9, 3, 3)
+	check("kdj", j, func(i int) float64 { _, _, v := kdj.Next(high[i], low[i], rsiPrices[i]); return v })
+
+	atr := NewATRStream(14)
+	check("atr", ATR(high, low, rsiPrices, 14), func(i int) float64 { return atr.Next(high[i], low[i], rsiPrices[i]) })
+
+	obv := NewOBVStream()
+	check("obv", OBV(rsiPrices, vol), func(i int) float64 { return obv.Next(rsiPrices[i], vol[i]) })
+
+	vwap := NewVWAPStream(7)
+	check("vwap", VWAP(high, low, rsiPrices, vol, 7), func(i int) float64 { return vwap.Next(high[i], low[i], rsiPrices[i], vol[i]) })
+}
+
+func TestTrim(t *testing.T) {
+	if got := Trim(SMA([]float64{1, 2, 3, 4}, 3)); len(got) != 2 || got[0] != 2 {
+		t.Errorf("trim = %v", got)
+	}
+	if got := Trim(SMA([]float64{1}, 3)); got != nil {
+		t.Errorf("trim = %v, want nil", got)
+	}
+}
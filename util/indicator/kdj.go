@@ -0,0 +1,56 @@
+package indicator
+
+import "math"
+
+// KDJStream 流式随机指标，K、D 初始值为 50
+type KDJStream struct {
+	m1, m2 int
+	highs  *ring
+	lows   *ring
+	k, d   float64
+}
+
+func NewKDJStream(period, m1, m2 int) *KDJStream {
+	period = clampPeriod(period)
+	return &KDJStream{
+		m1:    clampPeriod(m1),
+		m2:    clampPeriod(m2),
+		highs: newRing(period),
+		lows:  newRing(period),
+		k:     50,
+		d:     50,
+	}
+}
+
+// Next 返回 K、D、J
+func (s *KDJStream) Next(high, low, close float64) (float64, float64, float64) {
+	s.highs.push(high)
+	s.lows.push(low)
+	if !s.highs.full {
+		return NaN, NaN, NaN
+	}
+
+	hh, ll := math.Inf(-1), math.Inf(1)
+	s.highs.each(func(_ int, v float64) { hh = math.Max(hh, v) })
+	s.lows.each(func(_ int, v float64) { ll = math.Min(ll, v) })
+
+	rsv := 50.0
+	if hh != ll {
+		rsv = (close - ll) / (hh - ll) * 100
+	}
+	s.k = (float64(s.m1-1)*s.k + rsv) / float64(s.m1)
+	s.d = (float64(s.m2-1)*s.d + s.k) / float64(s.m2)
+	return s.k, s.d, 3*s.k - 2*s.d
+}
+
+// KDJ 随机指标，前 period-1 个为 NaN
+func KDJ(high, low, close []float64, period, m1, m2 int) ([]float64, []float64, []float64) {
+	s := NewKDJStream(period, m1, m2)
+	k := make([]float64, len(close))
+	d := make([]float64, len(close))
+	j := make([]float64, len(close))
+	for i := range close {
+		k[i], d[i], j[i] = s.Next(high[i], low[i], close[i])
+	}
+	return k, d, j
+}
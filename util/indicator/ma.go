@@ -0,0 +1,114 @@
+package indicator
+
+import "math"
+
+// SMAStream 流式简单移动平均
+type SMAStream struct {
+	period int
+	window *ring
+	sum    float64
+}
+
+func NewSMAStream(period int) *SMAStream {
+	period = clampPeriod(period)
+	return &SMAStream{period: period, window: newRing(period)}
+}
+
+// Next 追加一个价格，返回最新的均值
+func (s *SMAStream) Next(price float64) float64 {
+	old, evicted := s.window.push(price)
+	s.sum += price
+	if evicted {
+		s.sum -= old
+	}
+	if !s.window.full {
+		return NaN
+	}
+	return s.sum / float64(s.period)
+}
+
+// SMA 简单移动平均线，前 period-1 个为 NaN
+func SMA(prices []float64, period int) []float64 {
+	s := NewSMAStream(period)
+	out := make([]float64, len(prices))
+	for i, p := range prices {
+		out[i] = s.Next(p)
+	}
+	return out
+}
+
+// EMAStream 流式指数移动平均，以前 period 个值的 SMA 作为初值
+// 输入为 NaN 时跳过，不改变状态
+type EMAStream struct {
+	period int
+	alpha  float64
+	count  int
+	value  float64
+}
+
+func NewEMAStream(period int) *EMAStream {
+	period = clampPeriod(period)
+	return &EMAStream{period: period, alpha: 2.0 / (float64(period) + 1.0)}
+}
+
+func (s *EMAStream) Next(price float64) float64 {
+	if math.IsNaN(price) {
+		return NaN
+	}
+
+	s.count++
+	switch {
+	case s.count < s.period:
+		s.value += price
+		return NaN
+	case s.count == s.period:
+		s.value = (s.value + price) / float64(s.period)
+	default:
+		s.value = s.alpha*price + (1-s.alpha)*s.value
+	}
+	return s.value
+}
+
+// EMA 指数移动平均线，前 period-1 个为 NaN
+func EMA(prices []float64, period int) []float64 {
+	s := NewEMAStream(period)
+	out := make([]float64, len(prices))
+	for i, p := range prices {
+		out[i] = s.Next(p)
+	}
+	return out
+}
+
+// WMAStream 流式加权移动平均，越新的价格权重越大
+type WMAStream struct {
+	period int
+	window *ring
+}
+
+func NewWMAStream(period int) *WMAStream {
+	period = clampPeriod(period)
+	return &WMAStream{period: period, window: newRing(period)}
+}
+
+func (s *WMAStream) Next(price float64) float64 {
+	s.window.push(price)
+	if !s.window.full {
+		return NaN
+	}
+
+	weighted := 0.0
+	s.window.each(func(i int, v float64) {
+		weighted += v * float64(i+1)
+	})
+	return weighted / float64(s.period*(s.period+1)/2)
+}
+
+// WMA 加权移动平均线，前 period-1 个为 NaN
+func WMA(prices []float64, period int) []float64 {
+	s := NewWMAStream(period)
+	out := make([]float64, len(prices))
+	for i, p := range prices {
+		out[i] = s.Next(p)
+	}
+	return out
+}
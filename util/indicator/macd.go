@@ -0,0 +1,42 @@
+package indicator
+
+import "math"
+
+// MACDStream 流式 MACD
+type MACDStream struct {
+	fast, slow, signal *EMAStream
+}
+
+func NewMACDStream(fast, slow, signal int) *MACDStream {
+	return &MACDStream{
+		fast:   NewEMAStream(fast),
+		slow:   NewEMAStream(slow),
+		signal: NewEMAStream(signal),
+	}
+}
+
+// Next 返回 DIF、DEA 与柱（DIF-DEA）
+func (s *MACDStream) Next(price float64) (float64, float64, float64) {
+	fast := s.fast.Next(price)
+	slow := s.slow.Next(price)
+	if math.IsNaN(fast) || math.IsNaN(slow) {
+		return NaN, NaN, NaN
+	}
+
+	dif := fast - slow
+	dea := s.signal.Next(dif)
+	return dif, dea, dif - dea
+}
+
+// MACD 指数平滑异同平均线，返回 DIF、DEA 与柱，三条线等长
+// DIF 在 max(fast, slow)-1 之前为 NaN，DEA 与柱再往后 signal-1 个
+func MACD(prices []float64, fast, slow, signal int) ([]float64, []float64, []float64) {
+	s := NewMACDStream(fast, slow, signal)
+	dif := make([]float64, len(prices))
+	dea := make([]float64, len(prices))
+	hist := make([]float64, len(prices))
+	for i, p := range prices {
+		dif[i], dea[i], hist[i] = s.Next(p)
+	}
+	return dif, dea, hist
+}
@@ -0,0 +1,62 @@
+package indicator
+
+import "math"
+
+// RSIStream 流式相对强弱指标，使用 Wilder 平滑
+type RSIStream struct {
+	period  int
+	prev    float64
+	started bool
+	count   int
+	gain    float64
+	loss    float64
+}
+
+func NewRSIStream(period int) *RSIStream {
+	return &RSIStream{period: clampPeriod(period)}
+}
+
+func (s *RSIStream) Next(price float64) float64 {
+	if !s.started {
+		s.prev, s.started = price, true
+		return NaN
+	}
+
+	change := price - s.prev
+	s.prev = price
+	up, down := math.Max(change, 0), math.Max(-change, 0)
+
+	s.count++
+	p := float64(s.period)
+	switch {
+	case s.count < s.period:
+		s.gain += up
+		s.loss += down
+		return NaN
+	case s.count == s.period:
+		s.gain = (s.gain + up) / p
+		s.loss = (s.loss + down) / p
+	default:
+		s.gain = (s.gain*(p-1) + up) / p
+		s.loss = (s.loss*(p-1) + down) / p
+	}
+
+	// 没有下跌时为 100，价格完全不变时为 50
+	if s.loss == 0 {
+		if s.gain == 0 {
+			return 50
+		}
+		return 100
+	}
+	return 100 - 100/(1+s.gain/s.loss)
+}
+
+// RSI 相对强弱指标，需要 period 个涨跌幅，前 period 个为 NaN
+func RSI(prices []float64, period int) []float64 {
+	s := NewRSIStream(period)
+	out := make([]float64, len(prices))
+	for i, p := range prices {
+		out[i] = s.Next(p)
+	}
+	return out
+}
@@ -0,0 +1,215 @@
+package indicator
+
+import (
+	"financia/util"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Spec 指标及参数，例如 macd:12:26:9
+type Spec struct {
+	Name   string
+	Params []float64
+}
+
+func (s *Spec) String() string {
+	parts := []string{s.Name}
+	for _, p := range s.Params {
+		parts = append(parts, strconv.FormatFloat(p, 'f', -1, 64))
+	}
+	return strings.Join(parts, ":")
+}
+
+// 参数上限，接口不需要登录，限制周期避免分配过大的窗口与向前取过多数据
+const (
+	MaxPeriod   = 250 // 约一年的交易日
+	maxBollMult = 10
+)
+
+// indicatorDefaults 各指标的默认参数，也用于校验参数个数
+var indicatorDefaults = map[string][]float64{
+	"ma":   {20},
+	"ema":  {20},
+	"wma":  {20},
+	"macd": {12, 26, 9},
+	"rsi":  {14},
+	"boll": {20, 2},
+	"kdj":  {9, 3, 3},
+	"atr":  {14},
+	"obv":  {},
+	"vwap": {0},
+}
+
+// ParseSpecs 解析以逗号分隔的指标列表，如 ma:20,ema:60,macd:12:26:9,rsi:14
+// 省略的参数使用默认值
+func ParseSpecs(str string) ([]*Spec, error) {
+	specs := make([]*Spec, 0)
+	for _, item := range strings.Split(str, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		parts := strings.Split(item, ":")
+		name := strings.ToLower(parts[0])
+		defaults, ok := indicatorDefaults[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown indicator %q", parts[0])
+		}
+		if len(parts)-1 > len(defaults) {
+			return nil, fmt.Errorf("indicator %s takes at most %d params", name, len(defaults))
+		}
+
+		params := append([]float64(nil), defaults...)
+		for i, p := range parts[1:] {
+			v, err := strconv.ParseFloat(p, 64)
+			if err != nil || v < 0 {
+				return nil, fmt.Errorf("invalid param %q for indicator %s", p, name)
+			}
+			params[i] = v
+		}
+		// 周期为不超过 MaxPeriod 的正整数，vwap 可以为 0 表示累计；boll 的倍数不超过 maxBollMult
+		for i, v := range params {
+			if name == "boll" && i == 1 {
+				if v > maxBollMult {
+					return nil, fmt.Errorf("invalid param %v for indicator %s", v, name)
+				}
+				continue
+			}
+			lower := 1.0
+			if name == "vwap" {
+				lower = 0
+			}
+			if v < lower || v > MaxPeriod || v != math.Trunc(v) {
+				return nil, fmt.Errorf("invalid param %v for indicator %s", v, name)
+			}
+		}
+
+		specs = append(specs, &Spec{Name: name, Params: params})
+	}
+
+	if len(specs) == 0 {
+		return nil, fmt.Errorf("no indicator specified")
+	}
+	return specs, nil
+}
+
+// Lookback 第一个有效值需要的 K 线数量，用于向前多取数据
+func (s *Spec) Lookback() int {
+	n := 1
+	for _, p := range s.Params {
+		n = max(n, int(p))
+	}
+	switch s.Name {
+	case "macd":
+		n = int(max(s.Params[0], s.Params[1]) + s.Params[2] - 1)
+	case "rsi":
+		n++
+	}
+	return n
+}
+
+// Series 一个指标的计算结果，每条线与输入 K 线对齐，预热期为 nil
+type Series struct {
+	Spec  string                `json:"spec"`
+	Lines map[string][]*float64 `json:"lines"`
+}
+
+// Compute 按日期升序的 K 线计算指标
+func Compute(bars []*util.Bar, specs []*Spec) []*Series {
+	n := len(bars)
+	high := make([]float64, n)
+	low := make([]float64, n)
+	closes := make([]float64, n)
+	vol := make([]float64, n)
+	for i, v := range bars {
+		high[i], low[i], closes[i], vol[i] = v.High, v.Low, v.Close, v.Vol
+	}
+
+	list := make([]*Series, 0, len(specs))
+	for _, spec := range specs {
+		p := spec.Params
+		lines := make(map[string][]float64)
+		switch spec.Name {
+		case "ma":
+			lines["ma"] = SMA(closes, int(p[0]))
+		case "ema":
+			lines["ema"] = EMA(closes, int(p[0]))
+		case "wma":
+			lines["wma"] = WMA(closes, int(p[0]))
+		case "macd":
+			lines["dif"], lines["dea"], lines["hist"] = MACD(closes, int(p[0]), int(p[1]), int(p[2]))
+		case "rsi":
+			lines["rsi"] = RSI(closes, int(p[0]))
+		case "boll":
+			lines["mid"], lines["upper"], lines["lower"] = BOLL(closes, int(p[0]), p[1])
+		case "kdj":
+			lines["k"], lines["d"], lines["j"] = KDJ(high, low, closes, int(p[0]), int(p[1]), int(p[2]))
+		case "atr":
+			lines["atr"] = ATR(high, low, closes, int(p[0]))
+		case "obv":
+			lines["obv"] = OBV(closes, vol)
+		case "vwap":
+			lines["vwap"] = VWAP(high, low, closes, vol, int(p[0]))
+		}
+
+		series := &Series{
+			Spec:  spec.String(),
+			Lines: make(map[string][]*float64, len(lines)),
+		}
+		for name, line := range lines {
+			series.Lines[name] = nullable(line)
+		}
+		list = append(list, series)
+	}
+
+	return list
+}
+
+// ComputeFrom 计算指标并只保留 start 及之后的部分，返回对应的交易日
+// bars 应包含 start 之前至少 Lookback 根 K 线，保证区间起点已有数值
+func ComputeFrom(bars []*util.Bar, specs []*Spec, start time.Time) ([]string, []*Series) {
+	skip := 0
+	for skip < len(bars) && bars[skip].Date.Before(start) {
+		skip++
+	}
+
+	dates := make([]string, 0, len(bars)-skip)
+	for _, v := range bars[skip:] {
+		dates = append(dates, v.Date.Format(time.DateOnly))
+	}
+
+	list := Compute(bars, specs)
+	for _, series := range list {
+		for name, line := range series.Lines {
+			series.Lines[name] = line[skip:]
+		}
+	}
+
+	return dates, list
+}
+
+// MaxLookback 多个指标中最大的 Lookback
+func MaxLookback(specs []*Spec) int {
+	n := 0
+	for _, spec := range specs {
+		n = max(n, spec.Lookback())
+	}
+	return n
+}
+
+// nullable NaN 转为 nil，便于序列化为 JSON null
+func nullable(line []float64) []*float64 {
+	out := make([]*float64, len(line))
+	for i := range line {
+		v := line[i]
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			continue
+		}
+		out[i] = &v
+	}
+	return out
+}
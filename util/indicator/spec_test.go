@@ -0,0 +1,68 @@
+package indicator
+
+import (
+	"financia/util"
+	"math"
+	"testing"
+	"time"
+)
+
+func Test_ParseSpecs(t *testing.T) {
+	specs, err := ParseSpecs("ma:20, ema:60,macd,rsi:14,boll:20:2.5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"ma:20", "ema:60", "macd:12:26:9", "rsi:14", "boll:20:2.5"}
+	for i, s := range specs {
+		if s.String() != want[i] {
+			t.Errorf("spec[%d] = %s, want %s", i, s, want[i])
+		}
+	}
+
+	for _, bad := range []string{"", "foo:1", "ma:0", "ma:1.5", "rsi:14:2", "ma:x",
+		"ma:251", "ma:100000000", "macd:12:26:1e9", "boll:20:11", "vwap:2.5", "vwap:1e19", "vwap:251"} {
+		if _, err := ParseSpecs(bad); err == nil {
+			t.Errorf("ParseSpecs(%q) expected error", bad)
+		}
+	}
+
+	// 上限与 vwap 的累计模式
+	specs, err = ParseSpecs("ma:250,vwap:0,vwap:250,boll:250:10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := MaxLookback(specs); n != MaxPeriod {
+		t.Errorf("MaxLookback = %d, want %d", n, MaxPeriod)
+	}
+}
+
+func Test_ComputeFrom(t *testing.T) {
+	start, _ := time.Parse(time.DateOnly, "2024-01-01")
+	bars := make([]*util.Bar, 0, 10)
+	for i := 0; i < 10; i++ {
+		p := float64(i + 1)
+		bars = append(bars, &util.Bar{Date: start.AddDate(0, 0, i), Open: p, High: p + 1, Low: p - 1, Close: p, Vol: 100})
+	}
+
+	specs, _ := ParseSpecs("ma:3,obv,boll:5:2")
+	dates, list := ComputeFrom(bars, specs, start.AddDate(0, 0, 2))
+	if len(dates) != 8 || dates[0] != "2024-01-03" {
+		t.Fatalf("dates = %v", dates)
+	}
+
+	ma := list[0].Lines["ma"]
+	if len(ma) != 8 || ma[0] == nil || *ma[0] != 2 || *ma[7] != 9 {
+		t.Errorf("ma = %v", ma)
+	}
+	if obv := list[1].Lines["obv"]; *obv[7] != 900 {
+		t.Errorf("obv[7] = %v, want 900", *obv[7])
+	}
+	// boll:5 在前 4 根 K 线没有数值
+	mid, upper := list[2].Lines["mid"], list[2].Lines["upper"]
+	if mid[1] != nil || mid[2] == nil || *mid[2] != 3 {
+		t.Errorf("boll mid = %v", mid)
+	}
+	if math.Abs(*upper[2]-(3+2*math.Sqrt2)) > 1e-9 {
+		t.Errorf("boll upper = %v", *upper[2])
+	}
+}
@@ -0,0 +1,82 @@
+package indicator
+
+// OBVStream 流式能量潮，第一根 K 线为 0
+type OBVStream struct {
+	prev    float64
+	started bool
+	value   float64
+}
+
+func NewOBVStream() *OBVStream {
+	return &OBVStream{}
+}
+
+func (s *OBVStream) Next(close, vol float64) float64 {
+	if s.started {
+		switch {
+		case close > s.prev:
+			s.value += vol
+		case close < s.prev:
+			s.value -= vol
+		}
+	}
+	s.prev, s.started = close, true
+	return s.value
+}
+
+// OBV 能量潮，没有预热期
+func OBV(close, vol []float64) []float64 {
+	s := NewOBVStream()
+	out := make([]float64, len(close))
+	for i := range close {
+		out[i] = s.Next(close[i], vol[i])
+	}
+	return out
+}
+
+// VWAPStream 流式成交量加权平均价，使用典型价格 (H+L+C)/3
+// period 为 0 时从第一根 K 线开始累计，否则按 period 滚动
+type VWAPStream struct {
+	pvs, vols *ring
+	pv, vol   float64
+}
+
+func NewVWAPStream(period int) *VWAPStream {
+	s := &VWAPStream{}
+	if period > 0 {
+		s.pvs, s.vols = newRing(period), newRing(period)
+	}
+	return s
+}
+
+func (s *VWAPStream) Next(high, low, close, vol float64) float64 {
+	pv := (high + low + close) / 3 * vol
+	s.pv += pv
+	s.vol += vol
+	if s.pvs != nil {
+		if old, evicted := s.pvs.push(pv); evicted {
+			s.pv -= old
+		}
+		if old, evicted := s.vols.push(vol); evicted {
+			s.vol -= old
+		}
+		if !s.pvs.full {
+			return NaN
+		}
+	}
+
+	if s.vol == 0 {
+		return NaN
+	}
+	return s.pv / s.vol
+}
+
+// VWAP 成交量加权平均价，滚动模式前 period-1 个为 NaN，成交量为 0 时为 NaN
+func VWAP(high, low, close, vol []float64, period int) []float64 {
+	s := NewVWAPStream(period)
+	out := make([]float64, len(close))
+	for i := range close {
+		out[i] = s.Next(high[i], low[i], close[i], vol[i])
+	}
+	return out
+}
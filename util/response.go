@@ -33,6 +33,10 @@ var errMsg = map[int]string{
 	ShouldBindJSONError: "参数错误",
 	ReqDataError:        "参数内容错误",
 	CodeLimitError:      "验证码发送过于频繁",
+	TuShareLimitError:   "数据源访问受限，请稍后再试",
+	DataEmptyError:      "暂无数据",
+	QuotaExceededError:  "今日 AI 分析次数已用完，请明天再试",
+	BusyError:           "任务过多，请稍后再试",
 }
 
 const (
@@ -44,4 +48,8 @@ const (
 	ShouldBindJSONError
 	ReqDataError
 	CodeLimitError
+	TuShareLimitError
+	DataEmptyError
+	QuotaExceededError
+	BusyError
 )